@@ -1,12 +1,17 @@
 package main
 
 import (
+	"context"
 	"fmt"
 	"os"
 	"path/filepath"
+	"sort"
+	"strings"
+	"time"
 
 	"github.com/operator-framework/operator-lifecycle-manager/olmv0-mcp-server/pkg/client"
 	"github.com/operator-framework/operator-lifecycle-manager/olmv0-mcp-server/pkg/server"
+	"github.com/operator-framework/operator-lifecycle-manager/olmv0-mcp-server/pkg/tools"
 	"github.com/operator-framework/operator-lifecycle-manager/olmv0-mcp-server/pkg/types"
 	"github.com/sirupsen/logrus"
 	"github.com/spf13/cobra"
@@ -21,6 +26,12 @@ var (
 	readOnly   bool
 	toolsets   []string
 	stdio      bool
+	fromDir    string
+	catalogDir string
+
+	gatherNamespace string
+	gatherOutputDir string
+	gatherLogTail   int64
 )
 
 func main() {
@@ -43,11 +54,29 @@ on OLM v0 resources and operations.`,
 	}
 
 	rootCmd.Flags().IntVarP(&port, "port", "p", 8080, "HTTP/SSE server port (ignored when --stdio is used)")
-	rootCmd.Flags().StringVar(&kubeconfig, "kubeconfig", "", "Path to kubeconfig file (default: $HOME/.kube/config)")
+	rootCmd.PersistentFlags().StringVar(&kubeconfig, "kubeconfig", "", "Path to kubeconfig file (default: $HOME/.kube/config)")
 	rootCmd.Flags().BoolVar(&readOnly, "read-only", true, "Prevent write operations (default: true)")
-	rootCmd.Flags().StringSliceVar(&toolsets, "toolsets", []string{"csv", "subscription", "catalog", "installplan"}, "Enable specific toolsets")
+	rootCmd.Flags().StringSliceVar(&toolsets, "toolsets", []string{"csv", "subscription", "catalog", "installplan", "diagnostics"}, "Enable specific toolsets")
+	rootCmd.Flags().StringVar(&fromDir, "from-dir", "", "Serve tools from a directory of YAML/JSON manifests (a must-gather or collect_olm_state dump) instead of a live cluster; implies --read-only")
+	rootCmd.Flags().StringVar(&catalogDir, "catalog-dir", "", "Root directory of the File-Based Catalogs the catalog tools may read; with --toolsets catalog and no --from-dir, the server serves only these catalogs and does not connect to a cluster")
 	rootCmd.Flags().BoolVar(&stdio, "stdio", true, "Use stdio transport for MCP (default: true, use --stdio=false for HTTP)")
 
+	var gatherCmd = &cobra.Command{
+		Use:   "gather",
+		Short: "Collect OLM state into a tarball for support tickets",
+		Long: `Collect every OLM object (CSVs, Subscriptions, InstallPlans, CatalogSources,
+OperatorGroups, OperatorConditions), the Deployments and Pods of OLM and
+installed operators, registry Services, OLM-generated RBAC, bundle unpack Jobs,
+related events, OLM component logs and catalog pod status into a timestamped
+olm-state-<timestamp>-<random>.tar.gz.`,
+		Args: cobra.NoArgs,
+		Run:  runGather,
+	}
+	gatherCmd.Flags().StringVarP(&gatherNamespace, "namespace", "n", "", "Only collect objects in this namespace (default: all namespaces)")
+	gatherCmd.Flags().StringVarP(&gatherOutputDir, "output-dir", "o", ".", "Directory to write the tarball to")
+	gatherCmd.Flags().Int64Var(&gatherLogTail, "log-tail", 5000, "Number of log lines to keep per OLM container")
+	rootCmd.AddCommand(gatherCmd)
+
 	if err := rootCmd.Execute(); err != nil {
 		logrus.Fatal(err)
 	}
@@ -56,6 +85,73 @@ on OLM v0 resources and operations.`,
 func runServer(cmd *cobra.Command, args []string) {
 	logrus.Info("Starting OLM v0 MCP Server")
 
+	if err := validateToolsets(toolsets); err != nil {
+		logrus.Fatalf("Invalid --toolsets: %v", err)
+	}
+
+	mcpServer := newMCPServer()
+
+	if stdio {
+		logrus.Info("Starting MCP server with stdio transport")
+		stdioServer := server.NewMCPStdioServer(mcpServer)
+		if err := stdioServer.Start(); err != nil {
+			logrus.Fatalf("Error starting stdio server: %v", err)
+		}
+	} else {
+		logrus.Info("Starting MCP server with HTTP transport")
+		if err := server.StartServer(mcpServer); err != nil {
+			logrus.Fatalf("Error starting HTTP server: %v", err)
+		}
+	}
+}
+
+func runGather(cmd *cobra.Command, args []string) {
+	mcpServer := newMCPServer()
+
+	report, err := tools.GatherOLMState(context.Background(), mcpServer, tools.GatherOptions{
+		Namespace:    gatherNamespace,
+		OutputDir:    gatherOutputDir,
+		LogTailLines: gatherLogTail,
+	})
+	if err != nil {
+		logrus.Fatalf("Error collecting OLM state: %v", err)
+	}
+	for _, e := range report.Errors {
+		logrus.Warnf("Collection error: %s", e)
+	}
+	logrus.Infof("OLM state written to %s (%d files)", report.Path, len(report.Files))
+}
+
+// validateToolsets rejects toolset names that are not in
+// types.DefaultToolsets, which would otherwise enable no tools.
+func validateToolsets(names []string) error {
+	var unknown []string
+	for _, name := range names {
+		if _, ok := types.DefaultToolsets[name]; !ok {
+			unknown = append(unknown, name)
+		}
+	}
+	if len(unknown) == 0 {
+		return nil
+	}
+
+	known := make([]string, 0, len(types.DefaultToolsets))
+	for name := range types.DefaultToolsets {
+		known = append(known, name)
+	}
+	sort.Strings(known)
+	return fmt.Errorf("unknown toolsets %s (valid toolsets: %s)", strings.Join(unknown, ", "), strings.Join(known, ", "))
+}
+
+func newMCPServer() *types.MCPServer {
+	if fromDir != "" {
+		return newOfflineMCPServer(fromDir)
+	}
+
+	if isCatalogOnly() {
+		return newCatalogOnlyMCPServer()
+	}
+
 	config, err := getKubeConfig(kubeconfig)
 	if err != nil {
 		logrus.Fatalf("Error getting kubeconfig: %v", err)
@@ -71,7 +167,7 @@ func runServer(cmd *cobra.Command, args []string) {
 		logrus.Fatalf("Error creating OLM client: %v", err)
 	}
 
-	mcpServer := &types.MCPServer{
+	return &types.MCPServer{
 		Config:     config,
 		K8sClient:  k8sClient,
 		OLMClient:  olmClient,
@@ -79,19 +175,56 @@ func runServer(cmd *cobra.Command, args []string) {
 		ReadOnly:   readOnly,
 		Kubeconfig: kubeconfig,
 		Toolsets:   toolsets,
+		CatalogDir: catalogDir,
 	}
+}
 
-	if stdio {
-		logrus.Info("Starting MCP server with stdio transport")
-		stdioServer := server.NewMCPStdioServer(mcpServer)
-		if err := stdioServer.Start(); err != nil {
-			logrus.Fatalf("Error starting stdio server: %v", err)
-		}
-	} else {
-		logrus.Info("Starting MCP server with HTTP transport")
-		if err := server.StartServer(mcpServer); err != nil {
-			logrus.Fatalf("Error starting HTTP server: %v", err)
-		}
+// newOfflineMCPServer serves captured cluster state from dir. Nothing can be
+// written, so the server is always read-only.
+func newOfflineMCPServer(dir string) *types.MCPServer {
+	logrus.Infof("Serving OLM state from %s", dir)
+
+	olmClient, k8sClient, err := client.NewOfflineClients(dir)
+	if err != nil {
+		logrus.Fatalf("Error reading %s: %v", dir, err)
+	}
+	for _, skipped := range olmClient.Skipped {
+		logrus.Warnf("Skipped %s", skipped)
+	}
+	logrus.Infof("State captured at about %s", olmClient.CapturedAt.UTC().Format(time.RFC3339))
+
+	return &types.MCPServer{
+		K8sClient:  k8sClient,
+		OLMClient:  olmClient,
+		Port:       port,
+		ReadOnly:   true,
+		Toolsets:   toolsets,
+		CatalogDir: catalogDir,
+		CapturedAt: olmClient.CapturedAt,
+	}
+}
+
+// isCatalogOnly reports whether only File-Based Catalogs were asked for:
+// --toolsets catalog with a --catalog-dir and no --from-dir.
+func isCatalogOnly() bool {
+	return catalogDir != "" && fromDir == "" && len(toolsets) == 1 && toolsets[0] == "catalog"
+}
+
+// newCatalogOnlyMCPServer serves File-Based Catalogs without connecting to a
+// cluster. Only the tools that read catalogDir are enabled.
+func newCatalogOnlyMCPServer() *types.MCPServer {
+	logrus.Infof("Serving File-Based Catalogs from %s without a cluster", catalogDir)
+
+	olmClient, k8sClient := client.NewEmptyOfflineClients()
+
+	return &types.MCPServer{
+		K8sClient:   k8sClient,
+		OLMClient:   olmClient,
+		Port:        port,
+		ReadOnly:    true,
+		Toolsets:    toolsets,
+		CatalogDir:  catalogDir,
+		CatalogOnly: true,
 	}
 }
 
@@ -0,0 +1,647 @@
+package client
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+	"net/http"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+
+	operatorsv1 "github.com/operator-framework/api/pkg/operators/v1"
+	"github.com/operator-framework/api/pkg/operators/v1alpha1"
+	operatorsv2 "github.com/operator-framework/api/pkg/operators/v2"
+	"github.com/operator-framework/operator-lifecycle-manager/olmv0-mcp-server/pkg/types"
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/fields"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	utilyaml "k8s.io/apimachinery/pkg/util/yaml"
+	"k8s.io/client-go/kubernetes"
+	"k8s.io/client-go/kubernetes/fake"
+	"k8s.io/client-go/kubernetes/scheme"
+	corev1client "k8s.io/client-go/kubernetes/typed/core/v1"
+	"k8s.io/client-go/rest"
+	fakerest "k8s.io/client-go/rest/fake"
+	k8stesting "k8s.io/client-go/testing"
+)
+
+// errOffline is returned by every write operation of the OfflineClient.
+var errOffline = errors.New("cluster state is read from a directory; write operations are not available offline")
+
+// NotCapturedError is returned for a resource the directory holds no objects
+// of. The directory says nothing about such resources, so tools must report
+// them as unavailable rather than as absent from the cluster.
+type NotCapturedError struct {
+	Resource schema.GroupResource
+	Dir      string
+}
+
+func (e *NotCapturedError) Error() string {
+	if e.Dir == "" {
+		return fmt.Sprintf("%s not captured: no cluster state is loaded", e.Resource)
+	}
+	return fmt.Sprintf("%s not captured in %s", e.Resource, e.Dir)
+}
+
+// OfflineClient serves OLM objects read from a directory of YAML or JSON
+// manifests, such as a must-gather, a `kubectl get -o yaml` dump or an
+// extracted collect_olm_state tarball, instead of a live API server.
+type OfflineClient struct {
+	dir string
+	// captured holds the resources the directory has objects of.
+	captured map[schema.GroupResource]bool
+
+	csvs               []v1alpha1.ClusterServiceVersion
+	subscriptions      []v1alpha1.Subscription
+	catalogs           []v1alpha1.CatalogSource
+	installPlans       []v1alpha1.InstallPlan
+	packages           []types.PackageManifest
+	operatorGroups     []operatorsv1.OperatorGroup
+	operatorConditions []operatorsv2.OperatorCondition
+
+	// CapturedAt approximates when the directory was captured: the newest
+	// timestamp in its objects, or the directory's modification time if
+	// they have none.
+	CapturedAt time.Time
+	// Skipped lists the files that could not be decoded.
+	Skipped []string
+}
+
+// NewOfflineClients reads every manifest under dir and returns an OLM client
+// for the OLM objects and a Kubernetes client for the rest. The Kubernetes
+// client serves pods, deployments, events, ConfigMaps, RBAC and the other
+// built-in kinds found in the directory, and pod logs from
+// logs/<namespace>/<pod>/<container>[.previous].log files or the must-gather
+// namespaces/<namespace>/pods/<pod>/<container>/<container>/logs layout.
+// Objects found more than once, for example in a list and in a per-object
+// file, are kept once.
+// Resources the directory holds no objects of fail with a NotCapturedError
+// rather than being served as empty.
+func NewOfflineClients(dir string) (*OfflineClient, kubernetes.Interface, error) {
+	info, err := os.Stat(dir)
+	if err != nil {
+		return nil, nil, err
+	}
+	if !info.IsDir() {
+		return nil, nil, fmt.Errorf("%s is not a directory", dir)
+	}
+
+	loader := &offlineLoader{objects: map[string]interface{}{}, logs: map[string]string{}, captured: map[schema.GroupResource]bool{}}
+	err = filepath.WalkDir(dir, func(path string, entry fs.DirEntry, err error) error {
+		if err != nil || entry.IsDir() {
+			return err
+		}
+		rel, err := filepath.Rel(dir, path)
+		if err != nil {
+			return err
+		}
+		return loader.loadFile(path, filepath.ToSlash(rel))
+	})
+	if err != nil {
+		return nil, nil, err
+	}
+
+	client := &OfflineClient{dir: dir, captured: loader.captured, CapturedAt: loader.newest, Skipped: loader.skipped}
+	if client.CapturedAt.IsZero() {
+		client.CapturedAt = info.ModTime()
+	}
+	var kubeObjects []runtime.Object
+	keys := make([]string, 0, len(loader.objects))
+	for key := range loader.objects {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	for _, key := range keys {
+		switch obj := loader.objects[key].(type) {
+		case *v1alpha1.ClusterServiceVersion:
+			client.csvs = append(client.csvs, *obj)
+		case *v1alpha1.Subscription:
+			client.subscriptions = append(client.subscriptions, *obj)
+		case *v1alpha1.CatalogSource:
+			client.catalogs = append(client.catalogs, *obj)
+		case *v1alpha1.InstallPlan:
+			client.installPlans = append(client.installPlans, *obj)
+		case *types.PackageManifest:
+			client.packages = append(client.packages, *obj)
+		case *operatorsv1.OperatorGroup:
+			client.operatorGroups = append(client.operatorGroups, *obj)
+		case *operatorsv2.OperatorCondition:
+			client.operatorConditions = append(client.operatorConditions, *obj)
+		case runtime.Object:
+			kubeObjects = append(kubeObjects, obj)
+		}
+	}
+
+	return client, newOfflineKubeClient(dir, kubeObjects, loader.captured, loader.logs), nil
+}
+
+// NewEmptyOfflineClients returns offline clients with no cluster state, for
+// serving only the tools that read local files, such as File-Based Catalogs.
+func NewEmptyOfflineClients() (*OfflineClient, kubernetes.Interface) {
+	return &OfflineClient{}, newOfflineKubeClient("", nil, nil, map[string]string{})
+}
+
+// offlineLoader collects typed objects keyed by group, kind, namespace and
+// name, and pod logs keyed by namespace/pod/container.
+type offlineLoader struct {
+	objects  map[string]interface{}
+	logs     map[string]string
+	captured map[schema.GroupResource]bool
+	newest   time.Time
+	skipped  []string
+}
+
+func (l *offlineLoader) loadFile(path, rel string) error {
+	if strings.HasSuffix(rel, ".log") {
+		if key := podLogKey(rel); key != "" {
+			data, err := os.ReadFile(path)
+			if err != nil {
+				return err
+			}
+			l.logs[key] = string(data)
+		}
+		return nil
+	}
+	switch strings.ToLower(filepath.Ext(rel)) {
+	case ".yaml", ".yml", ".json":
+	default:
+		return nil
+	}
+
+	file, err := os.Open(path)
+	if err != nil {
+		return err
+	}
+	defer file.Close()
+
+	decoder := utilyaml.NewYAMLOrJSONDecoder(file, 4096)
+	for {
+		var object map[string]interface{}
+		if err := decoder.Decode(&object); err != nil {
+			if err != io.EOF {
+				l.skipped = append(l.skipped, fmt.Sprintf("%s: %v", rel, err))
+			}
+			return nil
+		}
+		if object == nil {
+			continue
+		}
+		u := &unstructured.Unstructured{Object: object}
+		if u.IsList() {
+			_ = u.EachListItem(func(item runtime.Object) error {
+				l.add(item.(*unstructured.Unstructured))
+				return nil
+			})
+			continue
+		}
+		l.add(u)
+	}
+}
+
+// podLogKey maps a log file path to namespace/pod/container, with a
+// "/previous" suffix for the previous container's log, or "" if the path is
+// not in a known layout.
+func podLogKey(rel string) string {
+	parts := strings.Split(rel, "/")
+	n := len(parts)
+	// logs/<namespace>/<pod>/<container>[.previous].log, as written by
+	// collect_olm_state.
+	if n >= 4 && parts[n-4] == "logs" {
+		container := strings.TrimSuffix(parts[n-1], ".log")
+		if previous := strings.TrimSuffix(container, ".previous"); previous != container {
+			return strings.Join([]string{parts[n-3], parts[n-2], previous, "previous"}, "/")
+		}
+		return strings.Join([]string{parts[n-3], parts[n-2], container}, "/")
+	}
+	// namespaces/<namespace>/pods/<pod>/<container>/<container>/logs/{current,previous}.log,
+	// as written by must-gather.
+	if n >= 8 && parts[n-8] == "namespaces" && parts[n-6] == "pods" && parts[n-2] == "logs" {
+		key := strings.Join([]string{parts[n-7], parts[n-5], parts[n-4]}, "/")
+		switch parts[n-1] {
+		case "current.log":
+			return key
+		case "previous.log":
+			return key + "/previous"
+		}
+	}
+	return ""
+}
+
+// add decodes an object into its typed form. Kinds the tools never read,
+// such as CRDs of other projects, are ignored.
+func (l *offlineLoader) add(u *unstructured.Unstructured) {
+	gvk := u.GroupVersionKind()
+	if gvk.Kind == "" || u.GetName() == "" {
+		return
+	}
+
+	var obj interface{}
+	switch gvk.Group {
+	case v1alpha1.GroupName:
+		switch gvk.Kind {
+		case v1alpha1.ClusterServiceVersionKind:
+			obj = &v1alpha1.ClusterServiceVersion{}
+		case v1alpha1.SubscriptionKind:
+			obj = &v1alpha1.Subscription{}
+		case v1alpha1.CatalogSourceKind:
+			obj = &v1alpha1.CatalogSource{}
+		case v1alpha1.InstallPlanKind:
+			obj = &v1alpha1.InstallPlan{}
+		case operatorsv1.OperatorGroupKind:
+			obj = &operatorsv1.OperatorGroup{}
+		case "OperatorCondition":
+			obj = &operatorsv2.OperatorCondition{}
+		default:
+			return
+		}
+	case "packages.operators.coreos.com":
+		if gvk.Kind != "PackageManifest" {
+			return
+		}
+		// PackageManifests are decoded as plain JSON, as with the live client.
+		data, err := json.Marshal(u.Object)
+		if err != nil {
+			return
+		}
+		pkg := &types.PackageManifest{}
+		if json.Unmarshal(data, pkg) != nil {
+			return
+		}
+		l.objects[objectKey(gvk, u)] = pkg
+		l.capture(gvk)
+		return
+	case "events.k8s.io":
+		// The same events are served from core/v1, which is where dumps
+		// usually capture them.
+		return
+	default:
+		typed, err := scheme.Scheme.New(gvk)
+		if err != nil {
+			return
+		}
+		obj = typed
+	}
+
+	if err := runtime.DefaultUnstructuredConverter.FromUnstructured(u.Object, obj); err != nil {
+		l.skipped = append(l.skipped, fmt.Sprintf("%s %s/%s: %v", gvk.Kind, u.GetNamespace(), u.GetName(), err))
+		return
+	}
+	l.objects[objectKey(gvk, u)] = obj
+	l.capture(gvk)
+	l.observeTimes(u)
+}
+
+// observeTimes tracks the newest timestamp seen: creation times, event
+// times and the timestamps in status, such as condition transitions.
+func (l *offlineLoader) observeTimes(u *unstructured.Unstructured) {
+	observe := func(value interface{}) {
+		raw, ok := value.(string)
+		if !ok {
+			return
+		}
+		if t, err := time.Parse(time.RFC3339Nano, raw); err == nil && t.After(l.newest) {
+			l.newest = t
+		}
+	}
+	var walk func(value interface{})
+	walk = func(value interface{}) {
+		switch v := value.(type) {
+		case map[string]interface{}:
+			for key, field := range v {
+				if strings.HasSuffix(key, "Time") || strings.HasSuffix(key, "Timestamp") {
+					observe(field)
+				}
+				walk(field)
+			}
+		case []interface{}:
+			for _, item := range v {
+				walk(item)
+			}
+		}
+	}
+	if metadata, ok := u.Object["metadata"].(map[string]interface{}); ok {
+		observe(metadata["creationTimestamp"])
+	}
+	for _, field := range []string{"firstTimestamp", "lastTimestamp", "eventTime"} {
+		observe(u.Object[field])
+	}
+	walk(u.Object["status"])
+}
+
+func (l *offlineLoader) capture(gvk schema.GroupVersionKind) {
+	resource, _ := meta.UnsafeGuessKindToResource(gvk)
+	l.captured[resource.GroupResource()] = true
+}
+
+func objectKey(gvk schema.GroupVersionKind, u *unstructured.Unstructured) string {
+	return strings.Join([]string{gvk.Group, gvk.Kind, u.GetNamespace(), u.GetName()}, "/")
+}
+
+func inNamespace(meta metav1.ObjectMeta, namespace string) bool {
+	return namespace == "" || meta.Namespace == namespace
+}
+
+func notFound(resource, name string) error {
+	return apierrors.NewNotFound(v1alpha1.Resource(resource), name)
+}
+
+// notCaptured returns a NotCapturedError if the directory holds no objects
+// of resource.
+func (c *OfflineClient) notCaptured(resource schema.GroupResource) error {
+	if c.captured[resource] {
+		return nil
+	}
+	return &NotCapturedError{Resource: resource, Dir: c.dir}
+}
+
+func (c *OfflineClient) ListClusterServiceVersions(ctx context.Context, namespace string) (*v1alpha1.ClusterServiceVersionList, error) {
+	if err := c.notCaptured(v1alpha1.Resource("clusterserviceversions")); err != nil {
+		return nil, err
+	}
+	result := &v1alpha1.ClusterServiceVersionList{}
+	for i := range c.csvs {
+		if inNamespace(c.csvs[i].ObjectMeta, namespace) {
+			result.Items = append(result.Items, *c.csvs[i].DeepCopy())
+		}
+	}
+	return result, nil
+}
+
+func (c *OfflineClient) GetClusterServiceVersion(ctx context.Context, namespace, name string) (*v1alpha1.ClusterServiceVersion, error) {
+	if err := c.notCaptured(v1alpha1.Resource("clusterserviceversions")); err != nil {
+		return nil, err
+	}
+	for i := range c.csvs {
+		if c.csvs[i].Namespace == namespace && c.csvs[i].Name == name {
+			return c.csvs[i].DeepCopy(), nil
+		}
+	}
+	return nil, notFound("clusterserviceversions", name)
+}
+
+func (c *OfflineClient) ListSubscriptions(ctx context.Context, namespace string) (*v1alpha1.SubscriptionList, error) {
+	if err := c.notCaptured(v1alpha1.Resource("subscriptions")); err != nil {
+		return nil, err
+	}
+	result := &v1alpha1.SubscriptionList{}
+	for i := range c.subscriptions {
+		if inNamespace(c.subscriptions[i].ObjectMeta, namespace) {
+			result.Items = append(result.Items, *c.subscriptions[i].DeepCopy())
+		}
+	}
+	return result, nil
+}
+
+func (c *OfflineClient) GetSubscription(ctx context.Context, namespace, name string) (*v1alpha1.Subscription, error) {
+	if err := c.notCaptured(v1alpha1.Resource("subscriptions")); err != nil {
+		return nil, err
+	}
+	for i := range c.subscriptions {
+		if c.subscriptions[i].Namespace == namespace && c.subscriptions[i].Name == name {
+			return c.subscriptions[i].DeepCopy(), nil
+		}
+	}
+	return nil, notFound("subscriptions", name)
+}
+
+func (c *OfflineClient) ListCatalogSources(ctx context.Context, namespace string) (*v1alpha1.CatalogSourceList, error) {
+	if err := c.notCaptured(v1alpha1.Resource("catalogsources")); err != nil {
+		return nil, err
+	}
+	result := &v1alpha1.CatalogSourceList{}
+	for i := range c.catalogs {
+		if inNamespace(c.catalogs[i].ObjectMeta, namespace) {
+			result.Items = append(result.Items, *c.catalogs[i].DeepCopy())
+		}
+	}
+	return result, nil
+}
+
+func (c *OfflineClient) GetCatalogSource(ctx context.Context, namespace, name string) (*v1alpha1.CatalogSource, error) {
+	if err := c.notCaptured(v1alpha1.Resource("catalogsources")); err != nil {
+		return nil, err
+	}
+	for i := range c.catalogs {
+		if c.catalogs[i].Namespace == namespace && c.catalogs[i].Name == name {
+			return c.catalogs[i].DeepCopy(), nil
+		}
+	}
+	return nil, notFound("catalogsources", name)
+}
+
+func (c *OfflineClient) ListInstallPlans(ctx context.Context, namespace string) (*v1alpha1.InstallPlanList, error) {
+	if err := c.notCaptured(v1alpha1.Resource("installplans")); err != nil {
+		return nil, err
+	}
+	result := &v1alpha1.InstallPlanList{}
+	for i := range c.installPlans {
+		if inNamespace(c.installPlans[i].ObjectMeta, namespace) {
+			result.Items = append(result.Items, *c.installPlans[i].DeepCopy())
+		}
+	}
+	return result, nil
+}
+
+func (c *OfflineClient) GetInstallPlan(ctx context.Context, namespace, name string) (*v1alpha1.InstallPlan, error) {
+	if err := c.notCaptured(v1alpha1.Resource("installplans")); err != nil {
+		return nil, err
+	}
+	for i := range c.installPlans {
+		if c.installPlans[i].Namespace == namespace && c.installPlans[i].Name == name {
+			return c.installPlans[i].DeepCopy(), nil
+		}
+	}
+	return nil, notFound("installplans", name)
+}
+
+func (c *OfflineClient) ListPackageManifests(ctx context.Context, namespace string) (*types.PackageManifestList, error) {
+	if err := c.notCaptured(schema.GroupResource{Group: "packages.operators.coreos.com", Resource: "packagemanifests"}); err != nil {
+		return nil, err
+	}
+	result := &types.PackageManifestList{}
+	for _, pkg := range c.packages {
+		if inNamespace(pkg.ObjectMeta, namespace) {
+			result.Items = append(result.Items, pkg)
+		}
+	}
+	return result, nil
+}
+
+func (c *OfflineClient) ListOperatorGroups(ctx context.Context, namespace string) (*operatorsv1.OperatorGroupList, error) {
+	if err := c.notCaptured(operatorsv1.Resource("operatorgroups")); err != nil {
+		return nil, err
+	}
+	result := &operatorsv1.OperatorGroupList{}
+	for i := range c.operatorGroups {
+		if inNamespace(c.operatorGroups[i].ObjectMeta, namespace) {
+			result.Items = append(result.Items, *c.operatorGroups[i].DeepCopy())
+		}
+	}
+	return result, nil
+}
+
+func (c *OfflineClient) ListOperatorConditions(ctx context.Context, namespace string) (*operatorsv2.OperatorConditionList, error) {
+	if err := c.notCaptured(operatorsv2.Resource("operatorconditions")); err != nil {
+		return nil, err
+	}
+	result := &operatorsv2.OperatorConditionList{}
+	for i := range c.operatorConditions {
+		if inNamespace(c.operatorConditions[i].ObjectMeta, namespace) {
+			result.Items = append(result.Items, *c.operatorConditions[i].DeepCopy())
+		}
+	}
+	return result, nil
+}
+
+func (c *OfflineClient) CreateSubscription(ctx context.Context, subscription *v1alpha1.Subscription, dryRun bool) (*v1alpha1.Subscription, error) {
+	return nil, errOffline
+}
+
+func (c *OfflineClient) UpdateSubscription(ctx context.Context, subscription *v1alpha1.Subscription, dryRun bool) (*v1alpha1.Subscription, error) {
+	return nil, errOffline
+}
+
+func (c *OfflineClient) DeleteSubscription(ctx context.Context, namespace, name string, dryRun bool) error {
+	return errOffline
+}
+
+func (c *OfflineClient) UpdateInstallPlan(ctx context.Context, installPlan *v1alpha1.InstallPlan, dryRun bool) (*v1alpha1.InstallPlan, error) {
+	return nil, errOffline
+}
+
+func (c *OfflineClient) DeleteClusterServiceVersion(ctx context.Context, namespace, name string, dryRun bool) error {
+	return errOffline
+}
+
+func (c *OfflineClient) DeleteInstallPlan(ctx context.Context, namespace, name string, dryRun bool) error {
+	return errOffline
+}
+
+func (c *OfflineClient) DeleteOperatorGroup(ctx context.Context, namespace, name string, dryRun bool) error {
+	return errOffline
+}
+
+// offlineKubeClient is a fake clientset over the captured objects that also
+// serves captured pod logs and honours event field selectors, which the fake
+// clientset ignores.
+type offlineKubeClient struct {
+	*fake.Clientset
+	logs map[string]string
+}
+
+// newOfflineKubeClient serves objects from a fake clientset. Requests for
+// resources that are not captured fail with a NotCapturedError instead of
+// returning nothing.
+func newOfflineKubeClient(dir string, objects []runtime.Object, captured map[schema.GroupResource]bool, logs map[string]string) *offlineKubeClient {
+	clientset := fake.NewClientset(objects...)
+	clientset.PrependReactor("*", "*", func(action k8stesting.Action) (bool, runtime.Object, error) {
+		resource := action.GetResource().GroupResource()
+		if captured[resource] {
+			return false, nil, nil
+		}
+		return true, nil, &NotCapturedError{Resource: resource, Dir: dir}
+	})
+	return &offlineKubeClient{Clientset: clientset, logs: logs}
+}
+
+func (c *offlineKubeClient) CoreV1() corev1client.CoreV1Interface {
+	return &offlineCoreV1{CoreV1Interface: c.Clientset.CoreV1(), logs: c.logs}
+}
+
+type offlineCoreV1 struct {
+	corev1client.CoreV1Interface
+	logs map[string]string
+}
+
+func (c *offlineCoreV1) Pods(namespace string) corev1client.PodInterface {
+	return &offlinePods{PodInterface: c.CoreV1Interface.Pods(namespace), namespace: namespace, logs: c.logs}
+}
+
+func (c *offlineCoreV1) Events(namespace string) corev1client.EventInterface {
+	return &offlineEvents{EventInterface: c.CoreV1Interface.Events(namespace)}
+}
+
+type offlinePods struct {
+	corev1client.PodInterface
+	namespace string
+	logs      map[string]string
+}
+
+// GetLogs serves a captured log, trimmed to the requested tail.
+func (p *offlinePods) GetLogs(name string, opts *corev1.PodLogOptions) *rest.Request {
+	key := p.namespace + "/" + name + "/" + opts.Container
+	if opts.Previous {
+		key += "/previous"
+	}
+	log, found := p.logs[key]
+
+	client := &fakerest.RESTClient{
+		Client: fakerest.CreateHTTPClient(func(*http.Request) (*http.Response, error) {
+			if !found {
+				return &http.Response{
+					StatusCode: http.StatusNotFound,
+					Body:       io.NopCloser(strings.NewReader(fmt.Sprintf("no captured log for container %s of pod %s/%s", opts.Container, p.namespace, name))),
+				}, nil
+			}
+			body := log
+			if opts.TailLines != nil {
+				lines := strings.SplitAfter(strings.TrimSuffix(log, "\n"), "\n")
+				if tail := int(*opts.TailLines); tail < len(lines) {
+					body = strings.Join(lines[len(lines)-tail:], "")
+				}
+			}
+			return &http.Response{
+				StatusCode: http.StatusOK,
+				Body:       io.NopCloser(strings.NewReader(body)),
+			}, nil
+		}),
+		NegotiatedSerializer: scheme.Codecs.WithoutConversion(),
+		GroupVersion:         corev1.SchemeGroupVersion,
+		VersionedAPIPath:     fmt.Sprintf("/api/v1/namespaces/%s/pods/%s/log", p.namespace, name),
+	}
+	return client.Request()
+}
+
+type offlineEvents struct {
+	corev1client.EventInterface
+}
+
+// List applies the field selectors the tools use, such as
+// involvedObject.kind and involvedObject.name.
+func (e *offlineEvents) List(ctx context.Context, opts metav1.ListOptions) (*corev1.EventList, error) {
+	selector, err := fields.ParseSelector(opts.FieldSelector)
+	if err != nil {
+		return nil, err
+	}
+	opts.FieldSelector = ""
+	events, err := e.EventInterface.List(ctx, opts)
+	if err != nil {
+		return nil, err
+	}
+	filtered := events.Items[:0]
+	for _, event := range events.Items {
+		if selector.Matches(fields.Set{
+			"involvedObject.kind":      event.InvolvedObject.Kind,
+			"involvedObject.name":      event.InvolvedObject.Name,
+			"involvedObject.namespace": event.InvolvedObject.Namespace,
+			"involvedObject.uid":       string(event.InvolvedObject.UID),
+			"reason":                   event.Reason,
+			"type":                     event.Type,
+		}) {
+			filtered = append(filtered, event)
+		}
+	}
+	events.Items = filtered
+	return events, nil
+}
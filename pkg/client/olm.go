@@ -2,19 +2,66 @@ package client
 
 import (
 	"context"
+	"encoding/json"
 
+	operatorsv1 "github.com/operator-framework/api/pkg/operators/v1"
 	"github.com/operator-framework/api/pkg/operators/v1alpha1"
+	operatorsv2 "github.com/operator-framework/api/pkg/operators/v2"
+	"github.com/operator-framework/operator-lifecycle-manager/olmv0-mcp-server/pkg/types"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime/schema"
 	"k8s.io/client-go/kubernetes/scheme"
 	"k8s.io/client-go/rest"
 )
 
 type OLMClient struct {
-	config *rest.Config
-	client rest.Interface
+	config         *rest.Config
+	client         rest.Interface
+	v1Client       rest.Interface
+	v2Client       rest.Interface
+	packagesClient rest.Interface
 }
 
 func NewOLMClient(config *rest.Config) (*OLMClient, error) {
 	v1alpha1.AddToScheme(scheme.Scheme)
+	operatorsv1.AddToScheme(scheme.Scheme)
+	operatorsv2.AddToScheme(scheme.Scheme)
+
+	// OperatorGroups are served from operators.coreos.com/v1.
+	v1Config := rest.CopyConfig(config)
+	v1GV := operatorsv1.SchemeGroupVersion
+	v1Config.GroupVersion = &v1GV
+	v1Config.APIPath = "/apis"
+	v1Config.NegotiatedSerializer = scheme.Codecs.WithoutConversion()
+
+	v1Client, err := rest.RESTClientFor(v1Config)
+	if err != nil {
+		return nil, err
+	}
+
+	// OperatorConditions are served from operators.coreos.com/v2.
+	v2Config := rest.CopyConfig(config)
+	v2GV := operatorsv2.GroupVersion
+	v2Config.GroupVersion = &v2GV
+	v2Config.APIPath = "/apis"
+	v2Config.NegotiatedSerializer = scheme.Codecs.WithoutConversion()
+
+	v2Client, err := rest.RESTClientFor(v2Config)
+	if err != nil {
+		return nil, err
+	}
+
+	// PackageManifests are served by OLM's aggregated package server and are
+	// decoded as plain JSON, so they get their own REST client.
+	packagesConfig := rest.CopyConfig(config)
+	packagesConfig.GroupVersion = &schema.GroupVersion{Group: "packages.operators.coreos.com", Version: "v1"}
+	packagesConfig.APIPath = "/apis"
+	packagesConfig.NegotiatedSerializer = scheme.Codecs.WithoutConversion()
+
+	packagesClient, err := rest.RESTClientFor(packagesConfig)
+	if err != nil {
+		return nil, err
+	}
 
 	gv := v1alpha1.SchemeGroupVersion
 	config.GroupVersion = &gv
@@ -27,8 +74,11 @@ func NewOLMClient(config *rest.Config) (*OLMClient, error) {
 	}
 
 	return &OLMClient{
-		config: config,
-		client: client,
+		config:         config,
+		client:         client,
+		v1Client:       v1Client,
+		v2Client:       v2Client,
+		packagesClient: packagesClient,
 	}, nil
 }
 
@@ -115,3 +165,123 @@ func (c *OLMClient) GetInstallPlan(ctx context.Context, namespace, name string)
 		Into(result)
 	return result, err
 }
+
+func (c *OLMClient) ListPackageManifests(ctx context.Context, namespace string) (*types.PackageManifestList, error) {
+	result := &types.PackageManifestList{}
+	data, err := c.packagesClient.Get().
+		Namespace(namespace).
+		Resource("packagemanifests").
+		DoRaw(ctx)
+	if err != nil {
+		return result, err
+	}
+	err = json.Unmarshal(data, result)
+	return result, err
+}
+
+func (c *OLMClient) ListOperatorGroups(ctx context.Context, namespace string) (*operatorsv1.OperatorGroupList, error) {
+	result := &operatorsv1.OperatorGroupList{}
+	err := c.v1Client.Get().
+		Namespace(namespace).
+		Resource("operatorgroups").
+		Do(ctx).
+		Into(result)
+	return result, err
+}
+
+func (c *OLMClient) ListOperatorConditions(ctx context.Context, namespace string) (*operatorsv2.OperatorConditionList, error) {
+	result := &operatorsv2.OperatorConditionList{}
+	err := c.v2Client.Get().
+		Namespace(namespace).
+		Resource("operatorconditions").
+		Do(ctx).
+		Into(result)
+	return result, err
+}
+
+func (c *OLMClient) CreateSubscription(ctx context.Context, subscription *v1alpha1.Subscription, dryRun bool) (*v1alpha1.Subscription, error) {
+	result := &v1alpha1.Subscription{}
+	err := c.client.Post().
+		Namespace(subscription.Namespace).
+		Resource("subscriptions").
+		VersionedParams(&metav1.CreateOptions{DryRun: dryRunOptions(dryRun)}, scheme.ParameterCodec).
+		Body(subscription).
+		Do(ctx).
+		Into(result)
+	return result, err
+}
+
+func (c *OLMClient) UpdateSubscription(ctx context.Context, subscription *v1alpha1.Subscription, dryRun bool) (*v1alpha1.Subscription, error) {
+	result := &v1alpha1.Subscription{}
+	err := c.client.Put().
+		Namespace(subscription.Namespace).
+		Resource("subscriptions").
+		Name(subscription.Name).
+		VersionedParams(&metav1.UpdateOptions{DryRun: dryRunOptions(dryRun)}, scheme.ParameterCodec).
+		Body(subscription).
+		Do(ctx).
+		Into(result)
+	return result, err
+}
+
+func (c *OLMClient) UpdateInstallPlan(ctx context.Context, installPlan *v1alpha1.InstallPlan, dryRun bool) (*v1alpha1.InstallPlan, error) {
+	result := &v1alpha1.InstallPlan{}
+	err := c.client.Put().
+		Namespace(installPlan.Namespace).
+		Resource("installplans").
+		Name(installPlan.Name).
+		VersionedParams(&metav1.UpdateOptions{DryRun: dryRunOptions(dryRun)}, scheme.ParameterCodec).
+		Body(installPlan).
+		Do(ctx).
+		Into(result)
+	return result, err
+}
+
+func (c *OLMClient) DeleteSubscription(ctx context.Context, namespace, name string, dryRun bool) error {
+	return c.client.Delete().
+		Namespace(namespace).
+		Resource("subscriptions").
+		Name(name).
+		Body(&metav1.DeleteOptions{DryRun: dryRunOptions(dryRun)}).
+		Do(ctx).
+		Error()
+}
+
+func (c *OLMClient) DeleteClusterServiceVersion(ctx context.Context, namespace, name string, dryRun bool) error {
+	return c.client.Delete().
+		Namespace(namespace).
+		Resource("clusterserviceversions").
+		Name(name).
+		Body(&metav1.DeleteOptions{DryRun: dryRunOptions(dryRun)}).
+		Do(ctx).
+		Error()
+}
+
+func (c *OLMClient) DeleteInstallPlan(ctx context.Context, namespace, name string, dryRun bool) error {
+	return c.client.Delete().
+		Namespace(namespace).
+		Resource("installplans").
+		Name(name).
+		Body(&metav1.DeleteOptions{DryRun: dryRunOptions(dryRun)}).
+		Do(ctx).
+		Error()
+}
+
+func (c *OLMClient) DeleteOperatorGroup(ctx context.Context, namespace, name string, dryRun bool) error {
+	return c.v1Client.Delete().
+		Namespace(namespace).
+		Resource("operatorgroups").
+		Name(name).
+		Body(&metav1.DeleteOptions{DryRun: dryRunOptions(dryRun)}).
+		Do(ctx).
+		Error()
+}
+
+// dryRunOptions translates a dry-run flag into the value expected by the
+// DryRun field of the metav1 write options.
+func dryRunOptions(dryRun bool) []string {
+	if dryRun {
+		return []string{metav1.DryRunAll}
+	}
+	return nil
+}
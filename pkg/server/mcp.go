@@ -6,6 +6,7 @@ import (
 	"encoding/json"
 	"fmt"
 	"os"
+	"strconv"
 	"strings"
 
 	"github.com/operator-framework/operator-lifecycle-manager/olmv0-mcp-server/pkg/tools"
@@ -14,22 +15,26 @@ import (
 )
 
 type MCPStdioServer struct {
-	server   *types.MCPServer
-	csvTools *tools.CSVTools
-	subTools *tools.SubscriptionTools
-	catTools *tools.CatalogTools
-	ipTools  *tools.InstallPlanTools
-	logger   *logrus.Logger
+	server    *types.MCPServer
+	csvTools  *tools.CSVTools
+	subTools  *tools.SubscriptionTools
+	catTools  *tools.CatalogTools
+	ipTools   *tools.InstallPlanTools
+	diagTools *tools.DiagnosticTools
+	enabled   map[string]bool
+	logger    *logrus.Logger
 }
 
 func NewMCPStdioServer(server *types.MCPServer) *MCPStdioServer {
 	return &MCPStdioServer{
-		server:   server,
-		csvTools: tools.NewCSVTools(server),
-		subTools: tools.NewSubscriptionTools(server),
-		catTools: tools.NewCatalogTools(server),
-		ipTools:  tools.NewInstallPlanTools(server),
-		logger:   logrus.New(),
+		server:    server,
+		enabled:   server.EnabledTools(),
+		csvTools:  tools.NewCSVTools(server),
+		subTools:  tools.NewSubscriptionTools(server),
+		catTools:  tools.NewCatalogTools(server),
+		ipTools:   tools.NewInstallPlanTools(server),
+		diagTools: tools.NewDiagnosticTools(server),
+		logger:    logrus.New(),
 	}
 }
 
@@ -106,7 +111,7 @@ func (s *MCPStdioServer) handleToolsList(req types.MCPRequest) *types.MCPRespons
 	tools := []types.MCPTool{
 		{
 			Name:        "list_csvs",
-			Description: "List ClusterServiceVersions in a namespace",
+			Description: "List ClusterServiceVersions in a namespace with the OperatorGroup that owns each. CSVs OLM copied from another namespace are hidden unless include_copied is set",
 			InputSchema: map[string]interface{}{
 				"type": "object",
 				"properties": map[string]interface{}{
@@ -114,6 +119,10 @@ func (s *MCPStdioServer) handleToolsList(req types.MCPRequest) *types.MCPRespons
 						"type":        "string",
 						"description": "Kubernetes namespace (default: default)",
 					},
+					"include_copied": map[string]interface{}{
+						"type":        "boolean",
+						"description": "Include copied CSVs and the namespace they were copied from (default: false)",
+					},
 				},
 			},
 		},
@@ -166,6 +175,62 @@ func (s *MCPStdioServer) handleToolsList(req types.MCPRequest) *types.MCPRespons
 				"required": []string{"name"},
 			},
 		},
+		{
+			Name:        "update_subscription",
+			Description: "Update a Subscription's channel or install plan approval strategy. The change is previewed with a server-side dry-run and shown as a diff; real writes require --read-only=false",
+			InputSchema: map[string]interface{}{
+				"type": "object",
+				"properties": map[string]interface{}{
+					"name": map[string]interface{}{
+						"type":        "string",
+						"description": "Name of the Subscription",
+					},
+					"namespace": map[string]interface{}{
+						"type":        "string",
+						"description": "Kubernetes namespace (default: default)",
+					},
+					"channel": map[string]interface{}{
+						"type":        "string",
+						"description": "New channel to subscribe to",
+					},
+					"approval": map[string]interface{}{
+						"type":        "string",
+						"description": "New install plan approval strategy (Automatic or Manual)",
+					},
+					"dry_run": map[string]interface{}{
+						"type":        "boolean",
+						"description": "Only preview the change using server-side dry-run (default: false)",
+					},
+				},
+				"required": []string{"name"},
+			},
+		},
+		{
+			Name:        "rollback_operator",
+			Description: "Roll an operator back to the CSV its installed CSV replaced, by deleting the Subscription and CSV and recreating the Subscription pinned to the previous CSV with Manual approval. Every step is previewed with a server-side dry-run; real writes require --read-only=false",
+			InputSchema: map[string]interface{}{
+				"type": "object",
+				"properties": map[string]interface{}{
+					"name": map[string]interface{}{
+						"type":        "string",
+						"description": "Name of the Subscription",
+					},
+					"namespace": map[string]interface{}{
+						"type":        "string",
+						"description": "Kubernetes namespace (default: default)",
+					},
+					"target_csv": map[string]interface{}{
+						"type":        "string",
+						"description": "CSV to roll back to (default: the CSV named in spec.replaces of the installed CSV)",
+					},
+					"dry_run": map[string]interface{}{
+						"type":        "boolean",
+						"description": "Only preview the rollback using server-side dry-run (default: false)",
+					},
+				},
+				"required": []string{"name"},
+			},
+		},
 		{
 			Name:        "list_catalog_sources",
 			Description: "List CatalogSources in a namespace",
@@ -212,7 +277,400 @@ func (s *MCPStdioServer) handleToolsList(req types.MCPRequest) *types.MCPRespons
 		},
 		{
 			Name:        "get_install_plan",
-			Description: "Get detailed information about a specific InstallPlan",
+			Description: "Get detailed information about a specific InstallPlan. Each step is shown with its group/version/kind, name, status and a short description; steps can be filtered by kind or status, and show_manifests decodes each step's full manifest, following bundle ConfigMap references",
+			InputSchema: map[string]interface{}{
+				"type": "object",
+				"properties": map[string]interface{}{
+					"name": map[string]interface{}{
+						"type":        "string",
+						"description": "Name of the InstallPlan",
+					},
+					"namespace": map[string]interface{}{
+						"type":        "string",
+						"description": "Kubernetes namespace (default: default)",
+					},
+					"kind": map[string]interface{}{
+						"type":        "string",
+						"description": "Only show steps of this kind, e.g. CustomResourceDefinition",
+					},
+					"status": map[string]interface{}{
+						"type":        "string",
+						"description": "Only show steps with this status, e.g. Created, Present, Unknown or WaitingForApi",
+					},
+					"show_manifests": map[string]interface{}{
+						"type":        "boolean",
+						"description": "Fetch and decode the full manifest of each shown step (default: false)",
+					},
+				},
+				"required": []string{"name"},
+			},
+		},
+		{
+			Name:        "approve_install_plan",
+			Description: "Approve a pending InstallPlan. The change is previewed with a server-side dry-run and shown as a diff; real writes require --read-only=false",
+			InputSchema: map[string]interface{}{
+				"type": "object",
+				"properties": map[string]interface{}{
+					"name": map[string]interface{}{
+						"type":        "string",
+						"description": "Name of the InstallPlan",
+					},
+					"namespace": map[string]interface{}{
+						"type":        "string",
+						"description": "Kubernetes namespace (default: default)",
+					},
+					"dry_run": map[string]interface{}{
+						"type":        "boolean",
+						"description": "Only preview the change using server-side dry-run (default: false)",
+					},
+				},
+				"required": []string{"name"},
+			},
+		},
+		{
+			Name:        "diagnose_subscription",
+			Description: "Diagnose why an operator is not installed by walking the Subscription, its InstallPlan, the CSV and the CSV's deployments and pods, returning a ranked list of findings with evidence",
+			InputSchema: map[string]interface{}{
+				"type": "object",
+				"properties": map[string]interface{}{
+					"name": map[string]interface{}{
+						"type":        "string",
+						"description": "Name of the Subscription",
+					},
+					"namespace": map[string]interface{}{
+						"type":        "string",
+						"description": "Kubernetes namespace (default: default)",
+					},
+				},
+				"required": []string{"name"},
+			},
+		},
+		{
+			Name:        "check_csv_health",
+			Description: "Check the health of a ClusterServiceVersion: phase history from status.conditions, unmet requirements, the deployments in spec.install.spec.deployments and their pods, including crash-looping or unready pods and recent restarts",
+			InputSchema: map[string]interface{}{
+				"type": "object",
+				"properties": map[string]interface{}{
+					"name": map[string]interface{}{
+						"type":        "string",
+						"description": "Name of the ClusterServiceVersion",
+					},
+					"namespace": map[string]interface{}{
+						"type":        "string",
+						"description": "Kubernetes namespace (default: default)",
+					},
+				},
+				"required": []string{"name"},
+			},
+		},
+		{
+			Name:        "check_catalog_health",
+			Description: "Check the health of a CatalogSource: connection state, registry pod (found by the olm.catalogSource label) image pull status and restarts, registry service and endpoints, image registry polling, and the Subscriptions that depend on it. States whether resolution failures are caused by catalog problems",
+			InputSchema: map[string]interface{}{
+				"type": "object",
+				"properties": map[string]interface{}{
+					"name": map[string]interface{}{
+						"type":        "string",
+						"description": "Name of the CatalogSource",
+					},
+					"namespace": map[string]interface{}{
+						"type":        "string",
+						"description": "Kubernetes namespace (default: olm)",
+					},
+				},
+				"required": []string{"name"},
+			},
+		},
+		{
+			Name:        "get_olm_logs",
+			Description: "Read olm-operator and catalog-operator container logs, limited by 'since' and 'tail', and keep only lines that mention the given namespace, Subscription, CSV or InstallPlan so reconciliation errors can be quoted directly",
+			InputSchema: map[string]interface{}{
+				"type": "object",
+				"properties": map[string]interface{}{
+					"namespace": map[string]interface{}{
+						"type":        "string",
+						"description": "Only keep lines mentioning this namespace",
+					},
+					"subscription": map[string]interface{}{
+						"type":        "string",
+						"description": "Only keep lines mentioning this Subscription",
+					},
+					"csv": map[string]interface{}{
+						"type":        "string",
+						"description": "Only keep lines mentioning this ClusterServiceVersion",
+					},
+					"install_plan": map[string]interface{}{
+						"type":        "string",
+						"description": "Only keep lines mentioning this InstallPlan",
+					},
+					"component": map[string]interface{}{
+						"type":        "string",
+						"description": "olm-operator, catalog-operator or all (default: all)",
+					},
+					"since": map[string]interface{}{
+						"type":        "string",
+						"description": "Only read logs newer than this duration, e.g. 30m or 2h",
+					},
+					"tail": map[string]interface{}{
+						"type":        "number",
+						"description": "Number of most recent log lines to read per container (default: 1000)",
+					},
+					"max_lines": map[string]interface{}{
+						"type":        "number",
+						"description": "Maximum matching lines to return per container (default: 200)",
+					},
+					"olm_namespace": map[string]interface{}{
+						"type":        "string",
+						"description": "Namespace OLM runs in (default: search all namespaces)",
+					},
+				},
+			},
+		},
+		{
+			Name:        "get_events",
+			Description: "List core/v1 and events.k8s.io events about an OLM resource, sorted by time and deduplicated by reason. CSV events such as InstallWaiting and ComponentUnhealthy often explain why an install is stuck",
+			InputSchema: map[string]interface{}{
+				"type": "object",
+				"properties": map[string]interface{}{
+					"kind": map[string]interface{}{
+						"type":        "string",
+						"description": "Kind of the resource: csv, subscription, catalogsource, installplan or operatorgroup",
+					},
+					"name": map[string]interface{}{
+						"type":        "string",
+						"description": "Name of the resource",
+					},
+					"namespace": map[string]interface{}{
+						"type":        "string",
+						"description": "Kubernetes namespace (default: default)",
+					},
+				},
+				"required": []string{"kind", "name"},
+			},
+		},
+		{
+			Name:        "list_pending_upgrades",
+			Description: "List pending operator upgrades across the cluster: Subscriptions whose currentCSV differs from installedCSV, InstallPlans awaiting manual approval, and Subscriptions whose catalog channel serves a newer CSV. Shows from and to versions and what each upgrade changes (new or updated CRDs, new permissions)",
+			InputSchema: map[string]interface{}{
+				"type": "object",
+				"properties": map[string]interface{}{
+					"namespace": map[string]interface{}{
+						"type":        "string",
+						"description": "Only report on this namespace (default: all namespaces)",
+					},
+				},
+			},
+		},
+		{
+			Name:        "explain_resolution_failure",
+			Description: "Explain a Subscription's ResolutionFailed condition: parse OLM's constraint message into structured violations (missing package, no channel head, conflicting API provider, required API not provided, installed CSV cannot be upgraded, catalog unavailable), map each to the catalogs, packages and CSVs involved, and suggest fixes based on what the catalogs serve",
+			InputSchema: map[string]interface{}{
+				"type": "object",
+				"properties": map[string]interface{}{
+					"name": map[string]interface{}{
+						"type":        "string",
+						"description": "Name of the Subscription whose ResolutionFailed condition to explain",
+					},
+					"namespace": map[string]interface{}{
+						"type":        "string",
+						"description": "Kubernetes namespace (default: default)",
+					},
+					"message": map[string]interface{}{
+						"type":        "string",
+						"description": "A resolution failure message to explain instead of reading the Subscription's condition",
+					},
+				},
+			},
+		},
+		{
+			Name:        "list_api_providers",
+			Description: "Build a map of which operator provides each API (group/version/kind) from every CSV's owned CRDs and APIServices, which operators require it, and flag APIs provided by more than one package (ignoring CSVs being replaced or deleted) or required but not provided. Copied CSVs are ignored",
+			InputSchema: map[string]interface{}{
+				"type": "object",
+				"properties": map[string]interface{}{
+					"namespace": map[string]interface{}{
+						"type":        "string",
+						"description": "Only read CSVs in this namespace (default: all namespaces)",
+					},
+				},
+			},
+		},
+		{
+			Name:        "get_operator_permissions",
+			Description: "Summarize a CSV's spec.install.spec.permissions and clusterPermissions per service account, highlight wildcard, cluster-admin-like and privilege-escalating grants, list the Roles and ClusterRoles OLM generated for it, and report drift between the CSV and the generated RBAC",
+			InputSchema: map[string]interface{}{
+				"type": "object",
+				"properties": map[string]interface{}{
+					"name": map[string]interface{}{
+						"type":        "string",
+						"description": "Name of the ClusterServiceVersion",
+					},
+					"namespace": map[string]interface{}{
+						"type":        "string",
+						"description": "Kubernetes namespace (default: default)",
+					},
+				},
+				"required": []string{"name"},
+			},
+		},
+		{
+			Name:        "summarize_olm",
+			Description: "Summarize OLM across all namespaces in one call: CSVs by phase (copied CSVs ignored), Subscriptions with failing conditions, CatalogSources by connection state, InstallPlans by phase and InstallPlans awaiting approval. Returns a dashboard-style summary and a JSON payload",
+			InputSchema: map[string]interface{}{
+				"type": "object",
+				"properties": map[string]interface{}{
+					"namespace": map[string]interface{}{
+						"type":        "string",
+						"description": "Only summarize this namespace (default: all namespaces)",
+					},
+				},
+			},
+		},
+		{
+			Name:        "list_deprecations",
+			Description: "List Subscriptions whose package, channel or installed bundle is deprecated, from the PackageDeprecated, ChannelDeprecated and BundleDeprecated conditions and the catalog's olm.deprecations data, with the deprecation messages and a suggested replacement channel where the catalog offers one",
+			InputSchema: map[string]interface{}{
+				"type": "object",
+				"properties": map[string]interface{}{
+					"namespace": map[string]interface{}{
+						"type":        "string",
+						"description": "Only check Subscriptions in this namespace (default: all namespaces)",
+					},
+				},
+			},
+		},
+		{
+			Name:        "find_orphans",
+			Description: "Find stale OLM objects: Complete or Failed InstallPlans no Subscription references, CSVs stuck in Replacing or Deleting, Subscriptions whose CatalogSource was deleted, and OperatorGroups in namespaces with no operators. Lists each with its age, reason and an ID for cleanup_orphans",
+			InputSchema: map[string]interface{}{
+				"type": "object",
+				"properties": map[string]interface{}{
+					"namespace": map[string]interface{}{
+						"type":        "string",
+						"description": "Only check this namespace (default: all namespaces)",
+					},
+					"min_age": map[string]interface{}{
+						"type":        "string",
+						"description": "Only report objects older than this duration, e.g. 24h (default: 1h)",
+					},
+				},
+			},
+		},
+		{
+			Name:        "cleanup_orphans",
+			Description: "Delete orphaned objects reported by find_orphans. Only the given IDs are deleted, and only if they are still orphans. Blocked in read-only mode unless dry_run is true; dry_run previews the deletions with a server-side dry-run",
+			InputSchema: map[string]interface{}{
+				"type": "object",
+				"properties": map[string]interface{}{
+					"targets": map[string]interface{}{
+						"type":        "string",
+						"description": "Comma-separated IDs from find_orphans, e.g. installplan/operators/install-abcde",
+					},
+					"min_age": map[string]interface{}{
+						"type":        "string",
+						"description": "Minimum age used when re-checking the targets (default: 1h)",
+					},
+					"dry_run": map[string]interface{}{
+						"type":        "boolean",
+						"description": "Preview the deletions without persisting them (default: false)",
+					},
+				},
+				"required": []string{"targets"},
+			},
+		},
+		{
+			Name:        "diff_csvs",
+			Description: "Semantic diff of two CSVs: version, replaces/skips/skipRange, owned and required CRDs, owned APIServices, permissions, deployment and related images, install modes and minKubeVersion. Each side is an installed CSV (name or namespace/name), a CSV pending in an InstallPlan (installplan:[namespace/]plan[/csv]) or a catalog channel head (catalog:[source/]package/channel)",
+			InputSchema: map[string]interface{}{
+				"type": "object",
+				"properties": map[string]interface{}{
+					"from": map[string]interface{}{
+						"type":        "string",
+						"description": "Reference to the old CSV",
+					},
+					"to": map[string]interface{}{
+						"type":        "string",
+						"description": "Reference to the new CSV",
+					},
+					"namespace": map[string]interface{}{
+						"type":        "string",
+						"description": "Namespace used for references without one (default: default)",
+					},
+				},
+				"required": []string{"from", "to"},
+			},
+		},
+		{
+			Name:        "get_dependency_graph",
+			Description: "Build a graph of installed operators from each CSV's required CRDs and APIServices and the olm.gvk.required and olm.package.required bundle properties, showing what each operator depends on and who depends on it. Renders as text, DOT or Mermaid",
+			InputSchema: map[string]interface{}{
+				"type": "object",
+				"properties": map[string]interface{}{
+					"format": map[string]interface{}{
+						"type":        "string",
+						"description": "text, dot, mermaid or all (default: text)",
+					},
+					"operator": map[string]interface{}{
+						"type":        "string",
+						"description": "Only show operators connected to this CSV",
+					},
+					"namespace": map[string]interface{}{
+						"type":        "string",
+						"description": "Namespace of 'operator' (default: default)",
+					},
+				},
+			},
+		},
+		{
+			Name:        "validate_operator_groups",
+			Description: "Validate OperatorGroups across the cluster: namespaces with more than one OperatorGroup or none, CSVs whose spec.installModes do not support their group's target namespaces, and groups with overlapping target namespaces that provide the same API. Each finding cites the CSVs' TooManyOperatorGroups, UnsupportedOperatorGroup or InterOperatorGroupOwnerConflict status reasons",
+			InputSchema: map[string]interface{}{
+				"type":       "object",
+				"properties": map[string]interface{}{},
+			},
+		},
+		{
+			Name:        "collect_olm_state",
+			Description: "Write a must-gather style tarball of OLM state for support tickets: every CSV, Subscription, InstallPlan, CatalogSource, OperatorGroup and OperatorCondition, the Deployments and Pods of OLM and installed operators, registry Services and EndpointSlices, OLM-generated RBAC, bundle unpack Jobs with their Pods and ConfigMaps, events about them and about catalog pods, olm-operator and catalog-operator logs, and catalog pod status, in a timestamped olm-state-<timestamp>-<random>.tar.gz with a fixed directory layout in the server's temp directory. Returns the path of the tarball",
+			InputSchema: map[string]interface{}{
+				"type": "object",
+				"properties": map[string]interface{}{
+					"namespace": map[string]interface{}{
+						"type":        "string",
+						"description": "Only collect objects in this namespace (default: all namespaces)",
+					},
+					"log_tail": map[string]interface{}{
+						"type":        "string",
+						"description": "Number of log lines to keep per OLM container (default: 5000)",
+					},
+				},
+			},
+		},
+		{
+			Name:        "get_install_timeline",
+			Description: "Reconstruct the install of a Subscription or CSV as one chronological timeline, merging creation timestamps, condition lastTransitionTime values from the Subscription, InstallPlan (including bundle lookups) and CSV, the CSV's phase history and events. Splits the install into stages (resolution, bundle unpacking, approval wait, step execution, CSV install) and reports where the time was spent",
+			InputSchema: map[string]interface{}{
+				"type": "object",
+				"properties": map[string]interface{}{
+					"name": map[string]interface{}{
+						"type":        "string",
+						"description": "Name of the Subscription or ClusterServiceVersion",
+					},
+					"kind": map[string]interface{}{
+						"type":        "string",
+						"description": "subscription or csv (default: subscription)",
+					},
+					"namespace": map[string]interface{}{
+						"type":        "string",
+						"description": "Kubernetes namespace (default: default)",
+					},
+				},
+				"required": []string{"name"},
+			},
+		},
+		{
+			Name:        "diagnose_bundle_unpack",
+			Description: "Diagnose BundleLookupFailed errors and hung bundle unpacking for an InstallPlan: reads status.bundleLookups and their conditions, finds the matching unpack Job, pod and ConfigMap in the catalog namespace, and reports image pull errors, timeouts (including the operatorframework.io/bundle-unpack-timeout annotation on the OperatorGroup) and the log tail of failed containers",
 			InputSchema: map[string]interface{}{
 				"type": "object",
 				"properties": map[string]interface{}{
@@ -228,13 +686,103 @@ func (s *MCPStdioServer) handleToolsList(req types.MCPRequest) *types.MCPRespons
 				"required": []string{"name"},
 			},
 		},
+		{
+			Name:        "trace_csv",
+			Description: "Trace where a CSV came from: the owning Subscription (by status.installedCSV/currentCSV or the operators.coreos.com owner label), the InstallPlan that created it, its catalog and channel, and the chain of CSVs it replaced. With kind=subscription, list everything a Subscription produced: its InstallPlans, the objects they created, the CSV chain and copied CSVs",
+			InputSchema: map[string]interface{}{
+				"type": "object",
+				"properties": map[string]interface{}{
+					"name": map[string]interface{}{
+						"type":        "string",
+						"description": "Name of the ClusterServiceVersion or Subscription",
+					},
+					"kind": map[string]interface{}{
+						"type":        "string",
+						"description": "What name refers to: csv (default) or subscription",
+					},
+					"namespace": map[string]interface{}{
+						"type":        "string",
+						"description": "Kubernetes namespace (default: default)",
+					},
+				},
+				"required": []string{"name"},
+			},
+		},
+		{
+			Name:        "list_fbc_packages",
+			Description: "List the packages of a File-Based Catalog directory on local disk with their default channel, channels and bundles, or the channels and bundles of one package. Reads olm.package, olm.channel and olm.bundle blobs from JSON and YAML files; no cluster is needed",
+			InputSchema: map[string]interface{}{
+				"type": "object",
+				"properties": map[string]interface{}{
+					"path": map[string]interface{}{
+						"type":        "string",
+						"description": "Directory of the File-Based Catalog, relative to the server's --catalog-dir (default: all of --catalog-dir)",
+					},
+					"package": map[string]interface{}{
+						"type":        "string",
+						"description": "Show the channels and bundles of this package",
+					},
+				},
+			},
+		},
+		{
+			Name:        "get_fbc_upgrade_graph",
+			Description: "Render the upgrade graph of a channel in a local File-Based Catalog from replaces, skips and skipRange, as text, DOT or Mermaid, marking the head, bundles with no upgrade path to it and referenced bundles the channel lacks",
+			InputSchema: map[string]interface{}{
+				"type": "object",
+				"properties": map[string]interface{}{
+					"package": map[string]interface{}{
+						"type":        "string",
+						"description": "Name of the package",
+					},
+					"channel": map[string]interface{}{
+						"type":        "string",
+						"description": "Name of the channel (default: the package's default channel)",
+					},
+					"format": map[string]interface{}{
+						"type":        "string",
+						"description": "Output format: text (default), dot, mermaid or all",
+					},
+					"path": map[string]interface{}{
+						"type":        "string",
+						"description": "Directory of the File-Based Catalog, relative to the server's --catalog-dir (default: all of --catalog-dir)",
+					},
+				},
+				"required": []string{"package"},
+			},
+		},
+		{
+			Name:        "validate_fbc",
+			Description: "Validate a local File-Based Catalog: malformed files and blobs, missing or unknown default channels, empty channels, channels with several heads or a looping replaces chain, bundles with no upgrade path to the channel head, bundles in no channel and invalid versions or skipRanges",
+			InputSchema: map[string]interface{}{
+				"type": "object",
+				"properties": map[string]interface{}{
+					"package": map[string]interface{}{
+						"type":        "string",
+						"description": "Only validate this package",
+					},
+					"path": map[string]interface{}{
+						"type":        "string",
+						"description": "Directory of the File-Based Catalog, relative to the server's --catalog-dir (default: all of --catalog-dir)",
+					},
+				},
+			},
+		},
+	}
+
+	// Only advertise the tools in the enabled toolsets
+	enabledTools := []types.MCPTool{}
+	for _, tool := range tools {
+		if s.enabled[tool.Name] {
+			enabledTools = append(enabledTools, tool)
+		}
 	}
 
 	return &types.MCPResponse{
 		JSONRPC: "2.0",
 		ID:      req.ID,
 		Result: map[string]interface{}{
-			"tools": tools,
+			"tools": enabledTools,
 		},
 	}
 }
@@ -264,14 +812,20 @@ func (s *MCPStdioServer) handleToolCall(ctx context.Context, req types.MCPReques
 		}
 	}
 
-	// Convert params to string map for existing tool functions
-	stringParams := make(map[string]string)
-	for k, v := range params {
-		if str, ok := v.(string); ok {
-			stringParams[k] = str
+	if !s.enabled[toolName] {
+		return &types.MCPResponse{
+			JSONRPC: "2.0",
+			ID:      req.ID,
+			Error: &types.MCPError{
+				Code:    -32601,
+				Message: fmt.Sprintf("Unknown or disabled tool: %s", toolName),
+			},
 		}
 	}
 
+	// Convert params to string map for existing tool functions
+	stringParams := toStringParams(params)
+
 	var result *types.MCPToolResult
 	var err error
 
@@ -284,6 +838,10 @@ func (s *MCPStdioServer) handleToolCall(ctx context.Context, req types.MCPReques
 		result, err = s.subTools.ListSubscriptions(ctx, stringParams)
 	case "get_subscription":
 		result, err = s.subTools.GetSubscription(ctx, stringParams)
+	case "update_subscription":
+		result, err = s.subTools.UpdateSubscription(ctx, stringParams)
+	case "rollback_operator":
+		result, err = s.subTools.RollbackOperator(ctx, stringParams)
 	case "list_catalog_sources":
 		result, err = s.catTools.ListCatalogSources(ctx, stringParams)
 	case "get_catalog_source":
@@ -292,6 +850,54 @@ func (s *MCPStdioServer) handleToolCall(ctx context.Context, req types.MCPReques
 		result, err = s.ipTools.ListInstallPlans(ctx, stringParams)
 	case "get_install_plan":
 		result, err = s.ipTools.GetInstallPlan(ctx, stringParams)
+	case "approve_install_plan":
+		result, err = s.ipTools.ApproveInstallPlan(ctx, stringParams)
+	case "diagnose_subscription":
+		result, err = s.diagTools.DiagnoseSubscription(ctx, stringParams)
+	case "check_csv_health":
+		result, err = s.diagTools.CheckCSVHealth(ctx, stringParams)
+	case "check_catalog_health":
+		result, err = s.diagTools.CheckCatalogHealth(ctx, stringParams)
+	case "get_olm_logs":
+		result, err = s.diagTools.GetOLMLogs(ctx, stringParams)
+	case "get_events":
+		result, err = s.diagTools.GetEvents(ctx, stringParams)
+	case "list_pending_upgrades":
+		result, err = s.subTools.ListPendingUpgrades(ctx, stringParams)
+	case "explain_resolution_failure":
+		result, err = s.diagTools.ExplainResolutionFailure(ctx, stringParams)
+	case "list_api_providers":
+		result, err = s.csvTools.ListAPIProviders(ctx, stringParams)
+	case "get_operator_permissions":
+		result, err = s.csvTools.GetOperatorPermissions(ctx, stringParams)
+	case "summarize_olm":
+		result, err = s.diagTools.SummarizeOLM(ctx, stringParams)
+	case "list_deprecations":
+		result, err = s.subTools.ListDeprecations(ctx, stringParams)
+	case "find_orphans":
+		result, err = s.diagTools.FindOrphans(ctx, stringParams)
+	case "cleanup_orphans":
+		result, err = s.diagTools.CleanupOrphans(ctx, stringParams)
+	case "diff_csvs":
+		result, err = s.csvTools.DiffCSVs(ctx, stringParams)
+	case "get_dependency_graph":
+		result, err = s.csvTools.GetDependencyGraph(ctx, stringParams)
+	case "validate_operator_groups":
+		result, err = s.diagTools.ValidateOperatorGroups(ctx, stringParams)
+	case "collect_olm_state":
+		result, err = s.diagTools.CollectOLMState(ctx, stringParams)
+	case "get_install_timeline":
+		result, err = s.diagTools.GetInstallTimeline(ctx, stringParams)
+	case "diagnose_bundle_unpack":
+		result, err = s.diagTools.DiagnoseBundleUnpack(ctx, stringParams)
+	case "trace_csv":
+		result, err = s.csvTools.TraceCSV(ctx, stringParams)
+	case "list_fbc_packages":
+		result, err = s.catTools.ListFBCPackages(ctx, stringParams)
+	case "get_fbc_upgrade_graph":
+		result, err = s.catTools.GetFBCUpgradeGraph(ctx, stringParams)
+	case "validate_fbc":
+		result, err = s.catTools.ValidateFBC(ctx, stringParams)
 	default:
 		return &types.MCPResponse{
 			JSONRPC: "2.0",
@@ -322,6 +928,24 @@ func (s *MCPStdioServer) handleToolCall(ctx context.Context, req types.MCPReques
 	}
 }
 
+// toStringParams flattens tool arguments into the string map expected by the
+// tool functions. Booleans and numbers are formatted so that flags such as
+// dry_run survive the conversion; other value types are dropped.
+func toStringParams(params map[string]interface{}) map[string]string {
+	stringParams := make(map[string]string)
+	for k, v := range params {
+		switch value := v.(type) {
+		case string:
+			stringParams[k] = value
+		case bool:
+			stringParams[k] = strconv.FormatBool(value)
+		case float64:
+			stringParams[k] = strconv.FormatFloat(value, 'f', -1, 64)
+		}
+	}
+	return stringParams
+}
+
 func (s *MCPStdioServer) sendError(id interface{}, code int, message, data string) {
 	response := &types.MCPResponse{
 		JSONRPC: "2.0",
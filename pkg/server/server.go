@@ -13,22 +13,26 @@ import (
 )
 
 type MCPHandler struct {
-	server   *types.MCPServer
-	csvTools *tools.CSVTools
-	subTools *tools.SubscriptionTools
-	catTools *tools.CatalogTools
-	ipTools  *tools.InstallPlanTools
-	logger   *logrus.Logger
+	server    *types.MCPServer
+	csvTools  *tools.CSVTools
+	subTools  *tools.SubscriptionTools
+	catTools  *tools.CatalogTools
+	ipTools   *tools.InstallPlanTools
+	diagTools *tools.DiagnosticTools
+	enabled   map[string]bool
+	logger    *logrus.Logger
 }
 
 func NewMCPHandler(server *types.MCPServer) *MCPHandler {
 	return &MCPHandler{
-		server:   server,
-		csvTools: tools.NewCSVTools(server),
-		subTools: tools.NewSubscriptionTools(server),
-		catTools: tools.NewCatalogTools(server),
-		ipTools:  tools.NewInstallPlanTools(server),
-		logger:   logrus.New(),
+		server:    server,
+		enabled:   server.EnabledTools(),
+		csvTools:  tools.NewCSVTools(server),
+		subTools:  tools.NewSubscriptionTools(server),
+		catTools:  tools.NewCatalogTools(server),
+		ipTools:   tools.NewInstallPlanTools(server),
+		diagTools: tools.NewDiagnosticTools(server),
+		logger:    logrus.New(),
 	}
 }
 
@@ -80,17 +84,20 @@ func (h *MCPHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
 func (h *MCPHandler) handleRequest(ctx context.Context, req types.MCPRequest) (*types.MCPResponse, error) {
 	method := req.Method
 	// Convert interface{} params to string map for HTTP compatibility
-	stringParams := make(map[string]string)
-	if req.Params != nil {
-		for k, v := range req.Params {
-			if str, ok := v.(string); ok {
-				stringParams[k] = str
-			}
-		}
-	}
+	stringParams := toStringParams(req.Params)
 
 	h.logger.Infof("Handling request: %s with params: %v", method, stringParams)
 
+	if method != "list_tools" && !h.enabled[method] {
+		return &types.MCPResponse{
+			JSONRPC: "2.0",
+			Error: &types.MCPError{
+				Code:    -32601,
+				Message: fmt.Sprintf("Unknown or disabled method: %s", method),
+			},
+		}, nil
+	}
+
 	var toolResult *types.MCPToolResult
 	var err error
 
@@ -105,6 +112,10 @@ func (h *MCPHandler) handleRequest(ctx context.Context, req types.MCPRequest) (*
 		toolResult, err = h.subTools.ListSubscriptions(ctx, stringParams)
 	case "get_subscription":
 		toolResult, err = h.subTools.GetSubscription(ctx, stringParams)
+	case "update_subscription":
+		toolResult, err = h.subTools.UpdateSubscription(ctx, stringParams)
+	case "rollback_operator":
+		toolResult, err = h.subTools.RollbackOperator(ctx, stringParams)
 	case "list_catalog_sources":
 		toolResult, err = h.catTools.ListCatalogSources(ctx, stringParams)
 	case "get_catalog_source":
@@ -113,6 +124,54 @@ func (h *MCPHandler) handleRequest(ctx context.Context, req types.MCPRequest) (*
 		toolResult, err = h.ipTools.ListInstallPlans(ctx, stringParams)
 	case "get_install_plan":
 		toolResult, err = h.ipTools.GetInstallPlan(ctx, stringParams)
+	case "approve_install_plan":
+		toolResult, err = h.ipTools.ApproveInstallPlan(ctx, stringParams)
+	case "diagnose_subscription":
+		toolResult, err = h.diagTools.DiagnoseSubscription(ctx, stringParams)
+	case "check_csv_health":
+		toolResult, err = h.diagTools.CheckCSVHealth(ctx, stringParams)
+	case "check_catalog_health":
+		toolResult, err = h.diagTools.CheckCatalogHealth(ctx, stringParams)
+	case "get_olm_logs":
+		toolResult, err = h.diagTools.GetOLMLogs(ctx, stringParams)
+	case "get_events":
+		toolResult, err = h.diagTools.GetEvents(ctx, stringParams)
+	case "list_pending_upgrades":
+		toolResult, err = h.subTools.ListPendingUpgrades(ctx, stringParams)
+	case "explain_resolution_failure":
+		toolResult, err = h.diagTools.ExplainResolutionFailure(ctx, stringParams)
+	case "list_api_providers":
+		toolResult, err = h.csvTools.ListAPIProviders(ctx, stringParams)
+	case "get_operator_permissions":
+		toolResult, err = h.csvTools.GetOperatorPermissions(ctx, stringParams)
+	case "summarize_olm":
+		toolResult, err = h.diagTools.SummarizeOLM(ctx, stringParams)
+	case "list_deprecations":
+		toolResult, err = h.subTools.ListDeprecations(ctx, stringParams)
+	case "find_orphans":
+		toolResult, err = h.diagTools.FindOrphans(ctx, stringParams)
+	case "cleanup_orphans":
+		toolResult, err = h.diagTools.CleanupOrphans(ctx, stringParams)
+	case "diff_csvs":
+		toolResult, err = h.csvTools.DiffCSVs(ctx, stringParams)
+	case "get_dependency_graph":
+		toolResult, err = h.csvTools.GetDependencyGraph(ctx, stringParams)
+	case "validate_operator_groups":
+		toolResult, err = h.diagTools.ValidateOperatorGroups(ctx, stringParams)
+	case "collect_olm_state":
+		toolResult, err = h.diagTools.CollectOLMState(ctx, stringParams)
+	case "get_install_timeline":
+		toolResult, err = h.diagTools.GetInstallTimeline(ctx, stringParams)
+	case "diagnose_bundle_unpack":
+		toolResult, err = h.diagTools.DiagnoseBundleUnpack(ctx, stringParams)
+	case "trace_csv":
+		toolResult, err = h.csvTools.TraceCSV(ctx, stringParams)
+	case "list_fbc_packages":
+		toolResult, err = h.catTools.ListFBCPackages(ctx, stringParams)
+	case "get_fbc_upgrade_graph":
+		toolResult, err = h.catTools.GetFBCUpgradeGraph(ctx, stringParams)
+	case "validate_fbc":
+		toolResult, err = h.catTools.ValidateFBC(ctx, stringParams)
 	default:
 		return &types.MCPResponse{
 			JSONRPC: "2.0",
@@ -146,32 +205,95 @@ func (h *MCPHandler) listTools() *types.MCPResponse {
 	result.WriteString("Available OLM MCP Tools:\n\n")
 
 	result.WriteString("ClusterServiceVersion Tools:\n")
-	result.WriteString("  - list_csvs: List ClusterServiceVersions in a namespace\n")
-	result.WriteString("    Parameters: namespace (optional, default: 'default')\n")
+	result.WriteString("  - list_csvs: List ClusterServiceVersions in a namespace with their OperatorGroup, hiding copied CSVs\n")
+	result.WriteString("    Parameters: namespace (optional, default: 'default'), include_copied (optional)\n")
 	result.WriteString("  - get_csv: Get detailed information about a specific ClusterServiceVersion\n")
-	result.WriteString("    Parameters: name (required), namespace (optional, default: 'default')\n\n")
+	result.WriteString("    Parameters: name (required), namespace (optional, default: 'default')\n")
+	result.WriteString("  - list_api_providers: Map each API to the operators that provide and require it\n")
+	result.WriteString("    Parameters: namespace (optional, default: all namespaces)\n")
+	result.WriteString("  - get_operator_permissions: Summarize an operator's RBAC and compare it with what OLM generated\n")
+	result.WriteString("    Parameters: name (required), namespace (optional, default: 'default')\n")
+	result.WriteString("  - diff_csvs: Semantic diff of two CSVs, including pending InstallPlan and catalog versions\n")
+	result.WriteString("    Parameters: from (required), to (required), namespace (optional, default: 'default')\n")
+	result.WriteString("  - get_dependency_graph: Show which installed operators depend on which, as text, DOT or Mermaid\n")
+	result.WriteString("    Parameters: format (optional, default: 'text'), operator (optional), namespace (optional, default: 'default')\n")
+	result.WriteString("  - trace_csv: Trace a CSV to its Subscription, InstallPlan, catalog and upgrade chain, or a Subscription to everything it produced\n")
+	result.WriteString("    Parameters: name (required), kind (optional: csv or subscription, default: csv), namespace (optional, default: 'default')\n\n")
 
 	result.WriteString("Subscription Tools:\n")
 	result.WriteString("  - list_subscriptions: List Subscriptions in a namespace\n")
 	result.WriteString("    Parameters: namespace (optional, default: 'default')\n")
 	result.WriteString("  - get_subscription: Get detailed information about a specific Subscription\n")
-	result.WriteString("    Parameters: name (required), namespace (optional, default: 'default')\n\n")
+	result.WriteString("    Parameters: name (required), namespace (optional, default: 'default')\n")
+	result.WriteString("  - update_subscription: Update a Subscription's channel or approval strategy (write)\n")
+	result.WriteString("    Parameters: name (required), namespace (optional, default: 'default'), channel (optional), approval (optional), dry_run (optional)\n")
+	result.WriteString("  - rollback_operator: Roll an operator back to its previous CSV (write)\n")
+	result.WriteString("    Parameters: name (required), namespace (optional, default: 'default'), target_csv (optional), dry_run (optional)\n")
+	result.WriteString("  - list_pending_upgrades: Report upgrades in progress, awaiting approval or available in the catalog\n")
+	result.WriteString("    Parameters: namespace (optional, default: all namespaces)\n")
+	result.WriteString("  - list_deprecations: List deprecated packages, channels and bundles in use, with replacement channels\n")
+	result.WriteString("    Parameters: namespace (optional, default: all namespaces)\n\n")
 
 	result.WriteString("CatalogSource Tools:\n")
 	result.WriteString("  - list_catalog_sources: List CatalogSources in a namespace\n")
 	result.WriteString("    Parameters: namespace (optional, default: 'olm')\n")
 	result.WriteString("  - get_catalog_source: Get detailed information about a specific CatalogSource\n")
-	result.WriteString("    Parameters: name (required), namespace (optional, default: 'olm')\n\n")
+	result.WriteString("    Parameters: name (required), namespace (optional, default: 'olm')\n")
+	result.WriteString("  - list_fbc_packages: List the packages and channels of a local File-Based Catalog\n")
+	result.WriteString("    Parameters: path (optional, relative to --catalog-dir), package (optional)\n")
+	result.WriteString("  - get_fbc_upgrade_graph: Render a channel's upgrade graph from a local File-Based Catalog\n")
+	result.WriteString("    Parameters: package (required), channel (optional, default: default channel), format (optional: text, dot, mermaid or all), path (optional, relative to --catalog-dir)\n")
+	result.WriteString("  - validate_fbc: Report problems in a local File-Based Catalog\n")
+	result.WriteString("    Parameters: package (optional), path (optional, relative to --catalog-dir)\n\n")
 
 	result.WriteString("InstallPlan Tools:\n")
 	result.WriteString("  - list_install_plans: List InstallPlans in a namespace\n")
 	result.WriteString("    Parameters: namespace (optional, default: 'default')\n")
-	result.WriteString("  - get_install_plan: Get detailed information about a specific InstallPlan\n")
+	result.WriteString("  - get_install_plan: Get detailed information about a specific InstallPlan and its steps\n")
+	result.WriteString("    Parameters: name (required), namespace (optional, default: 'default'), kind (optional), status (optional), show_manifests (optional)\n")
+	result.WriteString("  - approve_install_plan: Approve a pending InstallPlan (write)\n")
+	result.WriteString("    Parameters: name (required), namespace (optional, default: 'default'), dry_run (optional)\n\n")
+
+	result.WriteString("Diagnostic Tools:\n")
+	result.WriteString("  - diagnose_subscription: Walk a Subscription's install chain and rank the problems found\n")
+	result.WriteString("    Parameters: name (required), namespace (optional, default: 'default')\n")
+	result.WriteString("  - check_csv_health: Summarize a CSV's phase history, requirements, deployments and pods\n")
+	result.WriteString("    Parameters: name (required), namespace (optional, default: 'default')\n")
+	result.WriteString("  - check_catalog_health: Inspect a CatalogSource's registry pod, service and dependent Subscriptions\n")
+	result.WriteString("    Parameters: name (required), namespace (optional, default: 'olm')\n")
+	result.WriteString("  - get_olm_logs: Read OLM component logs filtered to a resource\n")
+	result.WriteString("    Parameters: namespace, subscription, csv, install_plan, component, since, tail, max_lines, olm_namespace (all optional)\n")
+	result.WriteString("  - get_events: List events about an OLM resource, deduplicated by reason\n")
+	result.WriteString("    Parameters: kind (required), name (required), namespace (optional, default: 'default')\n")
+	result.WriteString("  - explain_resolution_failure: Break a ResolutionFailed message into constraint violations with suggested fixes\n")
+	result.WriteString("    Parameters: name or message (one required), namespace (optional, default: 'default')\n")
+	result.WriteString("  - summarize_olm: Dashboard of OLM resource counts and health across the cluster\n")
+	result.WriteString("    Parameters: namespace (optional, default: all namespaces)\n")
+	result.WriteString("  - find_orphans: List stale InstallPlans, stuck CSVs, Subscriptions to deleted catalogs and empty OperatorGroups\n")
+	result.WriteString("    Parameters: namespace (optional, default: all namespaces), min_age (optional, default: 1h)\n")
+	result.WriteString("  - cleanup_orphans: Delete selected orphans reported by find_orphans (write)\n")
+	result.WriteString("    Parameters: targets (required), min_age (optional, default: 1h), dry_run (optional)\n")
+	result.WriteString("  - validate_operator_groups: Detect OperatorGroup conflicts and unsupported install modes cluster-wide\n")
+	result.WriteString("    Parameters: none\n")
+	result.WriteString("  - collect_olm_state: Write OLM objects, events, logs and catalog pod status to a tarball\n")
+	result.WriteString("    Parameters: namespace (optional, default: all namespaces), log_tail (optional, default: 5000)\n")
+	result.WriteString("  - get_install_timeline: Merge Subscription, InstallPlan and CSV timestamps into a timeline and show where time was spent\n")
+	result.WriteString("    Parameters: name (required), kind (optional, default: subscription), namespace (optional, default: 'default')\n")
+	result.WriteString("  - diagnose_bundle_unpack: Explain failed or hung bundle unpacking from unpack Jobs, pods and ConfigMaps\n")
 	result.WriteString("    Parameters: name (required), namespace (optional, default: 'default')\n\n")
 
+	result.WriteString("Write tools preview every change with a server-side dry-run and return a unified diff.\n")
+	result.WriteString("Pass dry_run=true to stop after the preview; real writes require --read-only=false.\n\n")
+
 	result.WriteString("General Tools:\n")
 	result.WriteString("  - list_tools: Show this help message\n")
 
+	if h.server.CatalogOnly {
+		result.WriteString(fmt.Sprintf("\nCatalog-only mode: only %s can be called.\n", strings.Join(types.CatalogOnlyTools, ", ")))
+	} else {
+		result.WriteString(fmt.Sprintf("\nOnly tools in the enabled toolsets (%s) can be called.\n", strings.Join(h.server.Toolsets, ", ")))
+	}
+
 	return &types.MCPResponse{
 		JSONRPC: "2.0",
 		Result: &types.MCPToolResult{
@@ -193,6 +315,9 @@ func StartServer(server *types.MCPServer) error {
 	logrus.Infof("Starting OLM MCP Server on port %d", server.Port)
 	logrus.Infof("Read-only mode: %t", server.ReadOnly)
 	logrus.Infof("Enabled toolsets: %v", server.Toolsets)
+	if server.CatalogOnly {
+		logrus.Infof("Catalog-only mode: serving %v for %s", types.CatalogOnlyTools, server.CatalogDir)
+	}
 
 	return http.ListenAndServe(addr, mux)
 }
@@ -0,0 +1,252 @@
+package tools
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/operator-framework/api/pkg/operators/v1alpha1"
+	"github.com/operator-framework/operator-lifecycle-manager/olmv0-mcp-server/pkg/types"
+)
+
+// apiKey identifies an API provided or required by a CSV.
+type apiKey struct {
+	Group   string
+	Version string
+	Kind    string
+	// Source is "CRD" or "APIService".
+	Source string
+}
+
+func (k apiKey) String() string {
+	return fmt.Sprintf("%s/%s, Kind=%s (%s)", k.Group, k.Version, k.Kind, k.Source)
+}
+
+// crdAPIKey derives a key from a CRD description, whose name is
+// "<plural>.<group>".
+func crdAPIKey(crd v1alpha1.CRDDescription) apiKey {
+	group := crd.Name
+	if i := strings.Index(crd.Name, "."); i >= 0 {
+		group = crd.Name[i+1:]
+	}
+	return apiKey{Group: group, Version: crd.Version, Kind: crd.Kind, Source: "CRD"}
+}
+
+func apiServiceAPIKey(api v1alpha1.APIServiceDescription) apiKey {
+	return apiKey{Group: api.Group, Version: api.Version, Kind: api.Kind, Source: "APIService"}
+}
+
+// apiUsers records which CSVs provide and require an API.
+type apiUsers struct {
+	Providers  []string
+	Dependents []string
+	// Unmet holds the requirement status of dependents for which OLM
+	// reports the API as not present.
+	Unmet []string
+}
+
+// buildAPIMap indexes the owned and required APIs of the given CSVs. Copied
+// CSVs are skipped since they only mirror the CSV in the operator's own
+// namespace.
+func buildAPIMap(csvs []v1alpha1.ClusterServiceVersion) map[apiKey]*apiUsers {
+	apis := map[apiKey]*apiUsers{}
+	entry := func(key apiKey) *apiUsers {
+		if apis[key] == nil {
+			apis[key] = &apiUsers{}
+		}
+		return apis[key]
+	}
+
+	for i := range csvs {
+		csv := &csvs[i]
+		if csv.IsCopied() {
+			continue
+		}
+		id := csv.Namespace + "/" + csv.Name
+
+		for _, crd := range csv.Spec.CustomResourceDefinitions.Owned {
+			users := entry(crdAPIKey(crd))
+			users.Providers = append(users.Providers, id)
+		}
+		for _, api := range csv.Spec.APIServiceDefinitions.Owned {
+			users := entry(apiServiceAPIKey(api))
+			users.Providers = append(users.Providers, id)
+		}
+
+		unmet := map[string]string{}
+		for _, req := range csv.Status.RequirementStatus {
+			if req.Status != v1alpha1.RequirementStatusReasonPresent {
+				unmet[req.Name] = string(req.Status)
+			}
+		}
+		for _, crd := range csv.Spec.CustomResourceDefinitions.Required {
+			users := entry(crdAPIKey(crd))
+			users.Dependents = append(users.Dependents, id)
+			if status, ok := unmet[crd.Name]; ok {
+				users.Unmet = append(users.Unmet, fmt.Sprintf("%s reports %s %s", id, crd.Name, status))
+			}
+		}
+		for _, api := range csv.Spec.APIServiceDefinitions.Required {
+			users := entry(apiServiceAPIKey(api))
+			users.Dependents = append(users.Dependents, id)
+			name := api.Version + "." + api.Group
+			if status, ok := unmet[name]; ok {
+				users.Unmet = append(users.Unmet, fmt.Sprintf("%s reports %s %s", id, name, status))
+			}
+		}
+	}
+	return apis
+}
+
+// csvPackage returns the package a CSV was installed from: its olm.package
+// property, its operator label, or failing both its name up to the version.
+func csvPackage(csv *v1alpha1.ClusterServiceVersion) string {
+	if pkg := csvPackageProperty(csv); pkg != "" {
+		return pkg
+	}
+	if pkg := csvPackageFromLabels(csv); pkg != "" {
+		return pkg
+	}
+	if i := strings.Index(csv.Name, ".v"); i > 0 {
+		return csv.Name[:i]
+	}
+	return csv.Name
+}
+
+// conflictingProviders groups the providers of an API by package, leaving
+// out CSVs that are being replaced or deleted. An upgrade in flight and one
+// package installed in several namespaces share the CRD by design; only
+// different packages fight over it.
+func conflictingProviders(providers []string, csvs map[string]*v1alpha1.ClusterServiceVersion) map[string][]string {
+	packages := map[string][]string{}
+	for _, id := range providers {
+		csv := csvs[id]
+		if csv == nil {
+			continue
+		}
+		switch csv.Status.Phase {
+		case v1alpha1.CSVPhaseReplacing, v1alpha1.CSVPhaseDeleting:
+			continue
+		}
+		pkg := csvPackage(csv)
+		packages[pkg] = append(packages[pkg], id)
+	}
+	return packages
+}
+
+// ListAPIProviders maps every API owned or required by a CSV to the
+// operators that provide and depend on it.
+func (t *CSVTools) ListAPIProviders(ctx context.Context, params map[string]string) (*types.MCPToolResult, error) {
+	// Providers are cluster-wide (CRDs are cluster-scoped), so unlike the
+	// list tools this defaults to every namespace.
+	namespace := params["namespace"]
+
+	csvs, err := t.server.OLMClient.ListClusterServiceVersions(ctx, namespace)
+	if err != nil {
+		return &types.MCPToolResult{
+			Content: []types.MCPContent{{
+				Type: "text",
+				Text: fmt.Sprintf("Error listing ClusterServiceVersions: %v", err),
+			}},
+			IsError: true,
+		}, nil
+	}
+
+	apis := buildAPIMap(csvs.Items)
+	byID := map[string]*v1alpha1.ClusterServiceVersion{}
+	for i := range csvs.Items {
+		byID[csvs.Items[i].Namespace+"/"+csvs.Items[i].Name] = &csvs.Items[i]
+	}
+	keys := make([]apiKey, 0, len(apis))
+	for key := range apis {
+		keys = append(keys, key)
+	}
+	sort.Slice(keys, func(i, j int) bool {
+		a, b := keys[i], keys[j]
+		if a.Group != b.Group {
+			return a.Group < b.Group
+		}
+		if a.Kind != b.Kind {
+			return a.Kind < b.Kind
+		}
+		return a.Version < b.Version
+	})
+
+	var result strings.Builder
+	scope := "all namespaces"
+	if namespace != "" {
+		scope = fmt.Sprintf("namespace '%s'", namespace)
+	}
+	result.WriteString(fmt.Sprintf("API providers in %s (copied CSVs excluded):\n\n", scope))
+
+	if len(keys) == 0 {
+		result.WriteString("No CSV owns or requires any API.\n")
+		return &types.MCPToolResult{
+			Content: []types.MCPContent{{
+				Type: "text",
+				Text: result.String(),
+			}},
+		}, nil
+	}
+
+	result.WriteString("GROUP\tVERSION\tKIND\tSOURCE\tPROVIDED BY\tREQUIRED BY\n")
+	var findings []finding
+	for _, key := range keys {
+		users := apis[key]
+		providers := uniqueStrings(users.Providers)
+		dependents := uniqueStrings(users.Dependents)
+
+		result.WriteString(fmt.Sprintf("%s\t%s\t%s\t%s\t%s\t%s\n",
+			key.Group,
+			key.Version,
+			key.Kind,
+			key.Source,
+			valueOrNone(strings.Join(providers, ",")),
+			valueOrNone(strings.Join(dependents, ",")),
+		))
+
+		if packages := conflictingProviders(providers, byID); len(packages) > 1 {
+			names := make([]string, 0, len(packages))
+			for pkg := range packages {
+				names = append(names, pkg)
+			}
+			sort.Strings(names)
+			evidence := []string{"each package's CSVs install and update the same cluster-wide CRD, and OLM refuses to install a second package providing the API in a namespace that already has one"}
+			for _, pkg := range names {
+				evidence = append(evidence, fmt.Sprintf("package %s: %s", pkg, strings.Join(packages[pkg], ", ")))
+			}
+			findings = append(findings, finding{
+				Severity: severityWarning,
+				Title:    fmt.Sprintf("%s is provided by %d packages", key, len(packages)),
+				Evidence: evidence,
+			})
+		}
+		if len(providers) == 0 && len(dependents) > 0 {
+			evidence := []string{fmt.Sprintf("required by %s", strings.Join(dependents, ", "))}
+			severity := severityInfo
+			if len(users.Unmet) > 0 {
+				severity = severityCritical
+				evidence = append(evidence, users.Unmet...)
+				evidence = append(evidence, "OLM keeps these CSVs in Pending until the API is available")
+			} else {
+				evidence = append(evidence, "the dependents' requirement status does not report it missing, so it was likely installed outside OLM")
+			}
+			findings = append(findings, finding{
+				Severity: severity,
+				Title:    fmt.Sprintf("%s is required but no operator provides it", key),
+				Evidence: evidence,
+			})
+		}
+	}
+
+	result.WriteString("\n")
+	writeFindings(&result, findings)
+
+	return &types.MCPToolResult{
+		Content: []types.MCPContent{{
+			Type: "text",
+			Text: result.String(),
+		}},
+	}, nil
+}
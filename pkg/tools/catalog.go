@@ -6,6 +6,7 @@ import (
 	"fmt"
 	"strings"
 
+	"github.com/operator-framework/api/pkg/operators/v1alpha1"
 	"github.com/operator-framework/operator-lifecycle-manager/olmv0-mcp-server/pkg/types"
 )
 
@@ -118,7 +119,10 @@ func (t *CatalogTools) GetCatalogSource(ctx context.Context, params map[string]s
 		result.WriteString(fmt.Sprintf("  Address: %s\n", catalog.Spec.Address))
 	}
 
-	result.WriteString("\nFull JSON representation:\n")
+	result.WriteString("\n")
+	writeEventsSection(ctx, &result, t.server, v1alpha1.CatalogSourceKind, namespace, name)
+
+	result.WriteString("Full JSON representation:\n")
 	result.WriteString("```json\n")
 	result.WriteString(string(jsonData))
 	result.WriteString("\n```\n")
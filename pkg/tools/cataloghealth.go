@@ -0,0 +1,348 @@
+package tools
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/operator-framework/api/pkg/operators/v1alpha1"
+	"github.com/operator-framework/operator-lifecycle-manager/olmv0-mcp-server/pkg/types"
+	corev1 "k8s.io/api/core/v1"
+	discoveryv1 "k8s.io/api/discovery/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// catalogSourceLabel is set by OLM on the registry pods it runs for a
+// CatalogSource.
+const catalogSourceLabel = "olm.catalogSource"
+
+// catalogConnectionState returns the last observed gRPC connection state,
+// which is unset until OLM first connects to the catalog.
+func catalogConnectionState(catalog *v1alpha1.CatalogSource) string {
+	if catalog.Status.GRPCConnectionState == nil {
+		return ""
+	}
+	return catalog.Status.GRPCConnectionState.LastObservedState
+}
+
+// CheckCatalogHealth inspects a CatalogSource together with its registry pod
+// and service, and reports whether it explains resolution failures in the
+// Subscriptions that depend on it.
+func (t *DiagnosticTools) CheckCatalogHealth(ctx context.Context, params map[string]string) (*types.MCPToolResult, error) {
+	namespace := params["namespace"]
+	name := params["name"]
+
+	if namespace == "" {
+		namespace = "olm"
+	}
+	if name == "" {
+		return &types.MCPToolResult{
+			Content: []types.MCPContent{{
+				Type: "text",
+				Text: "Error: 'name' parameter is required",
+			}},
+			IsError: true,
+		}, nil
+	}
+
+	catalog, err := t.server.OLMClient.GetCatalogSource(ctx, namespace, name)
+	if err != nil {
+		return &types.MCPToolResult{
+			Content: []types.MCPContent{{
+				Type: "text",
+				Text: fmt.Sprintf("Error getting CatalogSource '%s': %v", name, err),
+			}},
+			IsError: true,
+		}, nil
+	}
+
+	var result strings.Builder
+	var findings []finding
+	// unverified is set when the registry could not be inspected, so the
+	// verdict does not call the catalog healthy on missing evidence.
+	unverified := false
+
+	result.WriteString(fmt.Sprintf("Health of CatalogSource %s/%s\n\n", namespace, name))
+	result.WriteString("Status:\n")
+	result.WriteString(fmt.Sprintf("  Source Type: %s\n", catalog.Spec.SourceType))
+	if catalog.Spec.Image != "" {
+		result.WriteString(fmt.Sprintf("  Image: %s\n", catalog.Spec.Image))
+	}
+	if catalog.Spec.Address != "" {
+		result.WriteString(fmt.Sprintf("  Address: %s\n", catalog.Spec.Address))
+	}
+
+	state := catalogConnectionState(catalog)
+	result.WriteString(fmt.Sprintf("  Connection State: %s\n", valueOrNone(state)))
+	if conn := catalog.Status.GRPCConnectionState; conn != nil {
+		result.WriteString(fmt.Sprintf("  Last Connect: %s\n", formatTime(&conn.LastConnectTime)))
+	}
+	switch state {
+	case "READY":
+	case "TRANSIENT_FAILURE", "SHUTDOWN":
+		findings = append(findings, finding{
+			Severity: severityCritical,
+			Title:    fmt.Sprintf("OLM cannot connect to the catalog (state %s)", state),
+			Evidence: []string{"status.connectionState.lastObservedState=" + state},
+		})
+	default:
+		findings = append(findings, finding{
+			Severity: severityWarning,
+			Title:    fmt.Sprintf("Catalog connection is not ready (state %s)", valueOrNone(state)),
+			Evidence: []string{"OLM has not yet established a gRPC connection to the registry"},
+		})
+	}
+	if catalog.Status.Reason != "" || catalog.Status.Message != "" {
+		result.WriteString(fmt.Sprintf("  Reason: %s\n", catalog.Status.Reason))
+		result.WriteString(fmt.Sprintf("  Message: %s\n", catalog.Status.Message))
+		findings = append(findings, finding{
+			Severity: severityCritical,
+			Title:    fmt.Sprintf("CatalogSource reports %s", catalog.Status.Reason),
+			Evidence: []string{catalog.Status.Message},
+		})
+	}
+
+	if svc := catalog.Status.RegistryServiceStatus; svc != nil {
+		result.WriteString(fmt.Sprintf("  Registry Service: %s (%s, created %s)\n", svc.Address(), svc.Protocol, formatTime(&svc.CreatedAt)))
+	}
+
+	if catalog.Poll() {
+		interval := catalog.Spec.UpdateStrategy.Interval.Duration
+		result.WriteString(fmt.Sprintf("  Registry Poll Interval: %s\n", interval))
+		result.WriteString(fmt.Sprintf("  Latest Image Registry Poll: %s\n", formatTime(catalog.Status.LatestImageRegistryPoll)))
+		if poll := catalog.Status.LatestImageRegistryPoll; poll != nil && time.Since(poll.Time) > 2*interval {
+			findings = append(findings, finding{
+				Severity: severityWarning,
+				Title:    "Catalog image has not been polled for more than twice its poll interval",
+				Evidence: []string{fmt.Sprintf("last poll %s, interval %s", formatTime(poll), interval)},
+			})
+		}
+		if parseErr := catalog.Spec.UpdateStrategy.ParsingError; parseErr != "" {
+			findings = append(findings, finding{
+				Severity: severityWarning,
+				Title:    "Registry poll interval is invalid",
+				Evidence: []string{parseErr},
+			})
+		}
+	}
+	result.WriteString("\n")
+
+	// Registry pods
+	if catalog.Spec.SourceType == v1alpha1.SourceTypeGrpc && catalog.Spec.Image == "" {
+		result.WriteString("Registry pods: none expected; the catalog is served from spec.address\n\n")
+	} else {
+		pods, err := t.server.K8sClient.CoreV1().Pods(namespace).List(ctx, metav1.ListOptions{
+			LabelSelector: fmt.Sprintf("%s=%s", catalogSourceLabel, name),
+		})
+		switch {
+		case err != nil:
+			unverified = true
+			findings = append(findings, finding{
+				Severity: severityWarning,
+				Title:    "Could not list registry pods",
+				Evidence: []string{err.Error()},
+			})
+		case len(pods.Items) == 0:
+			result.WriteString("Registry pods: none found\n\n")
+			findings = append(findings, finding{
+				Severity: severityCritical,
+				Title:    "No registry pod is running for the catalog",
+				Evidence: []string{fmt.Sprintf("no pods with label %s=%s in namespace %s", catalogSourceLabel, name, namespace)},
+			})
+		default:
+			result.WriteString("Registry pods:\n")
+			result.WriteString("NAME\tPHASE\tREADY\tRESTARTS\tIMAGE\tIMAGE ID\n")
+			for _, pod := range pods.Items {
+				ready, restarts := 0, int32(0)
+				var imageIDs []string
+				for _, cs := range pod.Status.ContainerStatuses {
+					if cs.Ready {
+						ready++
+					}
+					restarts += cs.RestartCount
+					if cs.ImageID != "" {
+						imageIDs = append(imageIDs, cs.ImageID)
+					}
+				}
+				var images []string
+				for _, c := range pod.Spec.Containers {
+					images = append(images, c.Image)
+				}
+				result.WriteString(fmt.Sprintf("%s\t%s%s\t%d/%d\t%d\t%s\t%s\n",
+					pod.Name,
+					pod.Status.Phase,
+					podStateSuffix(pod),
+					ready,
+					len(pod.Spec.Containers),
+					restarts,
+					strings.Join(images, ","),
+					valueOrNone(strings.Join(imageIDs, ",")),
+				))
+				findings = append(findings, podFindings(pod)...)
+			}
+			result.WriteString("\n")
+		}
+	}
+
+	// Registry service
+	if svc := catalog.Status.RegistryServiceStatus; svc != nil && svc.ServiceName != "" {
+		serviceNamespace := svc.ServiceNamespace
+		if serviceNamespace == "" {
+			serviceNamespace = namespace
+		}
+		_, err := t.server.K8sClient.CoreV1().Services(serviceNamespace).Get(ctx, svc.ServiceName, metav1.GetOptions{})
+		switch {
+		case apierrors.IsNotFound(err):
+			findings = append(findings, finding{
+				Severity: severityCritical,
+				Title:    fmt.Sprintf("Registry service '%s' does not exist", svc.ServiceName),
+				Evidence: []string{"status.registryService points to a missing Service"},
+			})
+		case err != nil:
+			unverified = true
+			findings = append(findings, finding{
+				Severity: severityWarning,
+				Title:    fmt.Sprintf("Could not read registry service '%s'", svc.ServiceName),
+				Evidence: []string{err.Error()},
+			})
+		default:
+			ready, err := t.readyEndpoints(ctx, serviceNamespace, svc.ServiceName)
+			if err != nil {
+				unverified = true
+				findings = append(findings, finding{
+					Severity: severityWarning,
+					Title:    fmt.Sprintf("Could not read EndpointSlices of registry service '%s'", svc.ServiceName),
+					Evidence: []string{err.Error()},
+				})
+				break
+			}
+			result.WriteString(fmt.Sprintf("Registry service %s/%s: %d ready endpoint(s)\n\n", serviceNamespace, svc.ServiceName, ready))
+			if ready == 0 {
+				findings = append(findings, finding{
+					Severity: severityCritical,
+					Title:    fmt.Sprintf("Registry service '%s' has no ready endpoints", svc.ServiceName),
+					Evidence: []string{"the registry pod is not ready, so OLM cannot query the catalog"},
+				})
+			}
+		}
+	}
+
+	// Dependent Subscriptions
+	subscriptions, err := t.server.OLMClient.ListSubscriptions(ctx, "")
+	var dependents, failing []string
+	if err != nil {
+		findings = append(findings, finding{
+			Severity: severityInfo,
+			Title:    "Could not list Subscriptions across namespaces",
+			Evidence: []string{err.Error()},
+		})
+	} else {
+		for _, sub := range subscriptions.Items {
+			if sub.Spec == nil || sub.Spec.CatalogSource != name || sub.Spec.CatalogSourceNamespace != namespace {
+				continue
+			}
+			ref := fmt.Sprintf("%s/%s", sub.Namespace, sub.Name)
+			dependents = append(dependents, ref)
+			if reason := resolutionProblem(&sub); reason != "" {
+				failing = append(failing, fmt.Sprintf("%s (%s)", ref, reason))
+			}
+		}
+	}
+
+	result.WriteString(fmt.Sprintf("Dependent Subscriptions (%d):\n", len(dependents)))
+	if len(dependents) == 0 {
+		result.WriteString("  None\n")
+	}
+	for _, ref := range dependents {
+		result.WriteString(fmt.Sprintf("  - %s\n", ref))
+	}
+	result.WriteString("\n")
+
+	catalogBroken := false
+	for _, f := range findings {
+		if f.Severity == severityCritical {
+			catalogBroken = true
+			break
+		}
+	}
+
+	result.WriteString("Verdict:\n")
+	switch {
+	case catalogBroken && len(failing) > 0:
+		result.WriteString("  The catalog is unhealthy and is the likely cause of resolution failures in:\n")
+		for _, f := range failing {
+			result.WriteString(fmt.Sprintf("    - %s\n", f))
+		}
+	case catalogBroken:
+		result.WriteString("  The catalog is unhealthy. No dependent Subscription reports a resolution failure yet, but new installs and upgrades from it will fail.\n")
+	case unverified:
+		result.WriteString("  Unknown: the registry could not be fully inspected (see the warnings below), so the catalog is neither confirmed healthy nor ruled out as a cause.\n")
+		if len(failing) > 0 {
+			result.WriteString("  Dependent Subscriptions failing resolution:\n")
+		}
+		for _, f := range failing {
+			result.WriteString(fmt.Sprintf("    - %s\n", f))
+		}
+	case len(failing) > 0:
+		result.WriteString("  The catalog is healthy, so it is not the cause of resolution failures in:\n")
+		for _, f := range failing {
+			result.WriteString(fmt.Sprintf("    - %s\n", f))
+		}
+		result.WriteString("  Use diagnose_subscription on those Subscriptions to find the real cause.\n")
+	default:
+		result.WriteString("  The catalog is healthy and no dependent Subscription reports a resolution failure.\n")
+	}
+	result.WriteString("\n")
+
+	writeFindings(&result, findings)
+
+	return &types.MCPToolResult{
+		Content: []types.MCPContent{{
+			Type: "text",
+			Text: result.String(),
+		}},
+	}, nil
+}
+
+// readyEndpoints counts the ready endpoints backing a service across its
+// EndpointSlices.
+func (t *DiagnosticTools) readyEndpoints(ctx context.Context, namespace, service string) (int, error) {
+	slices, err := t.server.K8sClient.DiscoveryV1().EndpointSlices(namespace).List(ctx, metav1.ListOptions{
+		LabelSelector: fmt.Sprintf("%s=%s", discoveryv1.LabelServiceName, service),
+	})
+	if err != nil {
+		return 0, err
+	}
+	ready := 0
+	for _, slice := range slices.Items {
+		for _, endpoint := range slice.Endpoints {
+			if endpoint.Conditions.Ready == nil || *endpoint.Conditions.Ready {
+				ready++
+			}
+		}
+	}
+	return ready, nil
+}
+
+// resolutionProblem returns the reason a Subscription cannot resolve, or an
+// empty string if it reports no resolution problem.
+func resolutionProblem(sub *v1alpha1.Subscription) string {
+	for _, condType := range []v1alpha1.SubscriptionConditionType{
+		v1alpha1.SubscriptionResolutionFailed,
+		v1alpha1.SubscriptionCatalogSourcesUnhealthy,
+	} {
+		if cond := sub.Status.GetCondition(condType); cond.Status == corev1.ConditionTrue {
+			return string(condType)
+		}
+	}
+	return ""
+}
+
+func valueOrNone(value string) string {
+	if value == "" {
+		return "<none>"
+	}
+	return value
+}
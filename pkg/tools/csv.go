@@ -6,6 +6,8 @@ import (
 	"fmt"
 	"strings"
 
+	operatorsv1 "github.com/operator-framework/api/pkg/operators/v1"
+	"github.com/operator-framework/api/pkg/operators/v1alpha1"
 	"github.com/operator-framework/operator-lifecycle-manager/olmv0-mcp-server/pkg/types"
 )
 
@@ -17,6 +19,32 @@ func NewCSVTools(server *types.MCPServer) *CSVTools {
 	return &CSVTools{server: server}
 }
 
+// csvOperatorGroup names the OperatorGroup that owns a CSV as namespace/name.
+// OLM records it in the olm.operatorGroup annotations, which copied CSVs
+// keep; otherwise the single OperatorGroup in the CSV's namespace is used.
+func csvOperatorGroup(csv *v1alpha1.ClusterServiceVersion, groupsByNamespace map[string][]string) string {
+	annotations := csv.GetAnnotations()
+	if name := annotations[operatorsv1.OperatorGroupAnnotationKey]; name != "" {
+		namespace := annotations[operatorsv1.OperatorGroupNamespaceAnnotationKey]
+		if namespace == "" {
+			namespace = csv.Namespace
+		}
+		return namespace + "/" + name
+	}
+	namespace := csv.Namespace
+	if origin := csv.GetLabels()[v1alpha1.CopiedLabelKey]; origin != "" {
+		namespace = origin
+	}
+	switch groups := groupsByNamespace[namespace]; len(groups) {
+	case 0:
+		return "<none>"
+	case 1:
+		return namespace + "/" + groups[0]
+	default:
+		return fmt.Sprintf("<%d in %s>", len(groups), namespace)
+	}
+}
+
 func (t *CSVTools) ListCSVs(ctx context.Context, params map[string]string) (*types.MCPToolResult, error) {
 	namespace := params["namespace"]
 	if namespace == "" {
@@ -34,23 +62,59 @@ func (t *CSVTools) ListCSVs(ctx context.Context, params map[string]string) (*typ
 		}, nil
 	}
 
+	includeCopied := boolParam(params, "include_copied")
+
+	// OperatorGroups are only used to fill in CSVs without the
+	// olm.operatorGroup annotation, so a failed list is not fatal.
+	groupsByNamespace := map[string][]string{}
+	if groups, err := t.server.OLMClient.ListOperatorGroups(ctx, ""); err == nil {
+		for _, og := range groups.Items {
+			groupsByNamespace[og.Namespace] = append(groupsByNamespace[og.Namespace], og.Name)
+		}
+	}
+
 	var result strings.Builder
 	result.WriteString(fmt.Sprintf("ClusterServiceVersions in namespace '%s':\n\n", namespace))
 
-	if len(csvs.Items) == 0 {
+	var shown []*v1alpha1.ClusterServiceVersion
+	copied := 0
+	for i := range csvs.Items {
+		csv := &csvs.Items[i]
+		if csv.IsCopied() {
+			copied++
+			if !includeCopied {
+				continue
+			}
+		}
+		shown = append(shown, csv)
+	}
+
+	if len(shown) == 0 {
 		result.WriteString("No ClusterServiceVersions found.\n")
 	} else {
-		result.WriteString("NAME\tNAMESPACE\tPHASE\tVERSION\tREPLACES\n")
-		for _, csv := range csvs.Items {
-			result.WriteString(fmt.Sprintf("%s\t%s\t%s\t%s\t%s\n",
+		result.WriteString("NAME\tNAMESPACE\tPHASE\tVERSION\tREPLACES\tOPERATORGROUP")
+		if includeCopied {
+			result.WriteString("\tCOPIED FROM")
+		}
+		result.WriteString("\n")
+		for _, csv := range shown {
+			result.WriteString(fmt.Sprintf("%s\t%s\t%s\t%s\t%s\t%s",
 				csv.Name,
 				csv.Namespace,
 				csv.Status.Phase,
 				csv.Spec.Version.String(),
 				csv.Spec.Replaces,
+				csvOperatorGroup(csv, groupsByNamespace),
 			))
+			if includeCopied {
+				result.WriteString("\t" + valueOrNone(csv.GetLabels()[v1alpha1.CopiedLabelKey]))
+			}
+			result.WriteString("\n")
 		}
 	}
+	if copied > 0 && !includeCopied {
+		result.WriteString(fmt.Sprintf("\n%d copied CSV(s) hidden; OLM copies CSVs into every namespace their OperatorGroup targets. Set include_copied=true to show them.\n", copied))
+	}
 
 	return &types.MCPToolResult{
 		Content: []types.MCPContent{{
@@ -110,6 +174,8 @@ func (t *CSVTools) GetCSV(ctx context.Context, params map[string]string) (*types
 	result.WriteString(fmt.Sprintf("  Display Name: %s\n", csv.Spec.DisplayName))
 	result.WriteString(fmt.Sprintf("  Description: %s\n\n", csv.Spec.Description))
 
+	writeEventsSection(ctx, &result, t.server, v1alpha1.ClusterServiceVersionKind, namespace, name)
+
 	result.WriteString("Full JSON representation:\n")
 	result.WriteString("```json\n")
 	result.WriteString(string(jsonData))
@@ -0,0 +1,359 @@
+package tools
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/operator-framework/api/pkg/operators/v1alpha1"
+	"github.com/operator-framework/operator-lifecycle-manager/olmv0-mcp-server/pkg/types"
+)
+
+const skipRangeAnnotation = "olm.skipRange"
+
+// csvSnapshot holds the parts of a CSV that diff_csvs compares. Catalog
+// bundles only expose a CSV description through the package server, so
+// fields they lack are nil and reported as unknown.
+type csvSnapshot struct {
+	Source         string
+	Name           string
+	Version        string
+	Replaces       *string
+	Skips          []string
+	SkipRange      string
+	OwnedCRDs      map[string]string
+	RequiredCRDs   map[string]string
+	OwnedAPIs      map[string]bool
+	Permissions    map[string]bool
+	Images         map[string]string
+	RelatedImages  map[string]bool
+	InstallModes   map[string]bool
+	MinKubeVersion string
+}
+
+func snapshotFromCSV(source string, csv *v1alpha1.ClusterServiceVersion) *csvSnapshot {
+	s := &csvSnapshot{
+		Source:         source,
+		Name:           csv.Name,
+		Version:        csvVersion(csv),
+		Replaces:       &csv.Spec.Replaces,
+		Skips:          csv.Spec.Skips,
+		SkipRange:      csv.GetAnnotations()[skipRangeAnnotation],
+		OwnedCRDs:      map[string]string{},
+		RequiredCRDs:   map[string]string{},
+		OwnedAPIs:      map[string]bool{},
+		Permissions:    permissionSet(csv.Spec.InstallStrategy.StrategySpec),
+		Images:         map[string]string{},
+		RelatedImages:  map[string]bool{},
+		InstallModes:   map[string]bool{},
+		MinKubeVersion: csv.Spec.MinKubeVersion,
+	}
+	for _, crd := range csv.Spec.CustomResourceDefinitions.Owned {
+		s.OwnedCRDs[crd.Name] = crd.Version
+	}
+	for _, crd := range csv.Spec.CustomResourceDefinitions.Required {
+		s.RequiredCRDs[crd.Name] = crd.Version
+	}
+	for _, api := range csv.Spec.APIServiceDefinitions.Owned {
+		s.OwnedAPIs[apiServiceAPIKey(api).String()] = true
+	}
+	for _, deployment := range csv.Spec.InstallStrategy.StrategySpec.DeploymentSpecs {
+		for _, container := range deployment.Spec.Template.Spec.InitContainers {
+			s.Images[fmt.Sprintf("%s/%s (init)", deployment.Name, container.Name)] = container.Image
+		}
+		for _, container := range deployment.Spec.Template.Spec.Containers {
+			s.Images[fmt.Sprintf("%s/%s", deployment.Name, container.Name)] = container.Image
+		}
+	}
+	for _, image := range csv.Spec.RelatedImages {
+		s.RelatedImages[image.Image] = true
+	}
+	for _, mode := range csv.Spec.InstallModes {
+		s.InstallModes[string(mode.Type)] = mode.Supported
+	}
+	return s
+}
+
+func snapshotFromCatalog(source string, channel *types.PackageChannel) *csvSnapshot {
+	desc := channel.CurrentCSVDesc
+	s := &csvSnapshot{
+		Source:         source,
+		Name:           channel.CurrentCSV,
+		Version:        valueOrNone(desc.Version),
+		SkipRange:      desc.Annotations[skipRangeAnnotation],
+		OwnedCRDs:      map[string]string{},
+		RequiredCRDs:   map[string]string{},
+		OwnedAPIs:      map[string]bool{},
+		RelatedImages:  map[string]bool{},
+		InstallModes:   map[string]bool{},
+		MinKubeVersion: desc.MinKubeVersion,
+	}
+	for _, crd := range desc.CustomResourceDefinitions.Owned {
+		s.OwnedCRDs[crd.Name] = crd.Version
+	}
+	for _, crd := range desc.CustomResourceDefinitions.Required {
+		s.RequiredCRDs[crd.Name] = crd.Version
+	}
+	for _, api := range desc.APIServiceDefinitions.Owned {
+		s.OwnedAPIs[apiServiceAPIKey(api).String()] = true
+	}
+	for _, image := range desc.RelatedImages {
+		s.RelatedImages[image] = true
+	}
+	for _, mode := range desc.InstallModes {
+		s.InstallModes[string(mode.Type)] = mode.Supported
+	}
+	return s
+}
+
+// resolveCSVReference loads the CSV a diff_csvs reference names:
+//
+//	name or namespace/name                  an installed CSV
+//	installplan:[namespace/]plan[/csv]      the CSV an InstallPlan will create
+//	catalog:package/channel                 a channel head in the catalog
+//	catalog:source/package/channel          the same, from a specific CatalogSource
+func resolveCSVReference(ctx context.Context, server *types.MCPServer, resolver *manifestResolver, namespace, ref string) (*csvSnapshot, error) {
+	kind, value, found := strings.Cut(ref, ":")
+	if !found {
+		kind, value = "csv", ref
+	}
+	parts := strings.Split(value, "/")
+
+	switch kind {
+	case "csv":
+		ns, name := namespace, value
+		if len(parts) == 2 {
+			ns, name = parts[0], parts[1]
+		}
+		csv, err := server.OLMClient.GetClusterServiceVersion(ctx, ns, name)
+		if err != nil {
+			return nil, fmt.Errorf("error getting ClusterServiceVersion '%s' in namespace '%s': %v", name, ns, err)
+		}
+		return snapshotFromCSV(fmt.Sprintf("installed CSV %s/%s", ns, name), csv), nil
+
+	case "installplan":
+		ns, name, csvName := namespace, parts[0], ""
+		switch len(parts) {
+		case 2:
+			ns, name = parts[0], parts[1]
+		case 3:
+			ns, name, csvName = parts[0], parts[1], parts[2]
+		}
+		installPlan, err := server.OLMClient.GetInstallPlan(ctx, ns, name)
+		if err != nil {
+			return nil, fmt.Errorf("error getting InstallPlan '%s' in namespace '%s': %v", name, ns, err)
+		}
+		if csvName == "" {
+			if len(installPlan.Spec.ClusterServiceVersionNames) != 1 {
+				return nil, fmt.Errorf("InstallPlan '%s' installs %d CSVs (%s); use installplan:%s/%s/<csv>",
+					name, len(installPlan.Spec.ClusterServiceVersionNames), strings.Join(installPlan.Spec.ClusterServiceVersionNames, ", "), ns, name)
+			}
+			csvName = installPlan.Spec.ClusterServiceVersionNames[0]
+		}
+		csv, err := resolver.resolvePlanCSV(ctx, installPlan, csvName)
+		if err != nil {
+			return nil, err
+		}
+		return snapshotFromCSV(fmt.Sprintf("CSV %s from InstallPlan %s/%s", csvName, ns, name), csv), nil
+
+	case "catalog":
+		var source, pkgName, channelName string
+		switch len(parts) {
+		case 2:
+			pkgName, channelName = parts[0], parts[1]
+		case 3:
+			source, pkgName, channelName = parts[0], parts[1], parts[2]
+		default:
+			return nil, fmt.Errorf("catalog references take the form catalog:[source/]package/channel, got %q", ref)
+		}
+		packages, err := server.OLMClient.ListPackageManifests(ctx, namespace)
+		if err != nil {
+			return nil, fmt.Errorf("error listing package manifests: %v", err)
+		}
+		var matches []*types.PackageManifest
+		for i := range packages.Items {
+			pkg := &packages.Items[i]
+			if pkg.Status.PackageName == pkgName && (source == "" || pkg.Status.CatalogSource == source) {
+				matches = append(matches, pkg)
+			}
+		}
+		switch len(matches) {
+		case 0:
+			return nil, fmt.Errorf("package '%s' not found in catalogs visible to namespace '%s'", pkgName, namespace)
+		case 1:
+		default:
+			var sources []string
+			for _, pkg := range matches {
+				sources = append(sources, pkg.Status.CatalogSource)
+			}
+			return nil, fmt.Errorf("package '%s' is served by several catalogs (%s); use catalog:<source>/%s/%s", pkgName, strings.Join(sources, ", "), pkgName, channelName)
+		}
+		channel := matches[0].GetChannel(channelName)
+		if channel == nil {
+			return nil, fmt.Errorf("package '%s' has no channel '%s'", pkgName, channelName)
+		}
+		return snapshotFromCatalog(fmt.Sprintf("head of channel %s of package %s in catalog %s/%s",
+			channelName, pkgName, matches[0].Status.CatalogSourceNamespace, matches[0].Status.CatalogSource), channel), nil
+	}
+
+	return nil, fmt.Errorf("unknown reference type %q; use a CSV name, installplan:... or catalog:...", kind)
+}
+
+// writeMapDiff reports added, removed and changed keys between two maps.
+func writeMapDiff(result *strings.Builder, title string, from, to map[string]string) {
+	var lines []string
+	for key, value := range to {
+		old, ok := from[key]
+		switch {
+		case !ok:
+			lines = append(lines, fmt.Sprintf("  + %s: %s", key, value))
+		case old != value:
+			lines = append(lines, fmt.Sprintf("  ~ %s: %s -> %s", key, old, value))
+		}
+	}
+	for key, value := range from {
+		if _, ok := to[key]; !ok {
+			lines = append(lines, fmt.Sprintf("  - %s: %s", key, value))
+		}
+	}
+	writeDiffSection(result, title, lines)
+}
+
+// writeSetDiff reports entries added to and removed from a set. A nil set
+// means the information is not available for that side.
+func writeSetDiff(result *strings.Builder, title string, from, to map[string]bool) {
+	if from == nil || to == nil {
+		result.WriteString(fmt.Sprintf("%s: unknown (not published for catalog bundles)\n", title))
+		return
+	}
+	var lines []string
+	for _, entry := range setDifference(to, from) {
+		lines = append(lines, "  + "+entry)
+	}
+	for _, entry := range setDifference(from, to) {
+		lines = append(lines, "  - "+entry)
+	}
+	writeDiffSection(result, title, lines)
+}
+
+func writeDiffSection(result *strings.Builder, title string, lines []string) {
+	if len(lines) == 0 {
+		result.WriteString(fmt.Sprintf("%s: unchanged\n", title))
+		return
+	}
+	sort.SliceStable(lines, func(i, j int) bool {
+		return lines[i][4:] < lines[j][4:]
+	})
+	result.WriteString(fmt.Sprintf("%s:\n", title))
+	for _, line := range lines {
+		result.WriteString(line + "\n")
+	}
+}
+
+func writeValueDiff(result *strings.Builder, title, from, to string) {
+	if from == to {
+		result.WriteString(fmt.Sprintf("%s: %s (unchanged)\n", title, valueOrNone(from)))
+		return
+	}
+	result.WriteString(fmt.Sprintf("%s: %s -> %s\n", title, valueOrNone(from), valueOrNone(to)))
+}
+
+func installModeMap(modes map[string]bool) map[string]string {
+	out := map[string]string{}
+	for mode, supported := range modes {
+		out[mode] = fmt.Sprintf("supported=%t", supported)
+	}
+	return out
+}
+
+func skipsSet(skips []string) map[string]bool {
+	set := map[string]bool{}
+	for _, skip := range skips {
+		set[skip] = true
+	}
+	return set
+}
+
+// DiffCSVs produces a semantic diff of two CSVs, each of which may be
+// installed, pending in an InstallPlan, or a catalog channel head.
+func (t *CSVTools) DiffCSVs(ctx context.Context, params map[string]string) (*types.MCPToolResult, error) {
+	namespace := params["namespace"]
+	fromRef := params["from"]
+	toRef := params["to"]
+
+	if namespace == "" {
+		namespace = "default"
+	}
+	if fromRef == "" || toRef == "" {
+		return &types.MCPToolResult{
+			Content: []types.MCPContent{{
+				Type: "text",
+				Text: "Error: 'from' and 'to' parameters are required",
+			}},
+			IsError: true,
+		}, nil
+	}
+
+	resolver := newManifestResolver(t.server)
+	from, err := resolveCSVReference(ctx, t.server, resolver, namespace, fromRef)
+	if err == nil {
+		var to *csvSnapshot
+		to, err = resolveCSVReference(ctx, t.server, resolver, namespace, toRef)
+		if err == nil {
+			return &types.MCPToolResult{
+				Content: []types.MCPContent{{
+					Type: "text",
+					Text: renderCSVDiff(from, to),
+				}},
+			}, nil
+		}
+	}
+	return &types.MCPToolResult{
+		Content: []types.MCPContent{{
+			Type: "text",
+			Text: fmt.Sprintf("Error resolving CSV reference: %v", err),
+		}},
+		IsError: true,
+	}, nil
+}
+
+func renderCSVDiff(from, to *csvSnapshot) string {
+	var result strings.Builder
+	result.WriteString(fmt.Sprintf("CSV diff: %s -> %s\n", from.Name, to.Name))
+	result.WriteString(fmt.Sprintf("  From: %s\n", from.Source))
+	result.WriteString(fmt.Sprintf("  To:   %s\n\n", to.Source))
+
+	writeValueDiff(&result, "Version", from.Version, to.Version)
+	if from.Replaces == nil || to.Replaces == nil {
+		result.WriteString("Replaces: unknown (not published for catalog bundles)\n")
+		result.WriteString("Skips: unknown (not published for catalog bundles)\n")
+	} else {
+		writeValueDiff(&result, "Replaces", *from.Replaces, *to.Replaces)
+		writeSetDiff(&result, "Skips", skipsSet(from.Skips), skipsSet(to.Skips))
+		if *to.Replaces != from.Name && !containsString(to.Skips, from.Name) && to.SkipRange == "" {
+			result.WriteString(fmt.Sprintf("  Note: %s neither replaces nor skips %s, so OLM has no direct upgrade edge between them\n", to.Name, from.Name))
+		}
+	}
+	writeValueDiff(&result, "Skip range", from.SkipRange, to.SkipRange)
+	writeValueDiff(&result, "Min Kubernetes version", from.MinKubeVersion, to.MinKubeVersion)
+	writeMapDiff(&result, "Install modes", installModeMap(from.InstallModes), installModeMap(to.InstallModes))
+
+	result.WriteString("\n")
+	writeMapDiff(&result, "Owned CRDs (name: version)", from.OwnedCRDs, to.OwnedCRDs)
+	writeMapDiff(&result, "Required CRDs (name: version)", from.RequiredCRDs, to.RequiredCRDs)
+	writeSetDiff(&result, "Owned APIServices", from.OwnedAPIs, to.OwnedAPIs)
+
+	result.WriteString("\n")
+	writeSetDiff(&result, "Permissions", from.Permissions, to.Permissions)
+
+	result.WriteString("\n")
+	if from.Images == nil || to.Images == nil {
+		result.WriteString("Deployment images: unknown (not published for catalog bundles)\n")
+	} else {
+		writeMapDiff(&result, "Deployment images (deployment/container: image)", from.Images, to.Images)
+	}
+	writeSetDiff(&result, "Related images", from.RelatedImages, to.RelatedImages)
+
+	return result.String()
+}
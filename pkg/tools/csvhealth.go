@@ -0,0 +1,151 @@
+package tools
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/operator-framework/api/pkg/operators/v1alpha1"
+	"github.com/operator-framework/operator-lifecycle-manager/olmv0-mcp-server/pkg/types"
+	corev1 "k8s.io/api/core/v1"
+)
+
+// CheckCSVHealth summarizes a CSV's phase history, requirements and the
+// state of the deployments and pods it installs.
+func (t *DiagnosticTools) CheckCSVHealth(ctx context.Context, params map[string]string) (*types.MCPToolResult, error) {
+	namespace := params["namespace"]
+	name := params["name"]
+
+	if namespace == "" {
+		namespace = "default"
+	}
+	if name == "" {
+		return &types.MCPToolResult{
+			Content: []types.MCPContent{{
+				Type: "text",
+				Text: "Error: 'name' parameter is required",
+			}},
+			IsError: true,
+		}, nil
+	}
+
+	csv, err := t.server.OLMClient.GetClusterServiceVersion(ctx, namespace, name)
+	if err != nil {
+		return &types.MCPToolResult{
+			Content: []types.MCPContent{{
+				Type: "text",
+				Text: fmt.Sprintf("Error getting ClusterServiceVersion '%s': %v", name, err),
+			}},
+			IsError: true,
+		}, nil
+	}
+
+	findings := csvFindings(csv)
+	deployments := inspectCSVDeployments(ctx, t.server.K8sClient, csv)
+	findings = append(findings, deploymentFindings(fmt.Sprintf("CSV '%s'", name), deployments)...)
+
+	unmet := 0
+	for _, req := range csv.Status.RequirementStatus {
+		if req.Status != v1alpha1.RequirementStatusReasonPresent {
+			unmet++
+		}
+	}
+
+	health := "Healthy"
+	for _, f := range findings {
+		if f.Severity == severityCritical {
+			health = "Unhealthy"
+			break
+		}
+		if f.Severity == severityWarning {
+			health = "Degraded"
+		}
+	}
+
+	var result strings.Builder
+	result.WriteString(fmt.Sprintf("Health of ClusterServiceVersion %s/%s\n\n", namespace, name))
+	result.WriteString("Summary:\n")
+	result.WriteString(fmt.Sprintf("  Health: %s\n", health))
+	result.WriteString(fmt.Sprintf("  Phase: %s (%s)\n", phaseOrNone(csv.Status.Phase), csv.Status.Reason))
+	if csv.Status.Message != "" {
+		result.WriteString(fmt.Sprintf("  Message: %s\n", csv.Status.Message))
+	}
+	result.WriteString(fmt.Sprintf("  Last phase transition: %s\n", formatTime(csv.Status.LastTransitionTime)))
+	result.WriteString(fmt.Sprintf("  Requirements: %d of %d met\n", len(csv.Status.RequirementStatus)-unmet, len(csv.Status.RequirementStatus)))
+	if len(deployments) > 0 {
+		result.WriteString(fmt.Sprintf("  Deployments: %s\n", summarizeDeployments(deployments)))
+	}
+	result.WriteString("\n")
+
+	if len(csv.Status.Conditions) > 0 {
+		result.WriteString("Phase history (oldest first):\n")
+		result.WriteString("TIME\tPHASE\tREASON\tMESSAGE\n")
+		for _, cond := range csv.Status.Conditions {
+			result.WriteString(fmt.Sprintf("%s\t%s\t%s\t%s\n",
+				formatTime(cond.LastTransitionTime),
+				phaseOrNone(cond.Phase),
+				cond.Reason,
+				cond.Message,
+			))
+		}
+		result.WriteString("\n")
+	}
+
+	if unmet > 0 {
+		result.WriteString("Unmet requirements:\n")
+		for _, req := range csv.Status.RequirementStatus {
+			if req.Status == v1alpha1.RequirementStatusReasonPresent {
+				continue
+			}
+			result.WriteString(fmt.Sprintf("  - %s %s (%s/%s): %s", req.Kind, req.Name, req.Group, req.Version, req.Status))
+			if req.Message != "" {
+				result.WriteString(fmt.Sprintf(" - %s", req.Message))
+			}
+			result.WriteString("\n")
+			for _, dep := range req.Dependents {
+				if dep.Status != v1alpha1.DependentStatusReasonSatisfied {
+					result.WriteString(fmt.Sprintf("      dependent %s %s: %s\n", dep.Kind, dep.Status, dep.Message))
+				}
+			}
+		}
+		result.WriteString("\n")
+	}
+
+	if len(deployments) > 0 {
+		result.WriteString("Pods:\n")
+		for _, status := range deployments {
+			for _, pod := range status.Pods {
+				ready, restarts := 0, int32(0)
+				for _, cs := range pod.Status.ContainerStatuses {
+					if cs.Ready {
+						ready++
+					}
+					restarts += cs.RestartCount
+				}
+				result.WriteString(fmt.Sprintf("  - %s (deployment %s): %s, ready %d/%d, restarts %d%s\n",
+					pod.Name, status.Name, pod.Status.Phase, ready, len(pod.Spec.Containers), restarts, podStateSuffix(pod)))
+			}
+		}
+		result.WriteString("\n")
+	}
+
+	writeFindings(&result, findings)
+
+	return &types.MCPToolResult{
+		Content: []types.MCPContent{{
+			Type: "text",
+			Text: result.String(),
+		}},
+	}, nil
+}
+
+// podStateSuffix names the first waiting reason among a pod's containers, if
+// any, e.g. ", CrashLoopBackOff".
+func podStateSuffix(pod corev1.Pod) string {
+	for _, cs := range pod.Status.ContainerStatuses {
+		if cs.State.Waiting != nil && cs.State.Waiting.Reason != "" {
+			return ", " + cs.State.Waiting.Reason
+		}
+	}
+	return ""
+}
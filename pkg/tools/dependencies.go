@@ -0,0 +1,386 @@
+package tools
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/operator-framework/api/pkg/operators/v1alpha1"
+	"github.com/operator-framework/operator-lifecycle-manager/olmv0-mcp-server/pkg/types"
+)
+
+// propertiesAnnotation holds the bundle properties OLM projects onto a CSV
+// when it installs a bundle.
+const propertiesAnnotation = "operatorframework.io/properties"
+
+type bundleProperty struct {
+	Type  string          `json:"type"`
+	Value json.RawMessage `json:"value"`
+}
+
+// csvProperties decodes the bundle properties projected onto a CSV.
+// Malformed or missing annotations yield no properties.
+func csvProperties(csv *v1alpha1.ClusterServiceVersion) []bundleProperty {
+	var properties struct {
+		Properties []bundleProperty `json:"properties"`
+	}
+	if value := csv.GetAnnotations()[propertiesAnnotation]; value != "" {
+		_ = json.Unmarshal([]byte(value), &properties)
+	}
+	return properties.Properties
+}
+
+// csvPackageProperty returns the package in a CSV's olm.package property.
+func csvPackageProperty(csv *v1alpha1.ClusterServiceVersion) string {
+	for _, property := range csvProperties(csv) {
+		if property.Type != "olm.package" {
+			continue
+		}
+		var pkg struct {
+			PackageName string `json:"packageName"`
+		}
+		if json.Unmarshal(property.Value, &pkg) == nil && pkg.PackageName != "" {
+			return pkg.PackageName
+		}
+	}
+	return ""
+}
+
+// dependencyEdge is a dependency of one operator on another, or on nothing
+// when no installed operator satisfies it.
+type dependencyEdge struct {
+	From   string
+	To     string
+	Reason string
+}
+
+// dependencyGraph is a graph of installed operators keyed by
+// "namespace/csv".
+type dependencyGraph struct {
+	Nodes    []string
+	Packages map[string]string
+	Edges    []dependencyEdge
+}
+
+// buildDependencyGraph links CSVs that require an API or package to the CSVs
+// that provide it. Requirements come from required CRDs and APIServices and
+// from the olm.gvk.required and olm.package.required bundle properties.
+func buildDependencyGraph(csvs []v1alpha1.ClusterServiceVersion, subscriptions []v1alpha1.Subscription) *dependencyGraph {
+	graph := &dependencyGraph{Packages: map[string]string{}}
+
+	for _, sub := range subscriptions {
+		if sub.Status.InstalledCSV != "" {
+			graph.Packages[sub.Namespace+"/"+sub.Status.InstalledCSV] = sub.Spec.Package
+		}
+	}
+
+	apis := buildAPIMap(csvs)
+	gvkProviders := map[string][]string{}
+	for key, users := range apis {
+		gvk := fmt.Sprintf("%s/%s, Kind=%s", key.Group, key.Version, key.Kind)
+		gvkProviders[gvk] = append(gvkProviders[gvk], users.Providers...)
+	}
+
+	var installed []*v1alpha1.ClusterServiceVersion
+	for i := range csvs {
+		csv := &csvs[i]
+		if csv.IsCopied() {
+			continue
+		}
+		id := csv.Namespace + "/" + csv.Name
+		installed = append(installed, csv)
+		graph.Nodes = append(graph.Nodes, id)
+		if pkg := csvPackageProperty(csv); pkg != "" {
+			graph.Packages[id] = pkg
+		}
+	}
+
+	// Subscriptions can name CSVs that are gone or not created yet; only
+	// CSVs in the graph can provide a package.
+	nodes := map[string]bool{}
+	for _, id := range graph.Nodes {
+		nodes[id] = true
+	}
+	packageProviders := map[string][]string{}
+	for id, pkg := range graph.Packages {
+		if !nodes[id] {
+			delete(graph.Packages, id)
+			continue
+		}
+		packageProviders[pkg] = append(packageProviders[pkg], id)
+	}
+
+	seen := map[dependencyEdge]bool{}
+	link := func(from string, providers []string, reason string) {
+		if len(providers) == 0 {
+			providers = []string{""}
+		}
+		for _, to := range providers {
+			edge := dependencyEdge{From: from, To: to, Reason: reason}
+			if to != from && !seen[edge] {
+				seen[edge] = true
+				graph.Edges = append(graph.Edges, edge)
+			}
+		}
+	}
+
+	for _, csv := range installed {
+		id := csv.Namespace + "/" + csv.Name
+		for _, crd := range csv.Spec.CustomResourceDefinitions.Required {
+			key := crdAPIKey(crd)
+			link(id, apis[key].Providers, key.String())
+		}
+		for _, api := range csv.Spec.APIServiceDefinitions.Required {
+			key := apiServiceAPIKey(api)
+			link(id, apis[key].Providers, key.String())
+		}
+		for _, property := range csvProperties(csv) {
+			switch property.Type {
+			case "olm.gvk.required":
+				var gvk struct {
+					Group   string `json:"group"`
+					Version string `json:"version"`
+					Kind    string `json:"kind"`
+				}
+				if json.Unmarshal(property.Value, &gvk) == nil {
+					name := fmt.Sprintf("%s/%s, Kind=%s", gvk.Group, gvk.Version, gvk.Kind)
+					link(id, gvkProviders[name], name+" (olm.gvk.required)")
+				}
+			case "olm.package.required":
+				var pkg struct {
+					PackageName  string `json:"packageName"`
+					VersionRange string `json:"versionRange"`
+				}
+				if json.Unmarshal(property.Value, &pkg) == nil {
+					link(id, packageProviders[pkg.PackageName], fmt.Sprintf("package %s %s (olm.package.required)", pkg.PackageName, pkg.VersionRange))
+				}
+			}
+		}
+	}
+
+	sort.Strings(graph.Nodes)
+	sort.Slice(graph.Edges, func(i, j int) bool {
+		a, b := graph.Edges[i], graph.Edges[j]
+		if a.From != b.From {
+			return a.From < b.From
+		}
+		if a.To != b.To {
+			return a.To < b.To
+		}
+		return a.Reason < b.Reason
+	})
+	return graph
+}
+
+// focus reduces the graph to the operators connected to the given node,
+// following edges in both directions.
+func (g *dependencyGraph) focus(node string) *dependencyGraph {
+	keep := map[string]bool{node: true}
+	for changed := true; changed; {
+		changed = false
+		for _, edge := range g.Edges {
+			if keep[edge.From] != keep[edge.To] && edge.To != "" {
+				keep[edge.From], keep[edge.To] = true, true
+				changed = true
+			}
+		}
+	}
+
+	focused := &dependencyGraph{Packages: g.Packages}
+	for _, n := range g.Nodes {
+		if keep[n] {
+			focused.Nodes = append(focused.Nodes, n)
+		}
+	}
+	for _, edge := range g.Edges {
+		if keep[edge.From] {
+			focused.Edges = append(focused.Edges, edge)
+		}
+	}
+	return focused
+}
+
+func (g *dependencyGraph) label(node string) string {
+	if pkg := g.Packages[node]; pkg != "" {
+		return fmt.Sprintf("%s (%s)", node, pkg)
+	}
+	return node
+}
+
+func (g *dependencyGraph) renderText() string {
+	dependsOn := map[string][]string{}
+	requiredBy := map[string][]string{}
+	for _, edge := range g.Edges {
+		to := edge.To
+		if to == "" {
+			to = "MISSING"
+		} else {
+			requiredBy[edge.To] = append(requiredBy[edge.To], fmt.Sprintf("%s for %s", edge.From, edge.Reason))
+		}
+		dependsOn[edge.From] = append(dependsOn[edge.From], fmt.Sprintf("%s for %s", to, edge.Reason))
+	}
+
+	var result strings.Builder
+	for _, node := range g.Nodes {
+		result.WriteString(fmt.Sprintf("%s\n", g.label(node)))
+		result.WriteString("  Depends on:\n")
+		if len(dependsOn[node]) == 0 {
+			result.WriteString("    nothing\n")
+		}
+		for _, dep := range dependsOn[node] {
+			result.WriteString(fmt.Sprintf("    - %s\n", dep))
+		}
+		result.WriteString("  Required by:\n")
+		if len(requiredBy[node]) == 0 {
+			result.WriteString("    nothing; safe to remove as far as OLM dependencies go\n")
+		}
+		for _, dep := range requiredBy[node] {
+			result.WriteString(fmt.Sprintf("    - %s\n", dep))
+		}
+	}
+	return result.String()
+}
+
+func (g *dependencyGraph) nodeIDs() map[string]string {
+	ids := map[string]string{}
+	for i, node := range g.Nodes {
+		ids[node] = fmt.Sprintf("n%d", i)
+	}
+	return ids
+}
+
+func (g *dependencyGraph) renderDOT() string {
+	ids := g.nodeIDs()
+	var result strings.Builder
+	result.WriteString("digraph operators {\n")
+	result.WriteString("  rankdir=LR;\n")
+	result.WriteString("  node [shape=box];\n")
+	for _, node := range g.Nodes {
+		result.WriteString(fmt.Sprintf("  %s [label=%q];\n", ids[node], g.label(node)))
+	}
+	missing := 0
+	for _, edge := range g.Edges {
+		to := ids[edge.To]
+		if edge.To == "" {
+			to = fmt.Sprintf("missing%d", missing)
+			missing++
+			result.WriteString(fmt.Sprintf("  %s [label=\"MISSING\", style=dashed, color=red];\n", to))
+		}
+		result.WriteString(fmt.Sprintf("  %s -> %s [label=%q];\n", ids[edge.From], to, edge.Reason))
+	}
+	result.WriteString("}\n")
+	return result.String()
+}
+
+func (g *dependencyGraph) renderMermaid() string {
+	ids := g.nodeIDs()
+	escape := strings.NewReplacer(`"`, "#quot;", "|", "#124;")
+	var result strings.Builder
+	result.WriteString("graph LR\n")
+	for _, node := range g.Nodes {
+		result.WriteString(fmt.Sprintf("  %s[\"%s\"]\n", ids[node], escape.Replace(g.label(node))))
+	}
+	missing := 0
+	for _, edge := range g.Edges {
+		to := ids[edge.To]
+		if edge.To == "" {
+			to = fmt.Sprintf("missing%d", missing)
+			missing++
+			result.WriteString(fmt.Sprintf("  %s[\"MISSING\"]:::missing\n", to))
+		}
+		result.WriteString(fmt.Sprintf("  %s -->|\"%s\"| %s\n", ids[edge.From], escape.Replace(edge.Reason), to))
+	}
+	if missing > 0 {
+		result.WriteString("  classDef missing stroke:#f00,stroke-dasharray:5 5\n")
+	}
+	return result.String()
+}
+
+// GetDependencyGraph renders the dependencies between installed operators
+// as text, DOT or Mermaid.
+func (t *CSVTools) GetDependencyGraph(ctx context.Context, params map[string]string) (*types.MCPToolResult, error) {
+	// Dependencies cross namespaces through cluster-scoped CRDs, so the
+	// graph always covers the whole cluster.
+	format := strings.ToLower(params["format"])
+	operator := params["operator"]
+	namespace := params["namespace"]
+
+	if format == "" {
+		format = "text"
+	}
+	if format != "text" && format != "dot" && format != "mermaid" && format != "all" {
+		return &types.MCPToolResult{
+			Content: []types.MCPContent{{
+				Type: "text",
+				Text: "Error: 'format' must be one of text, dot, mermaid or all",
+			}},
+			IsError: true,
+		}, nil
+	}
+
+	csvs, err := t.server.OLMClient.ListClusterServiceVersions(ctx, "")
+	if err != nil {
+		return &types.MCPToolResult{
+			Content: []types.MCPContent{{
+				Type: "text",
+				Text: fmt.Sprintf("Error listing ClusterServiceVersions: %v", err),
+			}},
+			IsError: true,
+		}, nil
+	}
+	var subscriptions []v1alpha1.Subscription
+	if list, err := t.server.OLMClient.ListSubscriptions(ctx, ""); err == nil {
+		subscriptions = list.Items
+	}
+
+	graph := buildDependencyGraph(csvs.Items, subscriptions)
+
+	var result strings.Builder
+	if operator != "" {
+		if namespace == "" {
+			namespace = "default"
+		}
+		node := namespace + "/" + operator
+		found := false
+		for _, n := range graph.Nodes {
+			found = found || n == node
+		}
+		if !found {
+			return &types.MCPToolResult{
+				Content: []types.MCPContent{{
+					Type: "text",
+					Text: fmt.Sprintf("Error: ClusterServiceVersion '%s' not found in namespace '%s'", operator, namespace),
+				}},
+				IsError: true,
+			}, nil
+		}
+		graph = graph.focus(node)
+		result.WriteString(fmt.Sprintf("Dependency graph around %s:\n\n", graph.label(node)))
+	} else {
+		result.WriteString("Dependency graph of installed operators (copied CSVs excluded):\n\n")
+	}
+	result.WriteString(fmt.Sprintf("%d operators, %d dependencies\n\n", len(graph.Nodes), len(graph.Edges)))
+
+	if format == "text" || format == "all" {
+		result.WriteString(graph.renderText())
+	}
+	if format == "dot" || format == "all" {
+		result.WriteString("\n```dot\n")
+		result.WriteString(graph.renderDOT())
+		result.WriteString("```\n")
+	}
+	if format == "mermaid" || format == "all" {
+		result.WriteString("\n```mermaid\n")
+		result.WriteString(graph.renderMermaid())
+		result.WriteString("```\n")
+	}
+
+	return &types.MCPToolResult{
+		Content: []types.MCPContent{{
+			Type: "text",
+			Text: result.String(),
+		}},
+	}, nil
+}
@@ -0,0 +1,224 @@
+package tools
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/operator-framework/api/pkg/operators/v1alpha1"
+	"github.com/operator-framework/operator-lifecycle-manager/olmv0-mcp-server/pkg/types"
+	corev1 "k8s.io/api/core/v1"
+)
+
+// deprecationConditions are the Subscription conditions OLM sets from a
+// catalog's olm.deprecations, most severe first.
+var deprecationConditions = []v1alpha1.SubscriptionConditionType{
+	v1alpha1.SubscriptionPackageDeprecated,
+	v1alpha1.SubscriptionChannelDeprecated,
+	v1alpha1.SubscriptionBundleDeprecated,
+}
+
+// deprecation is one deprecated package, channel or bundle affecting a
+// Subscription.
+type deprecation struct {
+	Type    v1alpha1.SubscriptionConditionType
+	Message string
+}
+
+// subscriptionDeprecations collects deprecations from the Subscription's
+// conditions. OLM releases that predate these conditions do not set them,
+// so the catalog's own deprecation data is used as well when available.
+func subscriptionDeprecations(subscription *v1alpha1.Subscription, pkg *types.PackageManifest) []deprecation {
+	found := map[v1alpha1.SubscriptionConditionType]string{}
+	for _, cond := range subscription.Status.Conditions {
+		if cond.Status == corev1.ConditionTrue {
+			found[cond.Type] = cond.Message
+		}
+	}
+
+	if pkg != nil {
+		if _, ok := found[v1alpha1.SubscriptionPackageDeprecated]; !ok && pkg.Status.Deprecation != nil {
+			found[v1alpha1.SubscriptionPackageDeprecated] = pkg.Status.Deprecation.Message
+		}
+		if channel := pkg.GetChannel(subscription.Spec.Channel); channel != nil {
+			if _, ok := found[v1alpha1.SubscriptionChannelDeprecated]; !ok && channel.Deprecation != nil {
+				found[v1alpha1.SubscriptionChannelDeprecated] = channel.Deprecation.Message
+			}
+			for _, entry := range channel.Entries {
+				if _, ok := found[v1alpha1.SubscriptionBundleDeprecated]; !ok && entry.Name == subscription.Status.InstalledCSV && entry.Deprecation != nil {
+					found[v1alpha1.SubscriptionBundleDeprecated] = entry.Deprecation.Message
+				}
+			}
+		}
+	}
+
+	var deprecations []deprecation
+	for _, condType := range deprecationConditions {
+		if message, ok := found[condType]; ok {
+			deprecations = append(deprecations, deprecation{Type: condType, Message: message})
+		}
+	}
+	// The roll-up condition only adds information when the specific ones
+	// are missing.
+	if message, ok := found[v1alpha1.SubscriptionDeprecated]; ok && len(deprecations) == 0 {
+		deprecations = append(deprecations, deprecation{Type: v1alpha1.SubscriptionDeprecated, Message: message})
+	}
+	return deprecations
+}
+
+// suggestReplacement proposes how to move off the deprecated content.
+// Channels that already contain the installed CSV are preferred since
+// switching to them is a plain channel change with an upgrade path.
+func suggestReplacement(subscription *v1alpha1.Subscription, pkg *types.PackageManifest, deprecations []deprecation) string {
+	if pkg == nil {
+		return "Check the catalog for a supported channel or replacement package."
+	}
+
+	deprecated := map[v1alpha1.SubscriptionConditionType]bool{}
+	for _, d := range deprecations {
+		deprecated[d.Type] = true
+	}
+	if deprecated[v1alpha1.SubscriptionPackageDeprecated] {
+		return fmt.Sprintf("The whole package %s is deprecated; the catalog names no replacement channel, so plan a migration to the package the deprecation message recommends.", pkg.Status.PackageName)
+	}
+
+	if deprecated[v1alpha1.SubscriptionChannelDeprecated] {
+		var withInstalled, others []string
+		for _, channel := range pkg.Status.Channels {
+			if channel.Deprecation != nil || channel.Name == subscription.Spec.Channel {
+				continue
+			}
+			contains := false
+			for _, entry := range channel.Entries {
+				contains = contains || entry.Name == subscription.Status.InstalledCSV
+			}
+			name := channel.Name
+			if name == pkg.Status.DefaultChannelName {
+				name += " (default)"
+			}
+			if contains {
+				withInstalled = append(withInstalled, name)
+			} else {
+				others = append(others, name)
+			}
+		}
+		sort.Strings(withInstalled)
+		sort.Strings(others)
+
+		switch {
+		case len(withInstalled) > 0:
+			return fmt.Sprintf("Switch to channel %s, which also contains the installed CSV %s (update_subscription channel=...).", strings.Join(withInstalled, " or "), subscription.Status.InstalledCSV)
+		case len(others) > 0:
+			return fmt.Sprintf("Non-deprecated channels: %s. None contains the installed CSV, so switching may require a reinstall.", strings.Join(others, ", "))
+		default:
+			return "Every channel of the package is deprecated."
+		}
+	}
+
+	if deprecated[v1alpha1.SubscriptionBundleDeprecated] {
+		if channel := pkg.GetChannel(subscription.Spec.Channel); channel != nil && channel.CurrentCSV != subscription.Status.InstalledCSV {
+			return fmt.Sprintf("Upgrade to the channel head %s; with Manual approval, approve the pending InstallPlan.", channel.CurrentCSV)
+		}
+		return "The channel head itself is deprecated; wait for a newer bundle or switch channels."
+	}
+	return ""
+}
+
+// writeDeprecationLines renders a Subscription's deprecations.
+func writeDeprecationLines(result *strings.Builder, subscription *v1alpha1.Subscription, pkg *types.PackageManifest, deprecations []deprecation) {
+	for _, d := range deprecations {
+		result.WriteString(fmt.Sprintf("  %s: %s\n", d.Type, valueOrNone(d.Message)))
+	}
+	if suggestion := suggestReplacement(subscription, pkg, deprecations); suggestion != "" {
+		result.WriteString(fmt.Sprintf("  Suggestion: %s\n", suggestion))
+	}
+}
+
+// hasDeprecationCondition reports whether OLM flagged the Subscription as
+// deprecated, so that callers only query the package server when needed.
+func hasDeprecationCondition(subscription *v1alpha1.Subscription) bool {
+	for _, cond := range subscription.Status.Conditions {
+		if cond.Type == v1alpha1.SubscriptionDeprecated && cond.Status == corev1.ConditionTrue {
+			return true
+		}
+		for _, condType := range deprecationConditions {
+			if cond.Type == condType && cond.Status == corev1.ConditionTrue {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// ListDeprecations lists Subscriptions whose package, channel or installed
+// bundle is deprecated.
+func (t *SubscriptionTools) ListDeprecations(ctx context.Context, params map[string]string) (*types.MCPToolResult, error) {
+	// Like list_pending_upgrades this is a cluster-wide report by default.
+	namespace := params["namespace"]
+
+	subscriptions, err := t.server.OLMClient.ListSubscriptions(ctx, namespace)
+	if err != nil {
+		return &types.MCPToolResult{
+			Content: []types.MCPContent{{
+				Type: "text",
+				Text: fmt.Sprintf("Error listing Subscriptions: %v", err),
+			}},
+			IsError: true,
+		}, nil
+	}
+
+	subs := subscriptions.Items
+	sort.Slice(subs, func(i, j int) bool {
+		if subs[i].Namespace != subs[j].Namespace {
+			return subs[i].Namespace < subs[j].Namespace
+		}
+		return subs[i].Name < subs[j].Name
+	})
+
+	var result strings.Builder
+	scope := "all namespaces"
+	if namespace != "" {
+		scope = fmt.Sprintf("namespace '%s'", namespace)
+	}
+	result.WriteString(fmt.Sprintf("Deprecated operators in %s:\n", scope))
+
+	packages := map[string]*types.PackageManifestList{}
+	count := 0
+	for i := range subs {
+		sub := &subs[i]
+
+		list, ok := packages[sub.Namespace]
+		if !ok {
+			list, _ = t.server.OLMClient.ListPackageManifests(ctx, sub.Namespace)
+			packages[sub.Namespace] = list
+		}
+		var pkg *types.PackageManifest
+		if list != nil {
+			pkg, _ = matchPackageManifest(list, sub)
+		}
+
+		deprecations := subscriptionDeprecations(sub, pkg)
+		if len(deprecations) == 0 {
+			continue
+		}
+		count++
+
+		result.WriteString(fmt.Sprintf("\n%s/%s (package %s, channel %s, installed %s)\n",
+			sub.Namespace, sub.Name, sub.Spec.Package, sub.Spec.Channel, valueOrNone(sub.Status.InstalledCSV)))
+		writeDeprecationLines(&result, sub, pkg, deprecations)
+	}
+
+	if count == 0 {
+		result.WriteString("\nNo Subscription uses a deprecated package, channel or bundle.\n")
+	} else {
+		result.WriteString(fmt.Sprintf("\nTotal: %d of %d Subscriptions affected\n", count, len(subs)))
+	}
+
+	return &types.MCPToolResult{
+		Content: []types.MCPContent{{
+			Type: "text",
+			Text: result.String(),
+		}},
+	}, nil
+}
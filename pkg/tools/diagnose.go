@@ -0,0 +1,372 @@
+package tools
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/operator-framework/api/pkg/operators/v1alpha1"
+	"github.com/operator-framework/operator-lifecycle-manager/olmv0-mcp-server/pkg/types"
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+type severity int
+
+const (
+	severityInfo severity = iota
+	severityWarning
+	severityCritical
+)
+
+func (s severity) String() string {
+	switch s {
+	case severityCritical:
+		return "CRITICAL"
+	case severityWarning:
+		return "WARNING"
+	default:
+		return "INFO"
+	}
+}
+
+// finding is a single diagnostic result together with the evidence that led
+// to it.
+type finding struct {
+	Severity severity
+	Title    string
+	Evidence []string
+}
+
+// writeFindings renders findings most severe first. Findings of equal
+// severity keep the order in which they were discovered, which follows the
+// install chain.
+func writeFindings(result *strings.Builder, findings []finding) {
+	sort.SliceStable(findings, func(i, j int) bool {
+		return findings[i].Severity > findings[j].Severity
+	})
+
+	problems := 0
+	for _, f := range findings {
+		if f.Severity > severityInfo {
+			problems++
+		}
+	}
+
+	result.WriteString("Findings (most severe first):\n")
+	if len(findings) == 0 {
+		result.WriteString("  No problems found.\n")
+		return
+	}
+	for i, f := range findings {
+		result.WriteString(fmt.Sprintf("%d. [%s] %s\n", i+1, f.Severity, f.Title))
+		for _, e := range f.Evidence {
+			result.WriteString(fmt.Sprintf("     - %s\n", e))
+		}
+	}
+	if problems == 0 {
+		result.WriteString("\nNo problems found; the findings above are informational.\n")
+	}
+}
+
+// formatTime renders a timestamp along with how long ago it was.
+func formatTime(t *metav1.Time) string {
+	if t == nil || t.IsZero() {
+		return "unknown"
+	}
+	return fmt.Sprintf("%s (%s ago)", t.UTC().Format(time.RFC3339), time.Since(t.Time).Round(time.Second))
+}
+
+type DiagnosticTools struct {
+	server *types.MCPServer
+}
+
+func NewDiagnosticTools(server *types.MCPServer) *DiagnosticTools {
+	return &DiagnosticTools{server: server}
+}
+
+// Subscription conditions that block an install when their status is True.
+var failingSubscriptionConditions = map[v1alpha1.SubscriptionConditionType]bool{
+	v1alpha1.SubscriptionResolutionFailed:        true,
+	v1alpha1.SubscriptionCatalogSourcesUnhealthy: true,
+	v1alpha1.SubscriptionInstallPlanMissing:      true,
+	v1alpha1.SubscriptionInstallPlanFailed:       true,
+	v1alpha1.SubscriptionBundleUnpackFailed:      true,
+}
+
+func (t *DiagnosticTools) DiagnoseSubscription(ctx context.Context, params map[string]string) (*types.MCPToolResult, error) {
+	namespace := params["namespace"]
+	name := params["name"]
+
+	if namespace == "" {
+		namespace = "default"
+	}
+	if name == "" {
+		return &types.MCPToolResult{
+			Content: []types.MCPContent{{
+				Type: "text",
+				Text: "Error: 'name' parameter is required",
+			}},
+			IsError: true,
+		}, nil
+	}
+
+	subscription, err := t.server.OLMClient.GetSubscription(ctx, namespace, name)
+	if err != nil {
+		return &types.MCPToolResult{
+			Content: []types.MCPContent{{
+				Type: "text",
+				Text: fmt.Sprintf("Error getting Subscription '%s': %v", name, err),
+			}},
+			IsError: true,
+		}, nil
+	}
+
+	var chain strings.Builder
+	var findings []finding
+
+	chain.WriteString(fmt.Sprintf("  Subscription: %s/%s (package: %s, channel: %s, state: %s)\n",
+		namespace, name, subscription.Spec.Package, subscription.Spec.Channel, subscription.Status.State))
+	findings = append(findings, subscriptionFindings(subscription)...)
+
+	// InstallPlan
+	if ref := subscription.Status.InstallPlanRef; ref != nil {
+		ipNamespace := ref.Namespace
+		if ipNamespace == "" {
+			ipNamespace = namespace
+		}
+		installPlan, err := t.server.OLMClient.GetInstallPlan(ctx, ipNamespace, ref.Name)
+		if err != nil {
+			chain.WriteString(fmt.Sprintf("  InstallPlan: %s/%s (unreadable)\n", ipNamespace, ref.Name))
+			findings = append(findings, finding{
+				Severity: severityCritical,
+				Title:    fmt.Sprintf("InstallPlan '%s' referenced by the Subscription cannot be read", ref.Name),
+				Evidence: []string{err.Error()},
+			})
+		} else {
+			chain.WriteString(fmt.Sprintf("  InstallPlan: %s/%s (phase: %s, approval: %s, approved: %t)\n",
+				ipNamespace, installPlan.Name, installPlan.Status.Phase, installPlan.Spec.Approval, installPlan.Spec.Approved))
+			findings = append(findings, installPlanFindings(installPlan)...)
+		}
+	} else {
+		chain.WriteString("  InstallPlan: none referenced\n")
+		if subscription.Status.InstalledCSV == "" {
+			findings = append(findings, finding{
+				Severity: severityWarning,
+				Title:    "No InstallPlan has been generated for the Subscription",
+				Evidence: []string{"status.installPlanRef is empty; OLM has not resolved the Subscription yet"},
+			})
+		}
+	}
+
+	// ClusterServiceVersion
+	csvName := subscription.Status.CurrentCSV
+	if csvName == "" {
+		csvName = subscription.Status.InstalledCSV
+	}
+	if csvName == "" {
+		chain.WriteString("  CSV: none yet\n")
+	} else {
+		csv, err := t.server.OLMClient.GetClusterServiceVersion(ctx, namespace, csvName)
+		switch {
+		case apierrors.IsNotFound(err):
+			chain.WriteString(fmt.Sprintf("  CSV: %s/%s (not created yet)\n", namespace, csvName))
+			findings = append(findings, finding{
+				Severity: severityWarning,
+				Title:    fmt.Sprintf("CSV '%s' does not exist yet", csvName),
+				Evidence: []string{"the Subscription's current CSV has not been created by its InstallPlan"},
+			})
+		case err != nil:
+			chain.WriteString(fmt.Sprintf("  CSV: %s/%s (unreadable)\n", namespace, csvName))
+			findings = append(findings, finding{
+				Severity: severityWarning,
+				Title:    fmt.Sprintf("Could not read CSV '%s'", csvName),
+				Evidence: []string{err.Error()},
+			})
+		default:
+			chain.WriteString(fmt.Sprintf("  CSV: %s/%s (phase: %s, reason: %s)\n", namespace, csvName, csv.Status.Phase, csv.Status.Reason))
+			findings = append(findings, csvFindings(csv)...)
+
+			deployments := inspectCSVDeployments(ctx, t.server.K8sClient, csv)
+			if len(deployments) > 0 {
+				chain.WriteString(fmt.Sprintf("  Deployments: %s\n", summarizeDeployments(deployments)))
+			}
+			findings = append(findings, deploymentFindings(fmt.Sprintf("CSV '%s'", csvName), deployments)...)
+		}
+	}
+
+	var result strings.Builder
+	result.WriteString(fmt.Sprintf("Diagnosis of Subscription %s/%s\n\n", namespace, name))
+	result.WriteString("Install chain:\n")
+	result.WriteString(chain.String())
+	result.WriteString("\n")
+	writeFindings(&result, findings)
+
+	return &types.MCPToolResult{
+		Content: []types.MCPContent{{
+			Type: "text",
+			Text: result.String(),
+		}},
+	}, nil
+}
+
+func subscriptionFindings(subscription *v1alpha1.Subscription) []finding {
+	var findings []finding
+
+	for _, cond := range subscription.Status.Conditions {
+		if cond.Status != corev1.ConditionTrue {
+			continue
+		}
+		evidence := []string{fmt.Sprintf("condition %s=True reason=%s", cond.Type, cond.Reason)}
+		if cond.Message != "" {
+			evidence = append(evidence, cond.Message)
+		}
+
+		switch {
+		case failingSubscriptionConditions[cond.Type]:
+			findings = append(findings, finding{
+				Severity: severityCritical,
+				Title:    fmt.Sprintf("Subscription reports %s", cond.Type),
+				Evidence: evidence,
+			})
+		case cond.Type == v1alpha1.SubscriptionInstallPlanPending:
+			findings = append(findings, finding{
+				Severity: severityWarning,
+				Title:    fmt.Sprintf("InstallPlan is pending (%s)", cond.Reason),
+				Evidence: evidence,
+			})
+		case cond.Type == v1alpha1.SubscriptionBundleUnpacking:
+			findings = append(findings, finding{
+				Severity: severityInfo,
+				Title:    "Bundle is still being unpacked",
+				Evidence: evidence,
+			})
+		}
+	}
+
+	for _, health := range subscription.Status.CatalogHealth {
+		if health.Healthy || health.CatalogSourceRef == nil {
+			continue
+		}
+		findings = append(findings, finding{
+			Severity: severityCritical,
+			Title:    fmt.Sprintf("CatalogSource %s/%s is unhealthy", health.CatalogSourceRef.Namespace, health.CatalogSourceRef.Name),
+			Evidence: []string{"listed as unhealthy in status.catalogHealth; OLM cannot resolve against it"},
+		})
+	}
+
+	return findings
+}
+
+func installPlanFindings(installPlan *v1alpha1.InstallPlan) []finding {
+	var findings []finding
+
+	var stepEvidence []string
+	for _, step := range installPlan.Status.Plan {
+		if step == nil || step.Status == v1alpha1.StepStatusCreated || step.Status == v1alpha1.StepStatusPresent {
+			continue
+		}
+		stepEvidence = append(stepEvidence, fmt.Sprintf("step %s '%s' status: %s", step.Resource.Kind, step.Resource.Name, step.Status))
+	}
+
+	switch installPlan.Status.Phase {
+	case v1alpha1.InstallPlanPhaseFailed:
+		evidence := []string{}
+		if installPlan.Status.Message != "" {
+			evidence = append(evidence, installPlan.Status.Message)
+		}
+		for _, cond := range installPlan.Status.Conditions {
+			if cond.Status == corev1.ConditionFalse {
+				evidence = append(evidence, fmt.Sprintf("condition %s=False reason=%s: %s", cond.Type, cond.Reason, cond.Message))
+			}
+		}
+		findings = append(findings, finding{
+			Severity: severityCritical,
+			Title:    fmt.Sprintf("InstallPlan '%s' failed", installPlan.Name),
+			Evidence: append(evidence, stepEvidence...),
+		})
+	case v1alpha1.InstallPlanPhaseRequiresApproval:
+		findings = append(findings, finding{
+			Severity: severityWarning,
+			Title:    fmt.Sprintf("InstallPlan '%s' is waiting for manual approval", installPlan.Name),
+			Evidence: []string{
+				fmt.Sprintf("CSVs to install: %s", strings.Join(installPlan.Spec.ClusterServiceVersionNames, ", ")),
+				"approve it with approve_install_plan",
+			},
+		})
+	case v1alpha1.InstallPlanPhaseInstalling, v1alpha1.InstallPlanPhasePlanning:
+		if len(stepEvidence) > 0 {
+			findings = append(findings, finding{
+				Severity: severityInfo,
+				Title:    fmt.Sprintf("InstallPlan '%s' is still %s", installPlan.Name, strings.ToLower(string(installPlan.Status.Phase))),
+				Evidence: stepEvidence,
+			})
+		}
+	}
+
+	for _, lookup := range installPlan.Status.BundleLookups {
+		for _, cond := range lookup.Conditions {
+			if cond.Status != corev1.ConditionTrue {
+				continue
+			}
+			sev := severityInfo
+			if cond.Type == v1alpha1.BundleLookupFailed {
+				sev = severityCritical
+			}
+			findings = append(findings, finding{
+				Severity: sev,
+				Title:    fmt.Sprintf("Bundle lookup for %s: %s", lookup.Identifier, cond.Type),
+				Evidence: []string{fmt.Sprintf("bundle: %s", lookup.Path), fmt.Sprintf("%s: %s", cond.Reason, cond.Message)},
+			})
+		}
+	}
+
+	return findings
+}
+
+func csvFindings(csv *v1alpha1.ClusterServiceVersion) []finding {
+	var findings []finding
+
+	evidence := []string{fmt.Sprintf("phase %s, reason %s", csv.Status.Phase, csv.Status.Reason)}
+	if csv.Status.Message != "" {
+		evidence = append(evidence, csv.Status.Message)
+	}
+	for _, req := range csv.Status.RequirementStatus {
+		if req.Status != v1alpha1.RequirementStatusReasonPresent {
+			evidence = append(evidence, fmt.Sprintf("requirement %s %s: %s %s", req.Kind, req.Name, req.Status, req.Message))
+		}
+	}
+
+	switch csv.Status.Phase {
+	case v1alpha1.CSVPhaseFailed:
+		findings = append(findings, finding{
+			Severity: severityCritical,
+			Title:    fmt.Sprintf("CSV '%s' failed (%s)", csv.Name, csv.Status.Reason),
+			Evidence: evidence,
+		})
+	case v1alpha1.CSVPhasePending, v1alpha1.CSVPhaseInstallReady, v1alpha1.CSVPhaseInstalling, v1alpha1.CSVPhaseUnknown, v1alpha1.CSVPhaseNone:
+		findings = append(findings, finding{
+			Severity: severityWarning,
+			Title:    fmt.Sprintf("CSV '%s' is stuck in %s (%s)", csv.Name, phaseOrNone(csv.Status.Phase), csv.Status.Reason),
+			Evidence: evidence,
+		})
+	case v1alpha1.CSVPhaseReplacing, v1alpha1.CSVPhaseDeleting:
+		findings = append(findings, finding{
+			Severity: severityInfo,
+			Title:    fmt.Sprintf("CSV '%s' is %s", csv.Name, strings.ToLower(string(csv.Status.Phase))),
+			Evidence: evidence,
+		})
+	}
+
+	return findings
+}
+
+func phaseOrNone(phase v1alpha1.ClusterServiceVersionPhase) string {
+	if phase == v1alpha1.CSVPhaseNone {
+		return "no phase"
+	}
+	return string(phase)
+}
@@ -0,0 +1,296 @@
+package tools
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/operator-framework/operator-lifecycle-manager/olmv0-mcp-server/pkg/types"
+	"k8s.io/apimachinery/pkg/api/meta"
+	"k8s.io/apimachinery/pkg/runtime"
+	"sigs.k8s.io/yaml"
+)
+
+const diffContextLines = 3
+
+// writeOperation describes a single change to a cluster object. Apply is
+// called once with server-side dry-run to preview the change and, unless the
+// caller only asked for a dry run, a second time to persist it. Live is nil
+// for creates, and Apply returns a nil object for deletes.
+type writeOperation struct {
+	Action    string
+	Kind      string
+	Namespace string
+	Name      string
+	Live      runtime.Object
+	Apply     func(ctx context.Context, dryRun bool) (runtime.Object, error)
+}
+
+// boolParam reports whether params[key] holds a true boolean value such as
+// "true" or "1". Missing or malformed values are treated as false.
+func boolParam(params map[string]string, key string) bool {
+	value, err := strconv.ParseBool(params[key])
+	return err == nil && value
+}
+
+// checkWriteAllowed returns an error result when a real write is requested
+// while the server runs in read-only mode. Dry runs are always allowed since
+// they never persist anything.
+func checkWriteAllowed(server *types.MCPServer, dryRun bool) *types.MCPToolResult {
+	if !server.ReadOnly || dryRun {
+		return nil
+	}
+	return &types.MCPToolResult{
+		Content: []types.MCPContent{{
+			Type: "text",
+			Text: "Error: the server is running in read-only mode. Re-run with 'dry_run' set to true to preview the change, or restart the server with --read-only=false.",
+		}},
+		IsError: true,
+	}
+}
+
+// executeWrite previews op with a server-side dry-run and, unless dryRun is
+// set, applies it. The returned report contains the previewed diff followed
+// by the diff of what was actually persisted so both can be compared.
+func executeWrite(ctx context.Context, op writeOperation, dryRun bool) (string, error) {
+	var report strings.Builder
+	target := fmt.Sprintf("%s %s/%s", op.Kind, op.Namespace, op.Name)
+
+	preview, err := op.Apply(ctx, true)
+	if err != nil {
+		return "", fmt.Errorf("server-side dry-run of %s %s failed: %v", op.Action, target, err)
+	}
+	section, err := describeChange(op, preview, "dry-run")
+	if err != nil {
+		return "", err
+	}
+	report.WriteString(fmt.Sprintf("Planned %s of %s (server-side dry-run):\n", op.Action, target))
+	report.WriteString(section)
+
+	if dryRun {
+		report.WriteString("Dry run only: no changes were persisted.\n")
+		return report.String(), nil
+	}
+
+	applied, err := op.Apply(ctx, false)
+	if err != nil {
+		return report.String(), fmt.Errorf("%s of %s failed: %v", op.Action, target, err)
+	}
+	section, err = describeChange(op, applied, "applied")
+	if err != nil {
+		return report.String(), err
+	}
+	report.WriteString(fmt.Sprintf("\nApplied %s of %s:\n", op.Action, target))
+	report.WriteString(section)
+
+	return report.String(), nil
+}
+
+// describeChange renders the difference between the live object and the
+// result of op. Creates are shown as the full manifest instead of a diff.
+func describeChange(op writeOperation, result runtime.Object, label string) (string, error) {
+	after, err := renderManifest(result)
+	if err != nil {
+		return "", fmt.Errorf("error rendering %s %s: %v", label, op.Kind, err)
+	}
+
+	if op.Live == nil {
+		return fmt.Sprintf("```yaml\n%s```\n", after), nil
+	}
+
+	before, err := renderManifest(op.Live)
+	if err != nil {
+		return "", fmt.Errorf("error rendering live %s: %v", op.Kind, err)
+	}
+
+	path := fmt.Sprintf("%s/%s/%s", strings.ToLower(op.Kind), op.Namespace, op.Name)
+	diff := unifiedDiff("live/"+path, label+"/"+path, before, after)
+	if diff == "" {
+		return "No changes.\n", nil
+	}
+	return fmt.Sprintf("```diff\n%s```\n", diff), nil
+}
+
+// renderManifest marshals obj to YAML without its managed fields, which
+// would otherwise dominate every diff. A nil object renders as empty.
+func renderManifest(obj runtime.Object) (string, error) {
+	if obj == nil {
+		return "", nil
+	}
+
+	obj = obj.DeepCopyObject()
+	if accessor, err := meta.Accessor(obj); err == nil {
+		accessor.SetManagedFields(nil)
+	}
+
+	data, err := yaml.Marshal(obj)
+	if err != nil {
+		return "", err
+	}
+	return string(data), nil
+}
+
+// unifiedDiff returns a unified diff between two texts, or an empty string
+// when they are identical.
+func unifiedDiff(fromName, toName, from, to string) string {
+	a := splitLines(from)
+	b := splitLines(to)
+
+	ops := diffLines(a, b)
+	changed := false
+	for _, op := range ops {
+		if op.kind != ' ' {
+			changed = true
+			break
+		}
+	}
+	if !changed {
+		return ""
+	}
+
+	var out strings.Builder
+	out.WriteString(fmt.Sprintf("--- %s\n+++ %s\n", fromName, toName))
+
+	for start := 0; start < len(ops); {
+		// Find the next change and open a hunk around it.
+		for start < len(ops) && ops[start].kind == ' ' {
+			start++
+		}
+		if start == len(ops) {
+			break
+		}
+		hunkStart := max(start-diffContextLines, 0)
+
+		// Extend the hunk until we see more than twice the context of
+		// unchanged lines in a row.
+		end := start
+		for end < len(ops) {
+			if ops[end].kind != ' ' {
+				end++
+				continue
+			}
+			run := end
+			for run < len(ops) && ops[run].kind == ' ' {
+				run++
+			}
+			if run == len(ops) || run-end > 2*diffContextLines {
+				end = min(end+diffContextLines, len(ops))
+				break
+			}
+			end = run
+		}
+
+		fromLine, toLine := ops[hunkStart].fromLine, ops[hunkStart].toLine
+		fromCount, toCount := 0, 0
+		for _, op := range ops[hunkStart:end] {
+			if op.kind != '+' {
+				fromCount++
+			}
+			if op.kind != '-' {
+				toCount++
+			}
+		}
+		out.WriteString(fmt.Sprintf("@@ -%s +%s @@\n", hunkRange(fromLine, fromCount), hunkRange(toLine, toCount)))
+		for _, op := range ops[hunkStart:end] {
+			out.WriteString(fmt.Sprintf("%c%s\n", op.kind, op.text))
+		}
+
+		start = end
+	}
+
+	return out.String()
+}
+
+type diffOp struct {
+	kind     byte
+	text     string
+	fromLine int
+	toLine   int
+}
+
+// diffLines computes a line-based edit script. Common prefixes and suffixes
+// are trimmed first so the quadratic LCS only runs over the changed region,
+// which is small for the single-field edits our write tools make.
+func diffLines(a, b []string) []diffOp {
+	prefix := 0
+	for prefix < len(a) && prefix < len(b) && a[prefix] == b[prefix] {
+		prefix++
+	}
+	suffix := 0
+	for suffix < len(a)-prefix && suffix < len(b)-prefix && a[len(a)-1-suffix] == b[len(b)-1-suffix] {
+		suffix++
+	}
+
+	midA := a[prefix : len(a)-suffix]
+	midB := b[prefix : len(b)-suffix]
+
+	lcs := make([][]int, len(midA)+1)
+	for i := range lcs {
+		lcs[i] = make([]int, len(midB)+1)
+	}
+	for i := len(midA) - 1; i >= 0; i-- {
+		for j := len(midB) - 1; j >= 0; j-- {
+			if midA[i] == midB[j] {
+				lcs[i][j] = lcs[i+1][j+1] + 1
+			} else {
+				lcs[i][j] = max(lcs[i+1][j], lcs[i][j+1])
+			}
+		}
+	}
+
+	var ops []diffOp
+	i, j := 0, 0
+	emit := func(kind byte, text string) {
+		ops = append(ops, diffOp{kind: kind, text: text, fromLine: i + 1, toLine: j + 1})
+		switch kind {
+		case ' ':
+			i++
+			j++
+		case '-':
+			i++
+		case '+':
+			j++
+		}
+	}
+
+	for k := 0; k < prefix; k++ {
+		emit(' ', a[k])
+	}
+	for mi, mj := 0, 0; mi < len(midA) || mj < len(midB); {
+		switch {
+		case mi < len(midA) && mj < len(midB) && midA[mi] == midB[mj]:
+			emit(' ', midA[mi])
+			mi++
+			mj++
+		case mi < len(midA) && (mj == len(midB) || lcs[mi+1][mj] >= lcs[mi][mj+1]):
+			emit('-', midA[mi])
+			mi++
+		default:
+			emit('+', midB[mj])
+			mj++
+		}
+	}
+	for k := len(a) - suffix; k < len(a); k++ {
+		emit(' ', a[k])
+	}
+
+	return ops
+}
+
+func hunkRange(line, count int) string {
+	if count == 0 {
+		line--
+	}
+	if count == 1 {
+		return fmt.Sprintf("%d", line)
+	}
+	return fmt.Sprintf("%d,%d", line, count)
+}
+
+func splitLines(text string) []string {
+	if text == "" {
+		return nil
+	}
+	return strings.Split(strings.TrimSuffix(text, "\n"), "\n")
+}
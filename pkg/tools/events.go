@@ -0,0 +1,250 @@
+package tools
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/operator-framework/api/pkg/operators/v1alpha1"
+	"github.com/operator-framework/operator-lifecycle-manager/olmv0-mcp-server/pkg/types"
+	corev1 "k8s.io/api/core/v1"
+	eventsv1 "k8s.io/api/events/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	k8stypes "k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/kubernetes"
+)
+
+// objectEvent is a Kubernetes event normalized from either the core/v1 or
+// the events.k8s.io/v1 API.
+type objectEvent struct {
+	UID     k8stypes.UID
+	Type    string
+	Reason  string
+	Message string
+	Source  string
+	Count   int32
+	First   time.Time
+	Last    time.Time
+}
+
+// eventKinds maps the kind names accepted by get_events to OLM kinds.
+var eventKinds = map[string]string{
+	"csv":                   v1alpha1.ClusterServiceVersionKind,
+	"clusterserviceversion": v1alpha1.ClusterServiceVersionKind,
+	"subscription":          v1alpha1.SubscriptionKind,
+	"catalogsource":         v1alpha1.CatalogSourceKind,
+	"installplan":           v1alpha1.InstallPlanKind,
+	"operatorgroup":         "OperatorGroup",
+}
+
+// listObjectEvents returns the events about an object from both event APIs,
+// merged by UID, sorted oldest first and deduplicated by reason so that
+// repeated reconciliation events collapse into one entry with a total count.
+func listObjectEvents(ctx context.Context, k8sClient kubernetes.Interface, kind, namespace, name string) ([]objectEvent, error) {
+	byUID := map[k8stypes.UID]objectEvent{}
+
+	coreEvents, coreErr := k8sClient.CoreV1().Events(namespace).List(ctx, metav1.ListOptions{
+		FieldSelector: fmt.Sprintf("involvedObject.kind=%s,involvedObject.name=%s", kind, name),
+	})
+	if coreErr == nil {
+		for _, e := range coreEvents.Items {
+			byUID[e.UID] = fromCoreEvent(e)
+		}
+	}
+
+	newEvents, newErr := k8sClient.EventsV1().Events(namespace).List(ctx, metav1.ListOptions{
+		FieldSelector: fmt.Sprintf("regarding.kind=%s,regarding.name=%s", kind, name),
+	})
+	if newErr == nil {
+		for _, e := range newEvents.Items {
+			if _, seen := byUID[e.UID]; !seen {
+				byUID[e.UID] = fromEventsV1Event(e)
+			}
+		}
+	}
+
+	if coreErr != nil && newErr != nil {
+		return nil, coreErr
+	}
+
+	byReason := map[string]objectEvent{}
+	for _, e := range byUID {
+		existing, ok := byReason[e.Reason]
+		if !ok {
+			byReason[e.Reason] = e
+			continue
+		}
+		merged := existing
+		if e.Last.After(existing.Last) {
+			merged = e
+		}
+		merged.Count = existing.Count + e.Count
+		merged.First = earliest(existing.First, e.First)
+		byReason[e.Reason] = merged
+	}
+
+	events := make([]objectEvent, 0, len(byReason))
+	for _, e := range byReason {
+		events = append(events, e)
+	}
+	sort.Slice(events, func(i, j int) bool {
+		return events[i].Last.Before(events[j].Last)
+	})
+	return events, nil
+}
+
+func fromCoreEvent(e corev1.Event) objectEvent {
+	last := e.LastTimestamp.Time
+	if e.Series != nil && !e.Series.LastObservedTime.IsZero() {
+		last = e.Series.LastObservedTime.Time
+	}
+	if last.IsZero() {
+		last = e.EventTime.Time
+	}
+	if last.IsZero() {
+		last = e.CreationTimestamp.Time
+	}
+	first := e.FirstTimestamp.Time
+	if first.IsZero() {
+		first = last
+	}
+	count := e.Count
+	if e.Series != nil && e.Series.Count > count {
+		count = e.Series.Count
+	}
+	if count == 0 {
+		count = 1
+	}
+	source := e.Source.Component
+	if source == "" {
+		source = e.ReportingController
+	}
+	return objectEvent{
+		UID:     e.UID,
+		Type:    e.Type,
+		Reason:  e.Reason,
+		Message: e.Message,
+		Source:  source,
+		Count:   count,
+		First:   first,
+		Last:    last,
+	}
+}
+
+func fromEventsV1Event(e eventsv1.Event) objectEvent {
+	last := e.EventTime.Time
+	if e.Series != nil && !e.Series.LastObservedTime.IsZero() {
+		last = e.Series.LastObservedTime.Time
+	}
+	if last.IsZero() {
+		last = e.DeprecatedLastTimestamp.Time
+	}
+	if last.IsZero() {
+		last = e.CreationTimestamp.Time
+	}
+	first := e.DeprecatedFirstTimestamp.Time
+	if first.IsZero() {
+		first = last
+	}
+	count := e.DeprecatedCount
+	if e.Series != nil && e.Series.Count > count {
+		count = e.Series.Count
+	}
+	if count == 0 {
+		count = 1
+	}
+	return objectEvent{
+		UID:     e.UID,
+		Type:    e.Type,
+		Reason:  e.Reason,
+		Message: e.Note,
+		Source:  e.ReportingController,
+		Count:   count,
+		First:   first,
+		Last:    last,
+	}
+}
+
+func earliest(a, b time.Time) time.Time {
+	if a.IsZero() || (!b.IsZero() && b.Before(a)) {
+		return b
+	}
+	return a
+}
+
+// writeEvents renders an events table for an object.
+func writeEvents(result *strings.Builder, events []objectEvent) {
+	if len(events) == 0 {
+		result.WriteString("No events found (events expire after about an hour by default).\n")
+		return
+	}
+	result.WriteString("LAST SEEN\tTYPE\tREASON\tCOUNT\tSOURCE\tMESSAGE\n")
+	for _, e := range events {
+		lastSeen := metav1.NewTime(e.Last)
+		result.WriteString(fmt.Sprintf("%s\t%s\t%s\t%d\t%s\t%s\n",
+			formatTime(&lastSeen),
+			e.Type,
+			e.Reason,
+			e.Count,
+			e.Source,
+			e.Message,
+		))
+	}
+}
+
+// writeEventsSection appends an "Events:" section for an object to the
+// output of the get_* tools. Failures are reported inline since events are
+// supplementary.
+func writeEventsSection(ctx context.Context, result *strings.Builder, server *types.MCPServer, kind, namespace, name string) {
+	result.WriteString("Events:\n")
+	events, err := listObjectEvents(ctx, server.K8sClient, kind, namespace, name)
+	if err != nil {
+		result.WriteString(fmt.Sprintf("  Unavailable: %v\n\n", err))
+		return
+	}
+	writeEvents(result, events)
+	result.WriteString("\n")
+}
+
+func (t *DiagnosticTools) GetEvents(ctx context.Context, params map[string]string) (*types.MCPToolResult, error) {
+	namespace := params["namespace"]
+	name := params["name"]
+	kind := eventKinds[strings.ToLower(params["kind"])]
+
+	if namespace == "" {
+		namespace = "default"
+	}
+	if name == "" || kind == "" {
+		return &types.MCPToolResult{
+			Content: []types.MCPContent{{
+				Type: "text",
+				Text: "Error: 'name' and 'kind' parameters are required; 'kind' must be one of csv, subscription, catalogsource, installplan or operatorgroup",
+			}},
+			IsError: true,
+		}, nil
+	}
+
+	events, err := listObjectEvents(ctx, t.server.K8sClient, kind, namespace, name)
+	if err != nil {
+		return &types.MCPToolResult{
+			Content: []types.MCPContent{{
+				Type: "text",
+				Text: fmt.Sprintf("Error listing events for %s '%s': %v", kind, name, err),
+			}},
+			IsError: true,
+		}, nil
+	}
+
+	var result strings.Builder
+	result.WriteString(fmt.Sprintf("Events for %s %s/%s (deduplicated by reason, oldest first):\n\n", kind, namespace, name))
+	writeEvents(&result, events)
+
+	return &types.MCPToolResult{
+		Content: []types.MCPContent{{
+			Type: "text",
+			Text: result.String(),
+		}},
+	}, nil
+}
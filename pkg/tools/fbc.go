@@ -0,0 +1,936 @@
+package tools
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/blang/semver/v4"
+	"github.com/operator-framework/operator-lifecycle-manager/olmv0-mcp-server/pkg/types"
+	utilyaml "k8s.io/apimachinery/pkg/util/yaml"
+)
+
+// Schemas of the File-Based Catalog blobs the catalog tools understand.
+// Blobs with other schemas, such as olm.deprecations, are counted and
+// otherwise ignored.
+const (
+	fbcSchemaPackage = "olm.package"
+	fbcSchemaChannel = "olm.channel"
+	fbcSchemaBundle  = "olm.bundle"
+)
+
+// maxFBCFiles bounds how many catalog files one tool call reads.
+const maxFBCFiles = 10000
+
+// fbcBlob is the union of the fields of the olm.package, olm.channel and
+// olm.bundle schemas.
+type fbcBlob struct {
+	Schema         string            `json:"schema"`
+	Name           string            `json:"name"`
+	Package        string            `json:"package"`
+	DefaultChannel string            `json:"defaultChannel"`
+	Entries        []fbcChannelEntry `json:"entries"`
+	Image          string            `json:"image"`
+	Properties     []bundleProperty  `json:"properties"`
+}
+
+type fbcChannelEntry struct {
+	Name      string   `json:"name"`
+	Replaces  string   `json:"replaces"`
+	Skips     []string `json:"skips"`
+	SkipRange string   `json:"skipRange"`
+}
+
+type fbcBundle struct {
+	Name    string
+	Image   string
+	Version string
+	Source  string
+}
+
+type fbcChannel struct {
+	Name    string
+	Entries []fbcChannelEntry
+	Source  string
+}
+
+type fbcPackage struct {
+	Name           string
+	DefaultChannel string
+	Defined        bool
+	Source         string
+	Channels       map[string]*fbcChannel
+	Bundles        map[string]*fbcBundle
+}
+
+// fileBasedCatalog is a File-Based Catalog read from local disk.
+type fileBasedCatalog struct {
+	Dir      string
+	Files    int
+	Packages map[string]*fbcPackage
+	// Other counts blobs of schemas the catalog tools do not model.
+	Other map[string]int
+	// Problems are malformed files and blobs, which validation reports.
+	Problems []string
+}
+
+// loadFileBasedCatalog reads every JSON and YAML file under dir. Like opm,
+// it skips hidden files and directories. Symlinks are not followed, so that
+// nothing outside dir is read, and catalogs of more than maxFBCFiles files
+// are rejected.
+func loadFileBasedCatalog(dir string) (*fileBasedCatalog, error) {
+	info, err := os.Stat(dir)
+	if err != nil {
+		return nil, err
+	}
+	if !info.IsDir() {
+		return nil, fmt.Errorf("%s is not a directory", dir)
+	}
+
+	catalog := &fileBasedCatalog{Dir: dir, Packages: map[string]*fbcPackage{}, Other: map[string]int{}}
+	err = filepath.WalkDir(dir, func(path string, entry fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		if path != dir && strings.HasPrefix(entry.Name(), ".") {
+			if entry.IsDir() {
+				return filepath.SkipDir
+			}
+			return nil
+		}
+		if entry.IsDir() || entry.Type()&fs.ModeSymlink != 0 {
+			return nil
+		}
+		switch strings.ToLower(filepath.Ext(path)) {
+		case ".json", ".yaml", ".yml":
+		default:
+			return nil
+		}
+		if catalog.Files >= maxFBCFiles {
+			return fmt.Errorf("more than %d catalog files under %s", maxFBCFiles, dir)
+		}
+		rel, err := filepath.Rel(dir, path)
+		if err != nil {
+			return err
+		}
+		return catalog.loadFile(path, filepath.ToSlash(rel))
+	})
+	if err != nil {
+		return nil, err
+	}
+	return catalog, nil
+}
+
+func (c *fileBasedCatalog) loadFile(path, rel string) error {
+	file, err := os.Open(path)
+	if err != nil {
+		return err
+	}
+	defer file.Close()
+	c.Files++
+
+	decoder := utilyaml.NewYAMLOrJSONDecoder(file, 4096)
+	for i := 1; ; i++ {
+		var blob fbcBlob
+		err := decoder.Decode(&blob)
+		if errors.Is(err, io.EOF) {
+			return nil
+		}
+		if err != nil {
+			c.Problems = append(c.Problems, fmt.Sprintf("%s: %v", rel, err))
+			return nil
+		}
+		c.add(blob, fmt.Sprintf("%s#%d", rel, i))
+	}
+}
+
+func (c *fileBasedCatalog) pkg(name string) *fbcPackage {
+	pkg := c.Packages[name]
+	if pkg == nil {
+		pkg = &fbcPackage{Name: name, Channels: map[string]*fbcChannel{}, Bundles: map[string]*fbcBundle{}}
+		c.Packages[name] = pkg
+	}
+	return pkg
+}
+
+func (c *fileBasedCatalog) add(blob fbcBlob, source string) {
+	switch blob.Schema {
+	case "":
+		c.Problems = append(c.Problems, fmt.Sprintf("%s: blob has no schema", source))
+	case fbcSchemaPackage:
+		if blob.Name == "" {
+			c.Problems = append(c.Problems, fmt.Sprintf("%s: olm.package has no name", source))
+			return
+		}
+		pkg := c.pkg(blob.Name)
+		if pkg.Defined {
+			c.Problems = append(c.Problems, fmt.Sprintf("%s: package %s is already defined in %s", source, blob.Name, pkg.Source))
+			return
+		}
+		pkg.Defined, pkg.Source, pkg.DefaultChannel = true, source, blob.DefaultChannel
+	case fbcSchemaChannel:
+		if blob.Name == "" || blob.Package == "" {
+			c.Problems = append(c.Problems, fmt.Sprintf("%s: olm.channel needs both name and package", source))
+			return
+		}
+		pkg := c.pkg(blob.Package)
+		if existing := pkg.Channels[blob.Name]; existing != nil {
+			c.Problems = append(c.Problems, fmt.Sprintf("%s: channel %s/%s is already defined in %s", source, blob.Package, blob.Name, existing.Source))
+			return
+		}
+		pkg.Channels[blob.Name] = &fbcChannel{Name: blob.Name, Entries: blob.Entries, Source: source}
+	case fbcSchemaBundle:
+		if blob.Name == "" || blob.Package == "" {
+			c.Problems = append(c.Problems, fmt.Sprintf("%s: olm.bundle needs both name and package", source))
+			return
+		}
+		pkg := c.pkg(blob.Package)
+		if existing := pkg.Bundles[blob.Name]; existing != nil {
+			c.Problems = append(c.Problems, fmt.Sprintf("%s: bundle %s/%s is already defined in %s", source, blob.Package, blob.Name, existing.Source))
+			return
+		}
+		pkg.Bundles[blob.Name] = &fbcBundle{Name: blob.Name, Image: blob.Image, Version: fbcBundleVersion(blob.Properties), Source: source}
+	default:
+		c.Other[blob.Schema]++
+	}
+}
+
+// fbcBundleVersion returns the version in a bundle's olm.package property.
+func fbcBundleVersion(properties []bundleProperty) string {
+	for _, property := range properties {
+		if property.Type != "olm.package" {
+			continue
+		}
+		var value struct {
+			Version string `json:"version"`
+		}
+		if json.Unmarshal(property.Value, &value) == nil {
+			return value.Version
+		}
+	}
+	return ""
+}
+
+func (c *fileBasedCatalog) packageNames() []string {
+	names := make([]string, 0, len(c.Packages))
+	for name := range c.Packages {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+func (p *fbcPackage) channelNames() []string {
+	names := make([]string, 0, len(p.Channels))
+	for name := range p.Channels {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// fbcEdge is an upgrade edge from one bundle to a newer one in a channel.
+type fbcEdge struct {
+	From   string
+	To     string
+	Reason string
+}
+
+// channelGraph is the upgrade graph of one channel, derived the way OLM
+// resolves upgrades: from replaces, skips and skipRange.
+type channelGraph struct {
+	Package *fbcPackage
+	Channel *fbcChannel
+	// Nodes are the channel's entries, newest first, followed by bundles
+	// that entries reference but the channel does not contain.
+	Nodes   []string
+	Entries map[string]fbcChannelEntry
+	Edges   []fbcEdge
+	// Heads are the entries no other entry replaces or skips; a valid
+	// channel has exactly one.
+	Heads []string
+	// Chain is the replaces chain from the head.
+	Chain []string
+	// Cycle is the entry at which the replaces chain loops, if it does.
+	Cycle string
+	// Stranded are the entries with no upgrade path to the head.
+	Stranded []string
+	// Missing are the names entries reference that the channel does not
+	// contain.
+	Missing []string
+	// BadVersions and BadRanges hold entries whose bundle version or
+	// skipRange cannot be parsed.
+	BadVersions []string
+	BadRanges   []string
+}
+
+func (g *channelGraph) version(name string) string {
+	if bundle := g.Package.Bundles[name]; bundle != nil {
+		return bundle.Version
+	}
+	return ""
+}
+
+func buildChannelGraph(pkg *fbcPackage, channel *fbcChannel) *channelGraph {
+	graph := &channelGraph{Package: pkg, Channel: channel, Entries: map[string]fbcChannelEntry{}}
+	for _, entry := range channel.Entries {
+		graph.Entries[entry.Name] = entry
+	}
+
+	versions := map[string]semver.Version{}
+	for _, entry := range channel.Entries {
+		raw := graph.version(entry.Name)
+		if raw == "" {
+			continue
+		}
+		if v, err := semver.Parse(raw); err == nil {
+			versions[entry.Name] = v
+		} else {
+			graph.BadVersions = append(graph.BadVersions, fmt.Sprintf("%s: %q", entry.Name, raw))
+		}
+	}
+
+	for _, entry := range channel.Entries {
+		graph.Nodes = append(graph.Nodes, entry.Name)
+	}
+	sort.SliceStable(graph.Nodes, func(i, j int) bool {
+		vi, iok := versions[graph.Nodes[i]]
+		vj, jok := versions[graph.Nodes[j]]
+		if iok && jok && !vi.Equals(vj) {
+			return vi.GT(vj)
+		}
+		return iok && !jok
+	})
+
+	// Edges run from the older bundle to the one that upgrades it.
+	replacedOrSkipped := map[string]bool{}
+	missing := map[string]bool{}
+	reference := func(name string) {
+		if _, ok := graph.Entries[name]; !ok && !missing[name] {
+			missing[name] = true
+			graph.Missing = append(graph.Missing, name)
+		}
+	}
+	for _, entry := range channel.Entries {
+		if entry.Replaces != "" {
+			replacedOrSkipped[entry.Replaces] = true
+			reference(entry.Replaces)
+			graph.Edges = append(graph.Edges, fbcEdge{From: entry.Replaces, To: entry.Name, Reason: "replaces"})
+		}
+		for _, skip := range entry.Skips {
+			replacedOrSkipped[skip] = true
+			reference(skip)
+			graph.Edges = append(graph.Edges, fbcEdge{From: skip, To: entry.Name, Reason: "skips"})
+		}
+	}
+	for _, entry := range channel.Entries {
+		if entry.SkipRange == "" {
+			continue
+		}
+		inRange, err := semver.ParseRange(entry.SkipRange)
+		if err != nil {
+			graph.BadRanges = append(graph.BadRanges, fmt.Sprintf("%s: %q", entry.Name, entry.SkipRange))
+			continue
+		}
+		for _, other := range graph.Nodes {
+			if v, ok := versions[other]; ok && other != entry.Name && inRange(v) {
+				graph.Edges = append(graph.Edges, fbcEdge{From: other, To: entry.Name, Reason: "skipRange " + entry.SkipRange})
+			}
+		}
+	}
+	sort.Strings(graph.Missing)
+	graph.Nodes = append(graph.Nodes, graph.Missing...)
+
+	for _, entry := range channel.Entries {
+		if !replacedOrSkipped[entry.Name] {
+			graph.Heads = append(graph.Heads, entry.Name)
+		}
+	}
+	sort.Strings(graph.Heads)
+	if len(graph.Heads) != 1 {
+		return graph
+	}
+
+	// The replaces chain from the head is what OLM walks to find the
+	// latest bundle, so a loop in it breaks resolution.
+	onChain := map[string]bool{}
+	for name := graph.Heads[0]; name != ""; name = graph.Entries[name].Replaces {
+		if onChain[name] {
+			graph.Cycle = name
+			break
+		}
+		onChain[name] = true
+		graph.Chain = append(graph.Chain, name)
+	}
+
+	// Any bundle with a path of upgrade edges to the head can reach it.
+	upgradedFrom := map[string][]string{}
+	for _, edge := range graph.Edges {
+		upgradedFrom[edge.To] = append(upgradedFrom[edge.To], edge.From)
+	}
+	reachable := map[string]bool{graph.Heads[0]: true}
+	queue := []string{graph.Heads[0]}
+	for len(queue) > 0 {
+		name := queue[0]
+		queue = queue[1:]
+		for _, from := range upgradedFrom[name] {
+			if !reachable[from] {
+				reachable[from] = true
+				queue = append(queue, from)
+			}
+		}
+	}
+	for _, entry := range channel.Entries {
+		if !reachable[entry.Name] {
+			graph.Stranded = append(graph.Stranded, entry.Name)
+		}
+	}
+	return graph
+}
+
+// validateFileBasedCatalog checks the catalog, or one package of it, for the
+// problems opm validate and OLM's resolver trip over.
+func validateFileBasedCatalog(catalog *fileBasedCatalog, only string) []finding {
+	var findings []finding
+	if only == "" && len(catalog.Problems) > 0 {
+		findings = append(findings, finding{
+			Severity: severityCritical,
+			Title:    "Malformed files or blobs",
+			Evidence: catalog.Problems,
+		})
+	}
+
+	for _, name := range catalog.packageNames() {
+		if only != "" && name != only {
+			continue
+		}
+		pkg := catalog.Packages[name]
+
+		if !pkg.Defined {
+			findings = append(findings, finding{
+				Severity: severityCritical,
+				Title:    fmt.Sprintf("Package %s has channels or bundles but no olm.package blob", name),
+			})
+		}
+		switch {
+		case len(pkg.Channels) == 0:
+			findings = append(findings, finding{
+				Severity: severityCritical,
+				Title:    fmt.Sprintf("Package %s has no channels", name),
+			})
+		case pkg.Defined && pkg.DefaultChannel == "":
+			findings = append(findings, finding{
+				Severity: severityCritical,
+				Title:    fmt.Sprintf("Package %s has no default channel", name),
+				Evidence: []string{fmt.Sprintf("channels: %s", strings.Join(pkg.channelNames(), ", ")), "subscriptions without spec.channel cannot resolve"},
+			})
+		case pkg.Defined && pkg.Channels[pkg.DefaultChannel] == nil:
+			findings = append(findings, finding{
+				Severity: severityCritical,
+				Title:    fmt.Sprintf("Default channel %s of package %s does not exist", pkg.DefaultChannel, name),
+				Evidence: []string{fmt.Sprintf("channels: %s", strings.Join(pkg.channelNames(), ", "))},
+			})
+		}
+
+		inChannel := map[string]bool{}
+		for _, channelName := range pkg.channelNames() {
+			channel := pkg.Channels[channelName]
+			label := fmt.Sprintf("%s/%s", name, channelName)
+			if len(channel.Entries) == 0 {
+				findings = append(findings, finding{
+					Severity: severityCritical,
+					Title:    fmt.Sprintf("Channel %s has no entries", label),
+				})
+				continue
+			}
+
+			var undefined []string
+			for _, entry := range channel.Entries {
+				inChannel[entry.Name] = true
+				if pkg.Bundles[entry.Name] == nil {
+					undefined = append(undefined, entry.Name)
+				}
+			}
+			if len(undefined) > 0 {
+				findings = append(findings, finding{
+					Severity: severityCritical,
+					Title:    fmt.Sprintf("Channel %s lists bundles with no olm.bundle blob", label),
+					Evidence: undefined,
+				})
+			}
+
+			graph := buildChannelGraph(pkg, channel)
+			switch {
+			case len(graph.Heads) == 0:
+				findings = append(findings, finding{
+					Severity: severityCritical,
+					Title:    fmt.Sprintf("Channel %s has no head; every entry is replaced or skipped by another", label),
+				})
+			case len(graph.Heads) > 1:
+				findings = append(findings, finding{
+					Severity: severityCritical,
+					Title:    fmt.Sprintf("Channel %s has %d heads", label, len(graph.Heads)),
+					Evidence: append(graph.Heads, "no other entry replaces or skips these bundles, so OLM cannot tell which is the latest"),
+				})
+			}
+			if graph.Cycle != "" {
+				findings = append(findings, finding{
+					Severity: severityCritical,
+					Title:    fmt.Sprintf("Replaces chain of channel %s loops", label),
+					Evidence: []string{strings.Join(append(graph.Chain, graph.Cycle), " -> ")},
+				})
+			}
+			if len(graph.Stranded) > 0 {
+				findings = append(findings, finding{
+					Severity: severityWarning,
+					Title:    fmt.Sprintf("Channel %s has bundles with no upgrade path to the head %s", label, graph.Heads[0]),
+					Evidence: append(graph.Stranded, "no chain of replaces, skips or skipRange edges leads from them to the head"),
+				})
+			}
+			if len(graph.BadVersions) > 0 {
+				findings = append(findings, finding{
+					Severity: severityWarning,
+					Title:    fmt.Sprintf("Channel %s has bundles whose version is not valid semver", label),
+					Evidence: graph.BadVersions,
+				})
+			}
+			if len(graph.BadRanges) > 0 {
+				findings = append(findings, finding{
+					Severity: severityWarning,
+					Title:    fmt.Sprintf("Channel %s has entries with an invalid skipRange", label),
+					Evidence: graph.BadRanges,
+				})
+			}
+			if len(graph.Missing) > 0 {
+				findings = append(findings, finding{
+					Severity: severityInfo,
+					Title:    fmt.Sprintf("Channel %s replaces or skips bundles it does not contain", label),
+					Evidence: append(graph.Missing, "expected when older bundles were pruned from the catalog"),
+				})
+			}
+		}
+
+		var orphaned []string
+		for bundle := range pkg.Bundles {
+			if !inChannel[bundle] {
+				orphaned = append(orphaned, bundle)
+			}
+		}
+		if len(orphaned) > 0 {
+			sort.Strings(orphaned)
+			findings = append(findings, finding{
+				Severity: severityWarning,
+				Title:    fmt.Sprintf("Package %s has bundles that are in no channel", name),
+				Evidence: append(orphaned, "OLM can never install them"),
+			})
+		}
+	}
+	return findings
+}
+
+func (g *channelGraph) label(name string) string {
+	label := name
+	if version := g.version(name); version != "" {
+		label += " (" + version + ")"
+	}
+	return label
+}
+
+func (g *channelGraph) markers() map[string]string {
+	markers := map[string]string{}
+	for _, name := range g.Missing {
+		markers[name] = "not in channel"
+	}
+	for _, name := range g.Stranded {
+		markers[name] = "no upgrade path to head"
+	}
+	for _, name := range g.Heads {
+		markers[name] = "head"
+	}
+	return markers
+}
+
+func (g *channelGraph) renderText() string {
+	upgradesFrom := map[string][]string{}
+	for _, edge := range g.Edges {
+		upgradesFrom[edge.To] = append(upgradesFrom[edge.To], fmt.Sprintf("%s (%s)", edge.From, edge.Reason))
+	}
+	markers := g.markers()
+
+	var result strings.Builder
+	for _, node := range g.Nodes {
+		line := g.label(node)
+		if marker := markers[node]; marker != "" {
+			line += " [" + marker + "]"
+		}
+		result.WriteString(line + "\n")
+		for _, from := range upgradesFrom[node] {
+			result.WriteString(fmt.Sprintf("  <- %s\n", from))
+		}
+	}
+	return result.String()
+}
+
+func (g *channelGraph) nodeIDs() map[string]string {
+	ids := map[string]string{}
+	for i, node := range g.Nodes {
+		ids[node] = fmt.Sprintf("b%d", i)
+	}
+	return ids
+}
+
+func (g *channelGraph) renderDOT() string {
+	ids := g.nodeIDs()
+	markers := g.markers()
+	var result strings.Builder
+	result.WriteString("digraph channel {\n")
+	result.WriteString("  rankdir=BT;\n")
+	result.WriteString("  node [shape=box];\n")
+	for _, node := range g.Nodes {
+		style := ""
+		switch markers[node] {
+		case "head":
+			style = ", style=bold"
+		case "not in channel":
+			style = ", style=dashed"
+		case "no upgrade path to head":
+			style = ", color=red"
+		}
+		result.WriteString(fmt.Sprintf("  %s [label=%q%s];\n", ids[node], g.label(node), style))
+	}
+	for _, edge := range g.Edges {
+		style := ""
+		if edge.Reason != "replaces" {
+			style = ", style=dashed"
+		}
+		result.WriteString(fmt.Sprintf("  %s -> %s [label=%q%s];\n", ids[edge.From], ids[edge.To], edge.Reason, style))
+	}
+	result.WriteString("}\n")
+	return result.String()
+}
+
+func (g *channelGraph) renderMermaid() string {
+	ids := g.nodeIDs()
+	markers := g.markers()
+	escape := strings.NewReplacer(`"`, "#quot;", "|", "#124;")
+	var result strings.Builder
+	result.WriteString("graph BT\n")
+	classes := map[string]string{"head": "head", "not in channel": "missing", "no upgrade path to head": "stranded"}
+	for _, node := range g.Nodes {
+		class := ""
+		if name := classes[markers[node]]; name != "" {
+			class = ":::" + name
+		}
+		result.WriteString(fmt.Sprintf("  %s[\"%s\"]%s\n", ids[node], escape.Replace(g.label(node)), class))
+	}
+	for _, edge := range g.Edges {
+		arrow := "-->"
+		if edge.Reason != "replaces" {
+			arrow = "-.->"
+		}
+		result.WriteString(fmt.Sprintf("  %s %s|\"%s\"| %s\n", ids[edge.From], arrow, escape.Replace(edge.Reason), ids[edge.To]))
+	}
+	result.WriteString("  classDef head stroke-width:3px\n")
+	result.WriteString("  classDef missing stroke-dasharray:5 5\n")
+	result.WriteString("  classDef stranded stroke:#f00\n")
+	return result.String()
+}
+
+// loadCatalogParam loads the File-Based Catalog at the path parameter, which
+// is relative to the server's --catalog-dir, or the whole --catalog-dir
+// without one. Paths that leave --catalog-dir are rejected.
+func (t *CatalogTools) loadCatalogParam(params map[string]string) (*fileBasedCatalog, *types.MCPToolResult) {
+	if t.server.CatalogDir == "" {
+		return nil, &types.MCPToolResult{
+			Content: []types.MCPContent{{
+				Type: "text",
+				Text: "Error: the File-Based Catalog tools require the server to be started with --catalog-dir",
+			}},
+			IsError: true,
+		}
+	}
+
+	dir, err := resolveCatalogPath(t.server.CatalogDir, params["path"])
+	if err != nil {
+		return nil, &types.MCPToolResult{
+			Content: []types.MCPContent{{
+				Type: "text",
+				Text: fmt.Sprintf("Error: %v", err),
+			}},
+			IsError: true,
+		}
+	}
+
+	catalog, err := loadFileBasedCatalog(dir)
+	if err != nil {
+		return nil, &types.MCPToolResult{
+			Content: []types.MCPContent{{
+				Type: "text",
+				Text: fmt.Sprintf("Error reading File-Based Catalog: %v", err),
+			}},
+			IsError: true,
+		}
+	}
+	return catalog, nil
+}
+
+// resolveCatalogPath returns the directory path names under root. Symlinks
+// are resolved before the check, so that they cannot point outside root.
+func resolveCatalogPath(root, path string) (string, error) {
+	root, err := filepath.EvalSymlinks(root)
+	if err != nil {
+		return "", err
+	}
+	if filepath.IsAbs(path) {
+		return "", fmt.Errorf("path %q must be relative to --catalog-dir", path)
+	}
+	dir, err := filepath.EvalSymlinks(filepath.Join(root, filepath.Clean(path)))
+	if err != nil {
+		return "", err
+	}
+	rel, err := filepath.Rel(root, dir)
+	if err != nil || rel == ".." || strings.HasPrefix(rel, ".."+string(filepath.Separator)) {
+		return "", fmt.Errorf("path %q is outside --catalog-dir", path)
+	}
+	return dir, nil
+}
+
+// fbcPackageResult returns an error result when a package is not in the
+// catalog.
+func fbcPackageResult(catalog *fileBasedCatalog, name string) *types.MCPToolResult {
+	if catalog.Packages[name] != nil {
+		return nil
+	}
+	return &types.MCPToolResult{
+		Content: []types.MCPContent{{
+			Type: "text",
+			Text: fmt.Sprintf("Error: package '%s' not found in %s (packages: %s)", name, catalog.Dir, valueOrNone(strings.Join(catalog.packageNames(), ", "))),
+		}},
+		IsError: true,
+	}
+}
+
+// ListFBCPackages lists the packages and channels of a File-Based Catalog on
+// local disk, or the channels and bundles of one package.
+func (t *CatalogTools) ListFBCPackages(ctx context.Context, params map[string]string) (*types.MCPToolResult, error) {
+	catalog, errResult := t.loadCatalogParam(params)
+	if errResult != nil {
+		return errResult, nil
+	}
+	only := params["package"]
+	if only != "" {
+		if errResult := fbcPackageResult(catalog, only); errResult != nil {
+			return errResult, nil
+		}
+	}
+
+	var result strings.Builder
+	result.WriteString(fmt.Sprintf("File-Based Catalog %s: %d files, %d packages\n", catalog.Dir, catalog.Files, len(catalog.Packages)))
+	if len(catalog.Other) > 0 {
+		var other []string
+		for schema, count := range catalog.Other {
+			other = append(other, fmt.Sprintf("%s (%d)", schema, count))
+		}
+		sort.Strings(other)
+		result.WriteString(fmt.Sprintf("Other blobs: %s\n", strings.Join(other, ", ")))
+	}
+	if len(catalog.Problems) > 0 {
+		result.WriteString(fmt.Sprintf("%d malformed files or blobs; run validate_fbc for details\n", len(catalog.Problems)))
+	}
+	result.WriteString("\n")
+
+	if only == "" {
+		if len(catalog.Packages) == 0 {
+			result.WriteString("No packages found.\n")
+		} else {
+			result.WriteString("PACKAGE\tDEFAULT CHANNEL\tCHANNELS\tBUNDLES\tDEFAULT CHANNEL HEAD\n")
+			for _, name := range catalog.packageNames() {
+				pkg := catalog.Packages[name]
+				head := "<none>"
+				if channel := pkg.Channels[pkg.DefaultChannel]; channel != nil {
+					if graph := buildChannelGraph(pkg, channel); len(graph.Heads) > 0 {
+						head = strings.Join(graph.Heads, ", ")
+					}
+				}
+				result.WriteString(fmt.Sprintf("%s\t%s\t%s\t%d\t%s\n",
+					name,
+					valueOrNone(pkg.DefaultChannel),
+					valueOrNone(strings.Join(pkg.channelNames(), ", ")),
+					len(pkg.Bundles),
+					head,
+				))
+			}
+		}
+		return &types.MCPToolResult{
+			Content: []types.MCPContent{{
+				Type: "text",
+				Text: result.String(),
+			}},
+		}, nil
+	}
+
+	pkg := catalog.Packages[only]
+	result.WriteString(fmt.Sprintf("Package: %s\n", only))
+	result.WriteString(fmt.Sprintf("Default Channel: %s\n\n", valueOrNone(pkg.DefaultChannel)))
+
+	channelsOf := map[string][]string{}
+	result.WriteString("CHANNEL\tHEAD\tENTRIES\tDEFAULT\n")
+	for _, name := range pkg.channelNames() {
+		channel := pkg.Channels[name]
+		for _, entry := range channel.Entries {
+			channelsOf[entry.Name] = append(channelsOf[entry.Name], name)
+		}
+		graph := buildChannelGraph(pkg, channel)
+		result.WriteString(fmt.Sprintf("%s\t%s\t%d\t%t\n",
+			name,
+			valueOrNone(strings.Join(graph.Heads, ", ")),
+			len(channel.Entries),
+			name == pkg.DefaultChannel,
+		))
+	}
+
+	bundles := make([]string, 0, len(pkg.Bundles))
+	for name := range pkg.Bundles {
+		bundles = append(bundles, name)
+	}
+	sort.Strings(bundles)
+	result.WriteString("\nBUNDLE\tVERSION\tCHANNELS\tIMAGE\n")
+	for _, name := range bundles {
+		bundle := pkg.Bundles[name]
+		result.WriteString(fmt.Sprintf("%s\t%s\t%s\t%s\n",
+			name,
+			valueOrNone(bundle.Version),
+			valueOrNone(strings.Join(channelsOf[name], ", ")),
+			valueOrNone(bundle.Image),
+		))
+	}
+
+	return &types.MCPToolResult{
+		Content: []types.MCPContent{{
+			Type: "text",
+			Text: result.String(),
+		}},
+	}, nil
+}
+
+// GetFBCUpgradeGraph renders the upgrade graph of a channel in a File-Based
+// Catalog on local disk as text, DOT or Mermaid.
+func (t *CatalogTools) GetFBCUpgradeGraph(ctx context.Context, params map[string]string) (*types.MCPToolResult, error) {
+	pkgName := params["package"]
+	channelName := params["channel"]
+	format := strings.ToLower(params["format"])
+
+	if format == "" {
+		format = "text"
+	}
+	if pkgName == "" {
+		return &types.MCPToolResult{
+			Content: []types.MCPContent{{
+				Type: "text",
+				Text: "Error: 'package' parameter is required",
+			}},
+			IsError: true,
+		}, nil
+	}
+	if format != "text" && format != "dot" && format != "mermaid" && format != "all" {
+		return &types.MCPToolResult{
+			Content: []types.MCPContent{{
+				Type: "text",
+				Text: "Error: 'format' must be one of text, dot, mermaid or all",
+			}},
+			IsError: true,
+		}, nil
+	}
+
+	catalog, errResult := t.loadCatalogParam(params)
+	if errResult != nil {
+		return errResult, nil
+	}
+	if errResult := fbcPackageResult(catalog, pkgName); errResult != nil {
+		return errResult, nil
+	}
+	pkg := catalog.Packages[pkgName]
+	if channelName == "" {
+		channelName = pkg.DefaultChannel
+	}
+	channel := pkg.Channels[channelName]
+	if channel == nil {
+		return &types.MCPToolResult{
+			Content: []types.MCPContent{{
+				Type: "text",
+				Text: fmt.Sprintf("Error: channel '%s' not found in package '%s' (channels: %s)", channelName, pkgName, valueOrNone(strings.Join(pkg.channelNames(), ", "))),
+			}},
+			IsError: true,
+		}, nil
+	}
+
+	graph := buildChannelGraph(pkg, channel)
+
+	var result strings.Builder
+	result.WriteString(fmt.Sprintf("Upgrade graph of channel %s in package %s (newest first; edges point from a bundle to the bundles that upgrade it):\n\n", channelName, pkgName))
+	result.WriteString(fmt.Sprintf("%d entries, %d upgrade edges, head: %s\n", len(channel.Entries), len(graph.Edges), valueOrNone(strings.Join(graph.Heads, ", "))))
+	if len(graph.Heads) > 1 || graph.Cycle != "" || len(graph.Stranded) > 0 {
+		result.WriteString("The channel has problems; run validate_fbc for details.\n")
+	}
+	result.WriteString("\n")
+
+	if format == "text" || format == "all" {
+		result.WriteString(graph.renderText())
+	}
+	if format == "dot" || format == "all" {
+		result.WriteString("\n```dot\n")
+		result.WriteString(graph.renderDOT())
+		result.WriteString("```\n")
+	}
+	if format == "mermaid" || format == "all" {
+		result.WriteString("\n```mermaid\n")
+		result.WriteString(graph.renderMermaid())
+		result.WriteString("```\n")
+	}
+
+	return &types.MCPToolResult{
+		Content: []types.MCPContent{{
+			Type: "text",
+			Text: result.String(),
+		}},
+	}, nil
+}
+
+// ValidateFBC reports problems in a File-Based Catalog on local disk:
+// malformed blobs, missing default channels, channels with several heads and
+// bundles nothing upgrades from.
+func (t *CatalogTools) ValidateFBC(ctx context.Context, params map[string]string) (*types.MCPToolResult, error) {
+	only := params["package"]
+
+	catalog, errResult := t.loadCatalogParam(params)
+	if errResult != nil {
+		return errResult, nil
+	}
+	if only != "" {
+		if errResult := fbcPackageResult(catalog, only); errResult != nil {
+			return errResult, nil
+		}
+	}
+
+	var result strings.Builder
+	if only != "" {
+		result.WriteString(fmt.Sprintf("Validation of package %s in File-Based Catalog %s\n\n", only, catalog.Dir))
+	} else {
+		result.WriteString(fmt.Sprintf("Validation of File-Based Catalog %s: %d files, %d packages\n\n", catalog.Dir, catalog.Files, len(catalog.Packages)))
+	}
+	writeFindings(&result, validateFileBasedCatalog(catalog, only))
+
+	return &types.MCPToolResult{
+		Content: []types.MCPContent{{
+			Type: "text",
+			Text: result.String(),
+		}},
+	}, nil
+}
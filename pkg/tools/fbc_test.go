@@ -0,0 +1,284 @@
+package tools
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+// newTestFBCPackage returns a package with one channel, stable, holding
+// entries, and an olm.bundle for each entry with the version in versions.
+func newTestFBCPackage(defaultChannel string, versions map[string]string, entries ...fbcChannelEntry) *fbcPackage {
+	pkg := &fbcPackage{
+		Name:           "app",
+		DefaultChannel: defaultChannel,
+		Defined:        true,
+		Channels:       map[string]*fbcChannel{"stable": {Name: "stable", Entries: entries}},
+		Bundles:        map[string]*fbcBundle{},
+	}
+	for _, entry := range entries {
+		pkg.Bundles[entry.Name] = &fbcBundle{Name: entry.Name, Version: versions[entry.Name]}
+	}
+	return pkg
+}
+
+func TestBuildChannelGraph(t *testing.T) {
+	versions := map[string]string{"a": "0.1.0", "b": "0.2.0", "c": "0.3.0", "x": "0.0.1", "y": "0.0.2"}
+	tests := []struct {
+		name         string
+		entries      []fbcChannelEntry
+		wantHeads    []string
+		wantChain    []string
+		wantCycle    string
+		wantStranded []string
+		wantMissing  []string
+	}{
+		{
+			name: "single head",
+			entries: []fbcChannelEntry{
+				{Name: "c", Replaces: "b"},
+				{Name: "b", Replaces: "a"},
+				{Name: "a"},
+			},
+			wantHeads: []string{"c"},
+			wantChain: []string{"c", "b", "a"},
+		},
+		{
+			name: "skipRange does not make a head",
+			entries: []fbcChannelEntry{
+				{Name: "c", SkipRange: "<0.3.0"},
+				{Name: "b", Replaces: "a"},
+				{Name: "a"},
+			},
+			wantHeads: []string{"b", "c"},
+		},
+		{
+			name: "every entry replaced",
+			entries: []fbcChannelEntry{
+				{Name: "b", Replaces: "a"},
+				{Name: "a", Replaces: "b"},
+			},
+		},
+		{
+			name: "replaces chain loops",
+			entries: []fbcChannelEntry{
+				{Name: "c", Replaces: "b"},
+				{Name: "b", Replaces: "a"},
+				{Name: "a", Replaces: "b"},
+			},
+			wantHeads: []string{"c"},
+			wantChain: []string{"c", "b", "a"},
+			wantCycle: "b",
+		},
+		{
+			name: "skips strand bundles that only skip each other",
+			entries: []fbcChannelEntry{
+				{Name: "c", Replaces: "b"},
+				{Name: "b"},
+				{Name: "x", Skips: []string{"y"}},
+				{Name: "y", Skips: []string{"x"}},
+			},
+			wantHeads:    []string{"c"},
+			wantChain:    []string{"c", "b"},
+			wantStranded: []string{"x", "y"},
+		},
+		{
+			name: "skipRange of the head reaches them",
+			entries: []fbcChannelEntry{
+				{Name: "c", Replaces: "b", SkipRange: "<0.0.2"},
+				{Name: "b"},
+				{Name: "x", Skips: []string{"y"}},
+				{Name: "y", Skips: []string{"x"}},
+			},
+			wantHeads: []string{"c"},
+			wantChain: []string{"c", "b"},
+		},
+		{
+			name: "pruned replaces target",
+			entries: []fbcChannelEntry{
+				{Name: "c", Replaces: "b"},
+				{Name: "b", Replaces: "a"},
+			},
+			wantHeads:   []string{"c"},
+			wantChain:   []string{"c", "b", "a"},
+			wantMissing: []string{"a"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			pkg := newTestFBCPackage("stable", versions, tt.entries...)
+			graph := buildChannelGraph(pkg, pkg.Channels["stable"])
+			if !reflect.DeepEqual(graph.Heads, tt.wantHeads) {
+				t.Errorf("Heads = %q, want %q", graph.Heads, tt.wantHeads)
+			}
+			if !reflect.DeepEqual(graph.Chain, tt.wantChain) {
+				t.Errorf("Chain = %q, want %q", graph.Chain, tt.wantChain)
+			}
+			if graph.Cycle != tt.wantCycle {
+				t.Errorf("Cycle = %q, want %q", graph.Cycle, tt.wantCycle)
+			}
+			if !reflect.DeepEqual(graph.Stranded, tt.wantStranded) {
+				t.Errorf("Stranded = %q, want %q", graph.Stranded, tt.wantStranded)
+			}
+			if !reflect.DeepEqual(graph.Missing, tt.wantMissing) {
+				t.Errorf("Missing = %q, want %q", graph.Missing, tt.wantMissing)
+			}
+		})
+	}
+}
+
+func TestValidateFileBasedCatalog(t *testing.T) {
+	versions := map[string]string{"a": "0.1.0", "b": "0.2.0", "c": "0.3.0"}
+	tests := []struct {
+		name string
+		pkg  *fbcPackage
+		want []string
+	}{
+		{
+			name: "valid package",
+			pkg:  newTestFBCPackage("stable", versions, fbcChannelEntry{Name: "b", Replaces: "a"}, fbcChannelEntry{Name: "a"}),
+		},
+		{
+			name: "missing default channel",
+			pkg:  newTestFBCPackage("", versions, fbcChannelEntry{Name: "a"}),
+			want: []string{"Package app has no default channel"},
+		},
+		{
+			name: "unknown default channel",
+			pkg:  newTestFBCPackage("fast", versions, fbcChannelEntry{Name: "a"}),
+			want: []string{"Default channel fast of package app does not exist"},
+		},
+		{
+			name: "several heads",
+			pkg:  newTestFBCPackage("stable", versions, fbcChannelEntry{Name: "b"}, fbcChannelEntry{Name: "a"}),
+			want: []string{"Channel app/stable has 2 heads"},
+		},
+		{
+			name: "looping replaces chain",
+			pkg: newTestFBCPackage("stable", versions,
+				fbcChannelEntry{Name: "c", Replaces: "b"},
+				fbcChannelEntry{Name: "b", Replaces: "a"},
+				fbcChannelEntry{Name: "a", Replaces: "b"},
+			),
+			want: []string{"Replaces chain of channel app/stable loops"},
+		},
+		{
+			name: "stranded bundles",
+			pkg: newTestFBCPackage("stable", versions,
+				fbcChannelEntry{Name: "c"},
+				fbcChannelEntry{Name: "b", Skips: []string{"a"}},
+				fbcChannelEntry{Name: "a", Skips: []string{"b"}},
+			),
+			want: []string{"Channel app/stable has bundles with no upgrade path to the head c"},
+		},
+		{
+			name: "skipRange rescues stranded bundles",
+			pkg: newTestFBCPackage("stable", versions,
+				fbcChannelEntry{Name: "c", SkipRange: ">=0.1.0 <0.3.0"},
+				fbcChannelEntry{Name: "b", Skips: []string{"a"}},
+				fbcChannelEntry{Name: "a", Skips: []string{"b"}},
+			),
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			catalog := &fileBasedCatalog{Packages: map[string]*fbcPackage{"app": tt.pkg}}
+			var got []string
+			for _, f := range validateFileBasedCatalog(catalog, "") {
+				got = append(got, f.Title)
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("validateFileBasedCatalog() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestResolveCatalogPath(t *testing.T) {
+	root := t.TempDir()
+	outside := t.TempDir()
+	if err := os.MkdirAll(filepath.Join(root, "catalogs", "app"), 0o755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.Symlink(outside, filepath.Join(root, "escape")); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.Symlink(filepath.Join(root, "catalogs"), filepath.Join(root, "linked")); err != nil {
+		t.Fatal(err)
+	}
+	sibling, err := filepath.Rel(root, outside)
+	if err != nil {
+		t.Fatal(err)
+	}
+	// Results are symlink-free, and the temp directory may sit behind one.
+	resolvedRoot, err := filepath.EvalSymlinks(root)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		name    string
+		path    string
+		want    string
+		wantErr string
+	}{
+		{name: "root", path: ".", want: "."},
+		{name: "subdirectory", path: "catalogs/app", want: "catalogs/app"},
+		{name: "dot-dot that stays inside", path: "catalogs/../catalogs/app", want: "catalogs/app"},
+		{name: "symlink inside the root", path: "linked/app", want: "catalogs/app"},
+		{name: "parent directory", path: "..", wantErr: "is outside --catalog-dir"},
+		{name: "sibling directory", path: sibling, wantErr: "is outside --catalog-dir"},
+		{name: "absolute path", path: outside, wantErr: "must be relative to --catalog-dir"},
+		{name: "symlink outside the root", path: "escape", wantErr: "is outside --catalog-dir"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := resolveCatalogPath(root, tt.path)
+			if tt.wantErr != "" {
+				if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+					t.Fatalf("resolveCatalogPath(%q) = %q, %v, want error containing %q", tt.path, got, err, tt.wantErr)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("resolveCatalogPath(%q) error: %v", tt.path, err)
+			}
+			rel, err := filepath.Rel(resolvedRoot, got)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if filepath.ToSlash(rel) != tt.want {
+				t.Errorf("resolveCatalogPath(%q) = %q, want %q under the root", tt.path, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestLoadFileBasedCatalogFileCap(t *testing.T) {
+	dir := t.TempDir()
+	for i := 0; i < maxFBCFiles; i++ {
+		if err := os.WriteFile(filepath.Join(dir, fmt.Sprintf("%05d.yaml", i)), nil, 0o644); err != nil {
+			t.Fatal(err)
+		}
+	}
+	catalog, err := loadFileBasedCatalog(dir)
+	if err != nil {
+		t.Fatalf("loadFileBasedCatalog() with %d files: %v", maxFBCFiles, err)
+	}
+	if catalog.Files != maxFBCFiles {
+		t.Errorf("Files = %d, want %d", catalog.Files, maxFBCFiles)
+	}
+
+	if err := os.WriteFile(filepath.Join(dir, "extra.json"), nil, 0o644); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := loadFileBasedCatalog(dir); err == nil || !strings.Contains(err.Error(), "more than") {
+		t.Errorf("loadFileBasedCatalog() with %d files = %v, want the file cap error", maxFBCFiles+1, err)
+	}
+}
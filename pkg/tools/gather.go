@@ -0,0 +1,741 @@
+package tools
+
+import (
+	"archive/tar"
+	"compress/gzip"
+	"context"
+	"fmt"
+	"os"
+	"path"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	operatorsv1 "github.com/operator-framework/api/pkg/operators/v1"
+	"github.com/operator-framework/api/pkg/operators/v1alpha1"
+	operatorsv2 "github.com/operator-framework/api/pkg/operators/v2"
+	"github.com/operator-framework/operator-lifecycle-manager/olmv0-mcp-server/pkg/types"
+	appsv1 "k8s.io/api/apps/v1"
+	batchv1 "k8s.io/api/batch/v1"
+	corev1 "k8s.io/api/core/v1"
+	discoveryv1 "k8s.io/api/discovery/v1"
+	rbacv1 "k8s.io/api/rbac/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/yaml"
+)
+
+const defaultGatherLogTailLines = 5000
+
+// GatherOptions controls what GatherOLMState collects and where the tarball
+// is written.
+type GatherOptions struct {
+	// Namespace limits collection to one namespace; empty means all.
+	Namespace string
+	// OutputDir is the directory the tarball is written to.
+	OutputDir string
+	// LogTailLines is the number of log lines kept per OLM container.
+	LogTailLines int64
+}
+
+// GatherReport describes a written OLM state tarball.
+type GatherReport struct {
+	Path   string
+	Root   string
+	Files  []string
+	Counts map[string]int
+	Errors []string
+}
+
+// gatherLayout documents the directory layout inside the tarball. It is
+// written to the tarball as README.txt and shown by collect_olm_state.
+const gatherLayout = `<root>/
+  README.txt                                   this layout
+  summary.txt                                  object counts and collection errors
+  resources/<namespace>/<kind>/<name>.yaml     CSVs, Subscriptions, InstallPlans,
+                                               CatalogSources, OperatorGroups,
+                                               OperatorConditions, the Deployments and
+                                               Pods of OLM and installed operators,
+                                               registry Services and EndpointSlices,
+                                               OLM-generated Roles and RoleBindings,
+                                               and bundle unpack Jobs, their Pods and
+                                               ConfigMaps
+  resources/<kind>/<name>.yaml                 OLM-generated ClusterRoles and
+                                               ClusterRoleBindings
+  events/<namespace>.yaml                      events about OLM objects, workloads,
+                                               unpack Jobs and catalog pods
+  catalog-pods/<namespace>/<pod>.yaml          CatalogSource registry pods with status
+  logs/<namespace>/<pod>/<container>.log       olm-operator and catalog-operator logs,
+                                               and failed unpack containers
+  logs/<namespace>/<pod>/<container>.previous.log
+                                               logs of the previous container, if it restarted
+`
+
+// gatherer writes files into a gzipped tarball under a common root.
+type gatherer struct {
+	tw     *tar.Writer
+	root   string
+	now    time.Time
+	report *GatherReport
+	// workloads holds the deployments already written, by namespace/name.
+	workloads map[string]bool
+}
+
+func (g *gatherer) addFile(name string, data []byte) error {
+	name = path.Join(g.root, name)
+	if err := g.tw.WriteHeader(&tar.Header{
+		Name:    name,
+		Mode:    0o644,
+		Size:    int64(len(data)),
+		ModTime: g.now,
+	}); err != nil {
+		return err
+	}
+	if _, err := g.tw.Write(data); err != nil {
+		return err
+	}
+	g.report.Files = append(g.report.Files, name)
+	return nil
+}
+
+func (g *gatherer) addYAML(name string, obj interface{}) error {
+	data, err := yaml.Marshal(obj)
+	if err != nil {
+		return fmt.Errorf("encoding %s: %v", name, err)
+	}
+	return g.addFile(name, data)
+}
+
+func (g *gatherer) recordError(format string, args ...interface{}) {
+	g.report.Errors = append(g.report.Errors, fmt.Sprintf(format, args...))
+}
+
+// addObject writes an object under resources/ with its type information
+// restored and managed fields dropped.
+func (g *gatherer) addObject(kind string, gv fmt.Stringer, meta *metav1.ObjectMeta, typeMeta *metav1.TypeMeta, obj interface{}) error {
+	typeMeta.APIVersion = gv.String()
+	typeMeta.Kind = kind
+	meta.ManagedFields = nil
+	g.report.Counts[kind]++
+	return g.addYAML(fmt.Sprintf("resources/%s/%s/%s.yaml", meta.Namespace, strings.ToLower(kind)+"s", meta.Name), obj)
+}
+
+// gatheredWorkload is a deployment written to the tarball and its pods.
+type gatheredWorkload struct {
+	deployment *appsv1.Deployment
+	pods       []corev1.Pod
+}
+
+// addWorkload writes a deployment and the pods it selects under resources/,
+// once each, and tracks them for event collection. Failures to list the pods
+// are recorded as collection errors; only failures to write the tarball are
+// returned.
+func (g *gatherer) addWorkload(ctx context.Context, server *types.MCPServer, deployment *appsv1.Deployment, track func(string, *metav1.ObjectMeta)) (gatheredWorkload, error) {
+	workload := gatheredWorkload{deployment: deployment}
+	key := deployment.Namespace + "/" + deployment.Name
+	if g.workloads[key] {
+		return workload, nil
+	}
+	g.workloads[key] = true
+
+	pods, err := deploymentPods(ctx, server.K8sClient, deployment)
+	if err != nil {
+		g.recordError("listing pods of %s/%s: %v", deployment.Namespace, deployment.Name, err)
+	}
+	workload.pods = pods
+
+	track("Deployment", &deployment.ObjectMeta)
+	if err := g.addObject("Deployment", appsv1.SchemeGroupVersion, &deployment.ObjectMeta, &deployment.TypeMeta, deployment); err != nil {
+		return workload, err
+	}
+	for i := range pods {
+		pod := &pods[i]
+		track("Pod", &pod.ObjectMeta)
+		if err := g.addObject("Pod", corev1.SchemeGroupVersion, &pod.ObjectMeta, &pod.TypeMeta, pod); err != nil {
+			return workload, err
+		}
+	}
+	return workload, nil
+}
+
+// GatherOLMState collects OLM objects, the workloads of OLM and installed
+// operators, the registry Services, generated RBAC and unpack Jobs the
+// diagnostics read, related events, OLM component logs and catalog pod status
+// into a timestamped tarball in options.OutputDir.
+// Collection problems are recorded in the report and in summary.txt rather
+// than aborting, so a partially broken cluster still produces a bundle.
+func GatherOLMState(ctx context.Context, server *types.MCPServer, options GatherOptions) (*GatherReport, error) {
+	if options.OutputDir == "" {
+		options.OutputDir = "."
+	}
+	if options.LogTailLines <= 0 {
+		options.LogTailLines = defaultGatherLogTailLines
+	}
+
+	if err := os.MkdirAll(options.OutputDir, 0o755); err != nil {
+		return nil, err
+	}
+	// A random suffix keeps concurrent collections, which share the temp
+	// directory, from overwriting each other.
+	now := time.Now().UTC()
+	file, err := os.CreateTemp(options.OutputDir, "olm-state-"+now.Format("20060102-150405")+"-*.tar.gz")
+	if err != nil {
+		return nil, err
+	}
+	defer file.Close()
+	report := &GatherReport{
+		Path:   file.Name(),
+		Root:   strings.TrimSuffix(filepath.Base(file.Name()), ".tar.gz"),
+		Counts: map[string]int{},
+	}
+	gz := gzip.NewWriter(file)
+	g := &gatherer{tw: tar.NewWriter(gz), root: report.Root, now: now, report: report, workloads: map[string]bool{}}
+
+	if err := g.gather(ctx, server, options); err != nil {
+		os.Remove(report.Path)
+		return nil, err
+	}
+	if err := g.tw.Close(); err != nil {
+		return nil, err
+	}
+	if err := gz.Close(); err != nil {
+		return nil, err
+	}
+	return report, file.Close()
+}
+
+func (g *gatherer) gather(ctx context.Context, server *types.MCPServer, options GatherOptions) error {
+	if err := g.addFile("README.txt", []byte(gatherLayout)); err != nil {
+		return err
+	}
+
+	// Namespaces holding OLM objects, for the event collection below, and
+	// the objects events may refer to.
+	namespaces := map[string]bool{}
+	involved := map[string]bool{}
+	track := func(kind string, meta *metav1.ObjectMeta) {
+		namespaces[meta.Namespace] = true
+		involved[kind+"/"+meta.Namespace+"/"+meta.Name] = true
+	}
+
+	inventory := listOLMInventory(ctx, server.OLMClient, options.Namespace)
+	report := g.report
+	report.Errors = append(report.Errors, inventory.errors...)
+	if inventory.csvs != nil {
+		for i := range inventory.csvs.Items {
+			csv := &inventory.csvs.Items[i]
+			track(v1alpha1.ClusterServiceVersionKind, &csv.ObjectMeta)
+			if err := g.addObject(v1alpha1.ClusterServiceVersionKind, v1alpha1.SchemeGroupVersion, &csv.ObjectMeta, &csv.TypeMeta, csv); err != nil {
+				return err
+			}
+		}
+	}
+	if inventory.subscriptions != nil {
+		for i := range inventory.subscriptions.Items {
+			sub := &inventory.subscriptions.Items[i]
+			track(v1alpha1.SubscriptionKind, &sub.ObjectMeta)
+			if err := g.addObject(v1alpha1.SubscriptionKind, v1alpha1.SchemeGroupVersion, &sub.ObjectMeta, &sub.TypeMeta, sub); err != nil {
+				return err
+			}
+		}
+	}
+	if inventory.installPlans != nil {
+		for i := range inventory.installPlans.Items {
+			plan := &inventory.installPlans.Items[i]
+			track(v1alpha1.InstallPlanKind, &plan.ObjectMeta)
+			if err := g.addObject(v1alpha1.InstallPlanKind, v1alpha1.SchemeGroupVersion, &plan.ObjectMeta, &plan.TypeMeta, plan); err != nil {
+				return err
+			}
+		}
+	}
+	if inventory.catalogs != nil {
+		for i := range inventory.catalogs.Items {
+			catalog := &inventory.catalogs.Items[i]
+			track(v1alpha1.CatalogSourceKind, &catalog.ObjectMeta)
+			if err := g.addObject(v1alpha1.CatalogSourceKind, v1alpha1.SchemeGroupVersion, &catalog.ObjectMeta, &catalog.TypeMeta, catalog); err != nil {
+				return err
+			}
+		}
+	}
+	if groups, err := server.OLMClient.ListOperatorGroups(ctx, options.Namespace); err != nil {
+		g.recordError("listing OperatorGroups: %v", err)
+	} else {
+		for i := range groups.Items {
+			og := &groups.Items[i]
+			track(operatorsv1.OperatorGroupKind, &og.ObjectMeta)
+			if err := g.addObject(operatorsv1.OperatorGroupKind, operatorsv1.SchemeGroupVersion, &og.ObjectMeta, &og.TypeMeta, og); err != nil {
+				return err
+			}
+		}
+	}
+	if conditions, err := server.OLMClient.ListOperatorConditions(ctx, options.Namespace); err != nil {
+		g.recordError("listing OperatorConditions: %v", err)
+	} else {
+		for i := range conditions.Items {
+			condition := &conditions.Items[i]
+			track("OperatorCondition", &condition.ObjectMeta)
+			if err := g.addObject("OperatorCondition", operatorsv2.GroupVersion, &condition.ObjectMeta, &condition.TypeMeta, condition); err != nil {
+				return err
+			}
+		}
+	}
+
+	if server.K8sClient == nil {
+		g.recordError("no Kubernetes client; events, logs and catalog pods were not collected")
+		return g.addSummary(options)
+	}
+
+	// Catalog registry pods, with their status.
+	if inventory.catalogs != nil {
+		for _, catalog := range inventory.catalogs.Items {
+			pods, err := server.K8sClient.CoreV1().Pods(catalog.Namespace).List(ctx, metav1.ListOptions{
+				LabelSelector: fmt.Sprintf("%s=%s", catalogSourceLabel, catalog.Name),
+			})
+			if err != nil {
+				g.recordError("listing pods of CatalogSource %s/%s: %v", catalog.Namespace, catalog.Name, err)
+				continue
+			}
+			for i := range pods.Items {
+				pod := &pods.Items[i]
+				involved["Pod/"+pod.Namespace+"/"+pod.Name] = true
+				pod.APIVersion, pod.Kind = "v1", "Pod"
+				pod.ManagedFields = nil
+				report.Counts["CatalogPod"]++
+				if err := g.addYAML(fmt.Sprintf("catalog-pods/%s/%s.yaml", pod.Namespace, pod.Name), pod); err != nil {
+					return err
+				}
+			}
+		}
+	}
+
+	// Deployments and pods of OLM itself and of every installed operator, so
+	// that offline mode can serve get_olm_logs and check_csv_health.
+	deployments, err := findOLMDeployments(ctx, server.K8sClient, "", olmComponents)
+	if err != nil {
+		g.recordError("finding OLM deployments: %v", err)
+	} else if len(deployments) == 0 {
+		g.recordError("no %s deployment found", strings.Join(olmComponents, " or "))
+	}
+	var olmWorkloads []gatheredWorkload
+	for i := range deployments {
+		workload, err := g.addWorkload(ctx, server, &deployments[i], track)
+		if err != nil {
+			return err
+		}
+		olmWorkloads = append(olmWorkloads, workload)
+	}
+	if inventory.csvs != nil {
+		for i := range inventory.csvs.Items {
+			csv := &inventory.csvs.Items[i]
+			if csv.IsCopied() {
+				continue
+			}
+			for _, spec := range csv.Spec.InstallStrategy.StrategySpec.DeploymentSpecs {
+				deployment, err := server.K8sClient.AppsV1().Deployments(csv.Namespace).Get(ctx, spec.Name, metav1.GetOptions{})
+				if apierrors.IsNotFound(err) {
+					// check_csv_health reports the missing deployment.
+					continue
+				}
+				if err != nil {
+					g.recordError("getting deployment %s/%s of CSV %s: %v", csv.Namespace, spec.Name, csv.Name, err)
+					continue
+				}
+				if _, err := g.addWorkload(ctx, server, deployment, track); err != nil {
+					return err
+				}
+			}
+		}
+	}
+
+	// What the catalog, permission and unpack diagnostics read, so that
+	// they give the same answers offline.
+	if err := g.addRegistryServices(ctx, server, inventory); err != nil {
+		return err
+	}
+	if err := g.addGeneratedRBAC(ctx, server, inventory); err != nil {
+		return err
+	}
+	if err := g.addUnpackJobs(ctx, server, inventory, track, options.LogTailLines); err != nil {
+		return err
+	}
+
+	// Events about the collected objects, one file per namespace.
+	sortedNamespaces := make([]string, 0, len(namespaces))
+	for ns := range namespaces {
+		sortedNamespaces = append(sortedNamespaces, ns)
+	}
+	sort.Strings(sortedNamespaces)
+	for _, ns := range sortedNamespaces {
+		events, err := server.K8sClient.CoreV1().Events(ns).List(ctx, metav1.ListOptions{})
+		if err != nil {
+			g.recordError("listing events in %s: %v", ns, err)
+			continue
+		}
+		related := &corev1.EventList{TypeMeta: metav1.TypeMeta{APIVersion: "v1", Kind: "EventList"}}
+		for _, e := range events.Items {
+			ref := e.InvolvedObject
+			if involved[ref.Kind+"/"+ref.Namespace+"/"+ref.Name] {
+				e.ManagedFields = nil
+				related.Items = append(related.Items, e)
+			}
+		}
+		if len(related.Items) == 0 {
+			continue
+		}
+		sort.Slice(related.Items, func(i, j int) bool {
+			return related.Items[i].LastTimestamp.Before(&related.Items[j].LastTimestamp)
+		})
+		report.Counts["Event"] += len(related.Items)
+		if err := g.addYAML(fmt.Sprintf("events/%s.yaml", ns), related); err != nil {
+			return err
+		}
+	}
+
+	// OLM component logs, including the previous container after a restart.
+	for _, workload := range olmWorkloads {
+		for _, pod := range workload.pods {
+			restarted := map[string]bool{}
+			for _, status := range pod.Status.ContainerStatuses {
+				restarted[status.Name] = status.RestartCount > 0
+			}
+			for _, container := range pod.Spec.Containers {
+				if err := g.addLog(ctx, server, &pod, container.Name, false, options.LogTailLines); err != nil {
+					return err
+				}
+				if restarted[container.Name] {
+					if err := g.addLog(ctx, server, &pod, container.Name, true, options.LogTailLines); err != nil {
+						return err
+					}
+				}
+			}
+		}
+	}
+
+	return g.addSummary(options)
+}
+
+// addRegistryServices writes the registry Service of every CatalogSource and
+// the EndpointSlices backing it, for check_catalog_health.
+func (g *gatherer) addRegistryServices(ctx context.Context, server *types.MCPServer, inventory *olmInventory) error {
+	if inventory.catalogs == nil {
+		return nil
+	}
+	for _, catalog := range inventory.catalogs.Items {
+		status := catalog.Status.RegistryServiceStatus
+		if status == nil || status.ServiceName == "" {
+			continue
+		}
+		namespace := status.ServiceNamespace
+		if namespace == "" {
+			namespace = catalog.Namespace
+		}
+		service, err := server.K8sClient.CoreV1().Services(namespace).Get(ctx, status.ServiceName, metav1.GetOptions{})
+		if apierrors.IsNotFound(err) {
+			// check_catalog_health reports the missing service.
+			continue
+		}
+		if err != nil {
+			g.recordError("getting registry service %s/%s of CatalogSource %s/%s: %v", namespace, status.ServiceName, catalog.Namespace, catalog.Name, err)
+			continue
+		}
+		if err := g.addObject("Service", corev1.SchemeGroupVersion, &service.ObjectMeta, &service.TypeMeta, service); err != nil {
+			return err
+		}
+
+		slices, err := server.K8sClient.DiscoveryV1().EndpointSlices(namespace).List(ctx, metav1.ListOptions{
+			LabelSelector: fmt.Sprintf("%s=%s", discoveryv1.LabelServiceName, service.Name),
+		})
+		if err != nil {
+			g.recordError("listing EndpointSlices of service %s/%s: %v", namespace, service.Name, err)
+			continue
+		}
+		for i := range slices.Items {
+			slice := &slices.Items[i]
+			if err := g.addObject("EndpointSlice", discoveryv1.SchemeGroupVersion, &slice.ObjectMeta, &slice.TypeMeta, slice); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+// addGeneratedRBAC writes the Roles, RoleBindings, ClusterRoles and
+// ClusterRoleBindings OLM generated for each CSV, for
+// get_operator_permissions.
+func (g *gatherer) addGeneratedRBAC(ctx context.Context, server *types.MCPServer, inventory *olmInventory) error {
+	if inventory.csvs == nil {
+		return nil
+	}
+	rbac := server.K8sClient.RbacV1()
+	listed := map[string]bool{}
+	roles := map[string][]rbacv1.Role{}
+	roleBindings := map[string][]rbacv1.RoleBinding{}
+	for i := range inventory.csvs.Items {
+		csv := &inventory.csvs.Items[i]
+		if csv.IsCopied() {
+			continue
+		}
+
+		if !listed[csv.Namespace] {
+			listed[csv.Namespace] = true
+			list, err := rbac.Roles(csv.Namespace).List(ctx, metav1.ListOptions{})
+			if err != nil {
+				g.recordError("listing Roles in %s: %v", csv.Namespace, err)
+			} else {
+				roles[csv.Namespace] = list.Items
+			}
+			bindings, err := rbac.RoleBindings(csv.Namespace).List(ctx, metav1.ListOptions{})
+			if err != nil {
+				g.recordError("listing RoleBindings in %s: %v", csv.Namespace, err)
+			} else {
+				roleBindings[csv.Namespace] = bindings.Items
+			}
+		}
+		for j := range roles[csv.Namespace] {
+			role := &roles[csv.Namespace][j]
+			if !ownedByCSV(role.ObjectMeta, csv) {
+				continue
+			}
+			if err := g.addObject("Role", rbacv1.SchemeGroupVersion, &role.ObjectMeta, &role.TypeMeta, role); err != nil {
+				return err
+			}
+		}
+		for j := range roleBindings[csv.Namespace] {
+			binding := &roleBindings[csv.Namespace][j]
+			if !ownedByCSV(binding.ObjectMeta, csv) {
+				continue
+			}
+			if err := g.addObject("RoleBinding", rbacv1.SchemeGroupVersion, &binding.ObjectMeta, &binding.TypeMeta, binding); err != nil {
+				return err
+			}
+		}
+
+		selector := metav1.ListOptions{LabelSelector: fmt.Sprintf("%s=%s,%s=%s", ownerLabel, csv.Name, ownerNamespaceLabel, csv.Namespace)}
+		clusterRoles, err := rbac.ClusterRoles().List(ctx, selector)
+		if err != nil {
+			g.recordError("listing ClusterRoles of CSV %s/%s: %v", csv.Namespace, csv.Name, err)
+		} else {
+			for j := range clusterRoles.Items {
+				role := &clusterRoles.Items[j]
+				if err := g.addObject("ClusterRole", rbacv1.SchemeGroupVersion, &role.ObjectMeta, &role.TypeMeta, role); err != nil {
+					return err
+				}
+			}
+		}
+		clusterRoleBindings, err := rbac.ClusterRoleBindings().List(ctx, selector)
+		if err != nil {
+			g.recordError("listing ClusterRoleBindings of CSV %s/%s: %v", csv.Namespace, csv.Name, err)
+		} else {
+			for j := range clusterRoleBindings.Items {
+				binding := &clusterRoleBindings.Items[j]
+				if err := g.addObject("ClusterRoleBinding", rbacv1.SchemeGroupVersion, &binding.ObjectMeta, &binding.TypeMeta, binding); err != nil {
+					return err
+				}
+			}
+		}
+	}
+	return nil
+}
+
+// addUnpackJobs writes the bundle unpack Jobs in every catalog namespace,
+// with the ConfigMaps they unpack into, their pods and the logs of failed
+// containers, for diagnose_bundle_unpack.
+func (g *gatherer) addUnpackJobs(ctx context.Context, server *types.MCPServer, inventory *olmInventory, track func(string, *metav1.ObjectMeta), tail int64) error {
+	namespaces := map[string]bool{}
+	if inventory.catalogs != nil {
+		for _, catalog := range inventory.catalogs.Items {
+			namespaces[catalog.Namespace] = true
+		}
+	}
+	if inventory.installPlans != nil {
+		for _, plan := range inventory.installPlans.Items {
+			for _, lookup := range plan.Status.BundleLookups {
+				if ref := lookup.CatalogSourceRef; ref != nil && ref.Namespace != "" {
+					namespaces[ref.Namespace] = true
+				}
+			}
+		}
+	}
+	sorted := make([]string, 0, len(namespaces))
+	for ns := range namespaces {
+		sorted = append(sorted, ns)
+	}
+	sort.Strings(sorted)
+
+	for _, ns := range sorted {
+		jobs, err := server.K8sClient.BatchV1().Jobs(ns).List(ctx, metav1.ListOptions{})
+		if err != nil {
+			g.recordError("listing Jobs in %s: %v", ns, err)
+			continue
+		}
+		for i := range jobs.Items {
+			job := &jobs.Items[i]
+			// OLM makes the ConfigMap a bundle is unpacked into the owner of
+			// the unpack Job.
+			cmName := ""
+			for _, owner := range job.OwnerReferences {
+				if owner.Kind == "ConfigMap" {
+					cmName = owner.Name
+				}
+			}
+			if cmName == "" {
+				continue
+			}
+			track("Job", &job.ObjectMeta)
+			if err := g.addObject("Job", batchv1.SchemeGroupVersion, &job.ObjectMeta, &job.TypeMeta, job); err != nil {
+				return err
+			}
+
+			cm, err := server.K8sClient.CoreV1().ConfigMaps(ns).Get(ctx, cmName, metav1.GetOptions{})
+			switch {
+			case apierrors.IsNotFound(err):
+				// diagnose_bundle_unpack reports the missing ConfigMap.
+			case err != nil:
+				g.recordError("getting ConfigMap %s/%s of Job %s: %v", ns, cmName, job.Name, err)
+			default:
+				if err := g.addObject("ConfigMap", corev1.SchemeGroupVersion, &cm.ObjectMeta, &cm.TypeMeta, cm); err != nil {
+					return err
+				}
+			}
+
+			selector, err := metav1.LabelSelectorAsSelector(job.Spec.Selector)
+			if err != nil {
+				continue
+			}
+			pods, err := server.K8sClient.CoreV1().Pods(ns).List(ctx, metav1.ListOptions{LabelSelector: selector.String()})
+			if err != nil {
+				g.recordError("listing pods of Job %s/%s: %v", ns, job.Name, err)
+				continue
+			}
+			for j := range pods.Items {
+				pod := &pods.Items[j]
+				track("Pod", &pod.ObjectMeta)
+				if err := g.addObject("Pod", corev1.SchemeGroupVersion, &pod.ObjectMeta, &pod.TypeMeta, pod); err != nil {
+					return err
+				}
+				statuses := append(append([]corev1.ContainerStatus{}, pod.Status.InitContainerStatuses...), pod.Status.ContainerStatuses...)
+				for _, status := range statuses {
+					if terminated := status.State.Terminated; terminated != nil && terminated.ExitCode == 0 {
+						continue
+					}
+					if status.State.Waiting != nil && status.RestartCount == 0 {
+						continue
+					}
+					if err := g.addLog(ctx, server, pod, status.Name, status.State.Waiting != nil, tail); err != nil {
+						return err
+					}
+				}
+			}
+		}
+	}
+	return nil
+}
+
+// addLog writes one container's log. Failures to read the log are recorded
+// as collection errors; only failures to write the tarball are returned.
+func (g *gatherer) addLog(ctx context.Context, server *types.MCPServer, pod *corev1.Pod, container string, previous bool, tail int64) error {
+	options := &corev1.PodLogOptions{Container: container, Previous: previous, TailLines: &tail}
+	lines, _, err := readLogLines(ctx, server.K8sClient, pod.Namespace, pod.Name, options, logFilter{}, int(tail))
+	if err != nil {
+		g.recordError("reading logs of %s/%s container %s: %v", pod.Namespace, pod.Name, container, err)
+		return nil
+	}
+	name := container
+	if previous {
+		name += ".previous"
+	}
+	g.report.Counts["Log"]++
+	return g.addFile(fmt.Sprintf("logs/%s/%s/%s.log", pod.Namespace, pod.Name, name), []byte(strings.Join(lines, "\n")+"\n"))
+}
+
+func (g *gatherer) addSummary(options GatherOptions) error {
+	var summary strings.Builder
+	summary.WriteString(fmt.Sprintf("Collected: %s\n", g.now.Format(time.RFC3339)))
+	scope := "all namespaces"
+	if options.Namespace != "" {
+		scope = fmt.Sprintf("namespace '%s'", options.Namespace)
+	}
+	summary.WriteString(fmt.Sprintf("Scope: %s\n", scope))
+	summary.WriteString(fmt.Sprintf("Log tail: %d lines per container\n\n", options.LogTailLines))
+	summary.WriteString(formatGatherCounts(g.report.Counts))
+	summary.WriteString("\n")
+	if len(g.report.Errors) == 0 {
+		summary.WriteString("No collection errors.\n")
+	} else {
+		summary.WriteString("Collection errors:\n")
+		for _, e := range g.report.Errors {
+			summary.WriteString(fmt.Sprintf("  - %s\n", e))
+		}
+	}
+	return g.addFile("summary.txt", []byte(summary.String()))
+}
+
+func formatGatherCounts(counts map[string]int) string {
+	kinds := make([]string, 0, len(counts))
+	for kind := range counts {
+		kinds = append(kinds, kind)
+	}
+	sort.Strings(kinds)
+	var result strings.Builder
+	result.WriteString("KIND\tCOUNT\n")
+	for _, kind := range kinds {
+		result.WriteString(fmt.Sprintf("%s\t%d\n", kind, counts[kind]))
+	}
+	return result.String()
+}
+
+// CollectOLMState writes a must-gather style tarball of OLM state and
+// reports where it was written. Callers cannot choose where the tarball goes:
+// it is always written to the server's temp directory, and only the gather
+// subcommand takes an output directory.
+func (t *DiagnosticTools) CollectOLMState(ctx context.Context, params map[string]string) (*types.MCPToolResult, error) {
+	options := GatherOptions{
+		Namespace: params["namespace"],
+		OutputDir: os.TempDir(),
+	}
+	if value := params["log_tail"]; value != "" {
+		parsed, err := strconv.ParseInt(value, 10, 64)
+		if err != nil || parsed <= 0 {
+			return &types.MCPToolResult{
+				Content: []types.MCPContent{{
+					Type: "text",
+					Text: fmt.Sprintf("Error: 'log_tail' must be a positive number of lines, got %q", value),
+				}},
+				IsError: true,
+			}, nil
+		}
+		options.LogTailLines = parsed
+	}
+
+	report, err := GatherOLMState(ctx, t.server, options)
+	if err != nil {
+		return &types.MCPToolResult{
+			Content: []types.MCPContent{{
+				Type: "text",
+				Text: fmt.Sprintf("Error collecting OLM state: %v", err),
+			}},
+			IsError: true,
+		}, nil
+	}
+
+	var result strings.Builder
+	result.WriteString(fmt.Sprintf("OLM state written to %s (%d files)\n\n", report.Path, len(report.Files)))
+	result.WriteString(formatGatherCounts(report.Counts))
+	result.WriteString("\nLayout:\n")
+	result.WriteString(strings.ReplaceAll(gatherLayout, "<root>", report.Root))
+	if len(report.Errors) > 0 {
+		result.WriteString("\nCollection errors (also in summary.txt):\n")
+		for _, e := range report.Errors {
+			result.WriteString(fmt.Sprintf("  - %s\n", e))
+		}
+	}
+
+	return &types.MCPToolResult{
+		Content: []types.MCPContent{{
+			Type: "text",
+			Text: result.String(),
+		}},
+	}, nil
+}
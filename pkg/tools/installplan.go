@@ -6,7 +6,10 @@ import (
 	"fmt"
 	"strings"
 
+	"github.com/operator-framework/api/pkg/operators/v1alpha1"
 	"github.com/operator-framework/operator-lifecycle-manager/olmv0-mcp-server/pkg/types"
+	"k8s.io/apimachinery/pkg/runtime"
+	"sigs.k8s.io/yaml"
 )
 
 type InstallPlanTools struct {
@@ -118,18 +121,11 @@ func (t *InstallPlanTools) GetInstallPlan(ctx context.Context, params map[string
 	}
 
 	if len(installPlan.Status.Plan) > 0 {
-		result.WriteString("Planned Resources:\n")
-		for _, step := range installPlan.Status.Plan {
-			result.WriteString(fmt.Sprintf("  - %s: %s/%s (Status: %s)\n",
-				step.Resource.Kind,
-				step.Resource.Name,
-				step.Resource.Manifest,
-				step.Status,
-			))
-		}
-		result.WriteString("\n")
+		t.writeSteps(ctx, &result, installPlan, params)
 	}
 
+	writeEventsSection(ctx, &result, t.server, v1alpha1.InstallPlanKind, namespace, name)
+
 	result.WriteString("Full JSON representation:\n")
 	result.WriteString("```json\n")
 	result.WriteString(string(jsonData))
@@ -141,4 +137,202 @@ func (t *InstallPlanTools) GetInstallPlan(ctx context.Context, params map[string
 			Text: result.String(),
 		}},
 	}, nil
-}
\ No newline at end of file
+}
+
+// stepKindDescriptions describe what the objects an InstallPlan creates are
+// for.
+var stepKindDescriptions = map[string]string{
+	v1alpha1.ClusterServiceVersionKind: "operator definition",
+	"CustomResourceDefinition":         "API definition",
+	v1alpha1.SubscriptionKind:          "Subscription for a dependency",
+	"ServiceAccount":                   "operator service account",
+	"Role":                             "namespaced permissions",
+	"ClusterRole":                      "cluster permissions",
+	"RoleBinding":                      "binds namespaced permissions",
+	"ClusterRoleBinding":               "binds cluster permissions",
+	"Service":                          "service shipped in the bundle",
+	"ConfigMap":                        "configuration shipped in the bundle",
+	"Secret":                           "secret shipped in the bundle",
+	"APIService":                       "aggregated API registration",
+}
+
+// stepGVK renders a step's group, version and kind the way kubectl does,
+// e.g. "apps/v1 Deployment" or "v1 ServiceAccount".
+func stepGVK(resource v1alpha1.StepResource) string {
+	version := resource.Version
+	if resource.Group != "" {
+		version = resource.Group + "/" + version
+	}
+	return fmt.Sprintf("%s %s", version, resource.Kind)
+}
+
+// stepDescription says what a step's object is for and where its manifest
+// comes from.
+func stepDescription(step *v1alpha1.Step) string {
+	var parts []string
+	if description, ok := stepKindDescriptions[step.Resource.Kind]; ok {
+		parts = append(parts, description)
+	}
+	switch ref := parseBundleReference(step.Resource.Manifest); {
+	case step.Resource.Manifest == "":
+		parts = append(parts, "no manifest")
+	case ref != nil:
+		parts = append(parts, fmt.Sprintf("manifest in bundle ConfigMap %s/%s", ref.Namespace, ref.Name))
+	default:
+		parts = append(parts, "inline manifest")
+	}
+	if step.Optional {
+		parts = append(parts, "optional")
+	}
+	return strings.Join(parts, "; ")
+}
+
+// writeSteps renders an InstallPlan's steps, filtered by the 'kind' and
+// 'status' parameters, and with their decoded manifests when
+// 'show_manifests' is set.
+func (t *InstallPlanTools) writeSteps(ctx context.Context, result *strings.Builder, installPlan *v1alpha1.InstallPlan, params map[string]string) {
+	kind := params["kind"]
+	status := params["status"]
+	showManifests := boolParam(params, "show_manifests")
+
+	counts := map[v1alpha1.StepStatus]int{}
+	var steps []int
+	for i, step := range installPlan.Status.Plan {
+		counts[step.Status]++
+		if kind != "" && !strings.EqualFold(step.Resource.Kind, kind) {
+			continue
+		}
+		if status != "" && !strings.EqualFold(string(step.Status), status) {
+			continue
+		}
+		steps = append(steps, i)
+	}
+
+	var summary []string
+	for _, s := range []v1alpha1.StepStatus{
+		v1alpha1.StepStatusCreated,
+		v1alpha1.StepStatusPresent,
+		v1alpha1.StepStatusUnknown,
+		v1alpha1.StepStatusNotPresent,
+		v1alpha1.StepStatusNotCreated,
+		v1alpha1.StepStatusWaitingForAPI,
+		v1alpha1.StepStatusUnsupportedResource,
+	} {
+		if counts[s] > 0 {
+			summary = append(summary, fmt.Sprintf("%d %s", counts[s], s))
+		}
+	}
+
+	result.WriteString(fmt.Sprintf("Steps (%d of %d shown; %s):\n", len(steps), len(installPlan.Status.Plan), strings.Join(summary, ", ")))
+	if len(steps) == 0 {
+		result.WriteString("  No steps match the given kind and status.\n\n")
+		return
+	}
+	result.WriteString("#\tGROUP/VERSION KIND\tNAME\tSTATUS\tRESOLVING\tDESCRIPTION\n")
+	for _, i := range steps {
+		step := installPlan.Status.Plan[i]
+		result.WriteString(fmt.Sprintf("%d\t%s\t%s\t%s\t%s\t%s\n",
+			i+1,
+			stepGVK(step.Resource),
+			step.Resource.Name,
+			step.Status,
+			step.Resolving,
+			stepDescription(step),
+		))
+	}
+	result.WriteString("\n")
+
+	if !showManifests {
+		return
+	}
+	resolver := newManifestResolver(t.server)
+	for _, i := range steps {
+		step := installPlan.Status.Plan[i]
+		result.WriteString(fmt.Sprintf("Step %d manifest (%s %s):\n", i+1, step.Resource.Kind, step.Resource.Name))
+		obj, err := resolver.Resolve(ctx, step)
+		if err != nil {
+			result.WriteString(fmt.Sprintf("  Error: %v\n\n", err))
+			continue
+		}
+		data, err := yaml.Marshal(obj.Object)
+		if err != nil {
+			result.WriteString(fmt.Sprintf("  Error encoding manifest: %v\n\n", err))
+			continue
+		}
+		result.WriteString("```yaml\n")
+		result.Write(data)
+		result.WriteString("```\n\n")
+	}
+}
+
+func (t *InstallPlanTools) ApproveInstallPlan(ctx context.Context, params map[string]string) (*types.MCPToolResult, error) {
+	namespace := params["namespace"]
+	name := params["name"]
+	dryRun := boolParam(params, "dry_run")
+
+	if namespace == "" {
+		namespace = "default"
+	}
+	if name == "" {
+		return &types.MCPToolResult{
+			Content: []types.MCPContent{{
+				Type: "text",
+				Text: "Error: 'name' parameter is required",
+			}},
+			IsError: true,
+		}, nil
+	}
+	if denied := checkWriteAllowed(t.server, dryRun); denied != nil {
+		return denied, nil
+	}
+
+	installPlan, err := t.server.OLMClient.GetInstallPlan(ctx, namespace, name)
+	if err != nil {
+		return &types.MCPToolResult{
+			Content: []types.MCPContent{{
+				Type: "text",
+				Text: fmt.Sprintf("Error getting InstallPlan '%s': %v", name, err),
+			}},
+			IsError: true,
+		}, nil
+	}
+
+	if installPlan.Spec.Approved {
+		return &types.MCPToolResult{
+			Content: []types.MCPContent{{
+				Type: "text",
+				Text: fmt.Sprintf("InstallPlan %s/%s is already approved (Phase: %s).\n", namespace, name, installPlan.Status.Phase),
+			}},
+		}, nil
+	}
+
+	approved := installPlan.DeepCopy()
+	approved.Spec.Approved = true
+
+	report, err := executeWrite(ctx, writeOperation{
+		Action:    "approval",
+		Kind:      v1alpha1.InstallPlanKind,
+		Namespace: namespace,
+		Name:      name,
+		Live:      installPlan,
+		Apply: func(ctx context.Context, dryRun bool) (runtime.Object, error) {
+			return t.server.OLMClient.UpdateInstallPlan(ctx, approved, dryRun)
+		},
+	}, dryRun)
+	if err != nil {
+		return &types.MCPToolResult{
+			Content: []types.MCPContent{{
+				Type: "text",
+				Text: fmt.Sprintf("%sError approving InstallPlan '%s': %v", report, name, err),
+			}},
+			IsError: true,
+		}, nil
+	}
+
+	return &types.MCPToolResult{
+		Content: []types.MCPContent{{
+			Type: "text",
+			Text: report,
+		}},
+	}, nil
+}
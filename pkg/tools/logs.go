@@ -0,0 +1,260 @@
+package tools
+
+import (
+	"bufio"
+	"context"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/operator-framework/operator-lifecycle-manager/olmv0-mcp-server/pkg/types"
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes"
+)
+
+const (
+	defaultLogTailLines = 1000
+	defaultLogMaxLines  = 200
+)
+
+// olmComponents are the OLM deployments whose logs explain reconciliation
+// of OLM resources.
+var olmComponents = []string{"olm-operator", "catalog-operator"}
+
+// findOLMDeployments locates the OLM component deployments. OLM runs in "olm"
+// upstream and in "openshift-operator-lifecycle-manager" on OpenShift, so
+// unless a namespace is given every namespace is searched.
+func findOLMDeployments(ctx context.Context, k8sClient kubernetes.Interface, namespace string, components []string) ([]appsv1.Deployment, error) {
+	deployments, err := k8sClient.AppsV1().Deployments(namespace).List(ctx, metav1.ListOptions{
+		LabelSelector: fmt.Sprintf("app in (%s)", strings.Join(components, ",")),
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	var found []appsv1.Deployment
+	for _, deployment := range deployments.Items {
+		for _, component := range components {
+			if deployment.Name == component {
+				found = append(found, deployment)
+			}
+		}
+	}
+	return found, nil
+}
+
+// GetOLMLogs returns olm-operator and catalog-operator log lines that mention
+// the given resources.
+func (t *DiagnosticTools) GetOLMLogs(ctx context.Context, params map[string]string) (*types.MCPToolResult, error) {
+	component := params["component"]
+	olmNamespace := params["olm_namespace"]
+
+	components := olmComponents
+	if component != "" && component != "all" {
+		if component != "olm-operator" && component != "catalog-operator" {
+			return &types.MCPToolResult{
+				Content: []types.MCPContent{{
+					Type: "text",
+					Text: "Error: 'component' must be one of olm-operator, catalog-operator or all",
+				}},
+				IsError: true,
+			}, nil
+		}
+		components = []string{component}
+	}
+
+	logOptions := corev1.PodLogOptions{}
+	tail := int64(defaultLogTailLines)
+	if value := params["tail"]; value != "" {
+		parsed, err := strconv.ParseInt(value, 10, 64)
+		if err != nil || parsed <= 0 {
+			return &types.MCPToolResult{
+				Content: []types.MCPContent{{
+					Type: "text",
+					Text: fmt.Sprintf("Error: 'tail' must be a positive number of lines, got %q", value),
+				}},
+				IsError: true,
+			}, nil
+		}
+		tail = parsed
+	}
+	logOptions.TailLines = &tail
+	if value := params["since"]; value != "" {
+		since, err := time.ParseDuration(value)
+		if err != nil || since <= 0 {
+			return &types.MCPToolResult{
+				Content: []types.MCPContent{{
+					Type: "text",
+					Text: fmt.Sprintf("Error: 'since' must be a positive duration such as 30m or 2h, got %q", value),
+				}},
+				IsError: true,
+			}, nil
+		}
+		seconds := int64(since.Seconds())
+		logOptions.SinceSeconds = &seconds
+	}
+	maxLines := defaultLogMaxLines
+	if value := params["max_lines"]; value != "" {
+		parsed, err := strconv.Atoi(value)
+		if err != nil || parsed <= 0 {
+			return &types.MCPToolResult{
+				Content: []types.MCPContent{{
+					Type: "text",
+					Text: fmt.Sprintf("Error: 'max_lines' must be a positive number, got %q", value),
+				}},
+				IsError: true,
+			}, nil
+		}
+		maxLines = parsed
+	}
+
+	filter := newLogFilter(params)
+
+	deployments, err := findOLMDeployments(ctx, t.server.K8sClient, olmNamespace, components)
+	if err != nil {
+		return &types.MCPToolResult{
+			Content: []types.MCPContent{{
+				Type: "text",
+				Text: fmt.Sprintf("Error finding OLM deployments: %v", err),
+			}},
+			IsError: true,
+		}, nil
+	}
+	if len(deployments) == 0 {
+		return &types.MCPToolResult{
+			Content: []types.MCPContent{{
+				Type: "text",
+				Text: fmt.Sprintf("Error: no %s deployment found; pass 'olm_namespace' if OLM runs in a non-standard location", strings.Join(components, " or ")),
+			}},
+			IsError: true,
+		}, nil
+	}
+
+	var result strings.Builder
+	result.WriteString("OLM component logs")
+	if filter.empty() {
+		result.WriteString(" (unfiltered)")
+	} else {
+		result.WriteString(fmt.Sprintf(" mentioning %s", filter))
+	}
+	result.WriteString(fmt.Sprintf(", last %d lines per container", tail))
+	if logOptions.SinceSeconds != nil {
+		result.WriteString(fmt.Sprintf(" within %s", params["since"]))
+	}
+	result.WriteString(":\n\n")
+
+	for i := range deployments {
+		deployment := &deployments[i]
+		pods, err := deploymentPods(ctx, t.server.K8sClient, deployment)
+		if err != nil {
+			result.WriteString(fmt.Sprintf("=== %s/%s ===\nError listing pods: %v\n\n", deployment.Namespace, deployment.Name, err))
+			continue
+		}
+		for _, pod := range pods {
+			for _, container := range pod.Spec.Containers {
+				result.WriteString(fmt.Sprintf("=== %s (pod %s/%s, container %s) ===\n", deployment.Name, pod.Namespace, pod.Name, container.Name))
+
+				options := logOptions
+				options.Container = container.Name
+				lines, scanned, err := readLogLines(ctx, t.server.K8sClient, pod.Namespace, pod.Name, &options, filter, maxLines)
+				if err != nil {
+					result.WriteString(fmt.Sprintf("Error reading logs: %v\n\n", err))
+					continue
+				}
+				for _, line := range lines {
+					result.WriteString(line)
+					result.WriteString("\n")
+				}
+				result.WriteString(fmt.Sprintf("(%d of %d scanned lines matched", len(lines), scanned))
+				if len(lines) == maxLines {
+					result.WriteString(fmt.Sprintf("; showing the most recent %d", maxLines))
+				}
+				result.WriteString(")\n\n")
+			}
+		}
+	}
+
+	return &types.MCPToolResult{
+		Content: []types.MCPContent{{
+			Type: "text",
+			Text: result.String(),
+		}},
+	}, nil
+}
+
+// logFilter selects log lines that mention a resource. A line matches when
+// it mentions any of the names and, if a namespace is set, also mentions the
+// namespace.
+type logFilter struct {
+	namespace string
+	names     []string
+	labels    []string
+}
+
+func newLogFilter(params map[string]string) logFilter {
+	filter := logFilter{namespace: params["namespace"]}
+	for _, key := range []string{"subscription", "csv", "install_plan"} {
+		if value := params[key]; value != "" {
+			filter.names = append(filter.names, value)
+			filter.labels = append(filter.labels, fmt.Sprintf("%s=%s", key, value))
+		}
+	}
+	return filter
+}
+
+func (f logFilter) empty() bool {
+	return f.namespace == "" && len(f.names) == 0
+}
+
+func (f logFilter) matches(line string) bool {
+	if f.namespace != "" && !strings.Contains(line, f.namespace) {
+		return false
+	}
+	if len(f.names) == 0 {
+		return true
+	}
+	for _, name := range f.names {
+		if strings.Contains(line, name) {
+			return true
+		}
+	}
+	return false
+}
+
+func (f logFilter) String() string {
+	terms := f.labels
+	if f.namespace != "" {
+		terms = append([]string{"namespace=" + f.namespace}, terms...)
+	}
+	return strings.Join(terms, ", ")
+}
+
+// readLogLines streams a container's logs and keeps the most recent maxLines
+// lines that match the filter. It also returns how many lines were scanned.
+func readLogLines(ctx context.Context, k8sClient kubernetes.Interface, namespace, pod string, options *corev1.PodLogOptions, filter logFilter, maxLines int) ([]string, int, error) {
+	stream, err := k8sClient.CoreV1().Pods(namespace).GetLogs(pod, options).Stream(ctx)
+	if err != nil {
+		return nil, 0, err
+	}
+	defer stream.Close()
+
+	var lines []string
+	scanned := 0
+	scanner := bufio.NewScanner(stream)
+	scanner.Buffer(make([]byte, 64*1024), 1024*1024)
+	for scanner.Scan() {
+		scanned++
+		line := scanner.Text()
+		if !filter.matches(line) {
+			continue
+		}
+		lines = append(lines, line)
+		if len(lines) > maxLines {
+			lines = lines[1:]
+		}
+	}
+	return lines, scanned, scanner.Err()
+}
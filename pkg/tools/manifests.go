@@ -0,0 +1,162 @@
+package tools
+
+import (
+	"bytes"
+	"compress/gzip"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+
+	"github.com/operator-framework/api/pkg/operators/v1alpha1"
+	"github.com/operator-framework/operator-lifecycle-manager/olmv0-mcp-server/pkg/types"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+	"sigs.k8s.io/yaml"
+)
+
+// unpackedBundleReference is stored in an InstallPlan step's manifest when
+// the real manifest lives in the ConfigMap that OLM unpacked the bundle into.
+type unpackedBundleReference struct {
+	Kind                   string `json:"kind"`
+	Name                   string `json:"name"`
+	Namespace              string `json:"namespace"`
+	CatalogSourceName      string `json:"catalogSourceName"`
+	CatalogSourceNamespace string `json:"catalogSourceNamespace"`
+	Replaces               string `json:"replaces"`
+	Properties             string `json:"properties,omitempty"`
+}
+
+// parseBundleReference returns the ConfigMap reference held in a step
+// manifest, or nil if the manifest is an inline object.
+func parseBundleReference(manifest string) *unpackedBundleReference {
+	var probe struct {
+		APIVersion string `json:"apiVersion"`
+		unpackedBundleReference
+	}
+	if err := json.Unmarshal([]byte(manifest), &probe); err != nil {
+		return nil
+	}
+	if probe.APIVersion != "" || probe.Kind != "ConfigMap" || probe.Name == "" {
+		return nil
+	}
+	return &probe.unpackedBundleReference
+}
+
+// manifestResolver decodes InstallPlan step manifests, following ConfigMap
+// references into unpacked bundles. Bundle ConfigMaps are cached since every
+// step of a bundle points to the same one.
+type manifestResolver struct {
+	server  *types.MCPServer
+	bundles map[string][]*unstructured.Unstructured
+}
+
+func newManifestResolver(server *types.MCPServer) *manifestResolver {
+	return &manifestResolver{
+		server:  server,
+		bundles: map[string][]*unstructured.Unstructured{},
+	}
+}
+
+// Resolve returns the object an InstallPlan step creates.
+func (r *manifestResolver) Resolve(ctx context.Context, step *v1alpha1.Step) (*unstructured.Unstructured, error) {
+	if step.Resource.Manifest == "" {
+		return nil, fmt.Errorf("step has no manifest")
+	}
+
+	ref := parseBundleReference(step.Resource.Manifest)
+	if ref == nil {
+		obj := &unstructured.Unstructured{}
+		if err := obj.UnmarshalJSON([]byte(step.Resource.Manifest)); err != nil {
+			return nil, fmt.Errorf("error decoding inline manifest: %v", err)
+		}
+		return obj, nil
+	}
+
+	objects, err := r.bundleObjects(ctx, ref.Namespace, ref.Name)
+	if err != nil {
+		return nil, err
+	}
+	for _, obj := range objects {
+		if obj.GetKind() == step.Resource.Kind && obj.GetName() == step.Resource.Name {
+			return obj, nil
+		}
+	}
+	return nil, fmt.Errorf("%s '%s' not found in bundle ConfigMap %s/%s", step.Resource.Kind, step.Resource.Name, ref.Namespace, ref.Name)
+}
+
+// bundleObjects decodes every manifest stored in a bundle ConfigMap.
+func (r *manifestResolver) bundleObjects(ctx context.Context, namespace, name string) ([]*unstructured.Unstructured, error) {
+	key := namespace + "/" + name
+	if objects, ok := r.bundles[key]; ok {
+		return objects, nil
+	}
+
+	cm, err := r.server.K8sClient.CoreV1().ConfigMaps(namespace).Get(ctx, name, metav1.GetOptions{})
+	if err != nil {
+		return nil, fmt.Errorf("error getting bundle ConfigMap %s: %v", key, err)
+	}
+
+	objects, err := decodeBundleConfigMap(cm)
+	if err != nil {
+		return nil, fmt.Errorf("error decoding bundle ConfigMap %s: %v", key, err)
+	}
+	r.bundles[key] = objects
+	return objects, nil
+}
+
+// decodeBundleConfigMap decodes the manifests OLM stores in a bundle
+// ConfigMap. Newer OLM releases gzip them into binaryData.
+func decodeBundleConfigMap(cm *corev1.ConfigMap) ([]*unstructured.Unstructured, error) {
+	var raw [][]byte
+	for _, value := range cm.Data {
+		raw = append(raw, []byte(value))
+	}
+	for _, value := range cm.BinaryData {
+		if reader, err := gzip.NewReader(bytes.NewReader(value)); err == nil {
+			decompressed, err := io.ReadAll(reader)
+			if err != nil {
+				return nil, err
+			}
+			value = decompressed
+		}
+		raw = append(raw, value)
+	}
+
+	var objects []*unstructured.Unstructured
+	for _, data := range raw {
+		jsonData, err := yaml.YAMLToJSON(data)
+		if err != nil {
+			return nil, err
+		}
+		obj := &unstructured.Unstructured{}
+		if err := obj.UnmarshalJSON(jsonData); err != nil {
+			// Not every key holds a Kubernetes object.
+			continue
+		}
+		objects = append(objects, obj)
+	}
+	return objects, nil
+}
+
+// resolvePlanCSV returns the CSV created by an InstallPlan step, if the plan
+// has one for the named CSV.
+func (r *manifestResolver) resolvePlanCSV(ctx context.Context, installPlan *v1alpha1.InstallPlan, csvName string) (*v1alpha1.ClusterServiceVersion, error) {
+	for _, step := range installPlan.Status.Plan {
+		if step == nil || step.Resource.Kind != v1alpha1.ClusterServiceVersionKind || step.Resource.Name != csvName {
+			continue
+		}
+		obj, err := r.Resolve(ctx, step)
+		if err != nil {
+			return nil, err
+		}
+		csv := &v1alpha1.ClusterServiceVersion{}
+		if err := runtime.DefaultUnstructuredConverter.FromUnstructured(obj.Object, csv); err != nil {
+			return nil, fmt.Errorf("error converting CSV manifest: %v", err)
+		}
+		return csv, nil
+	}
+	return nil, fmt.Errorf("InstallPlan '%s' has no step for CSV '%s'", installPlan.Name, csvName)
+}
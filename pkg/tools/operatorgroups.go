@@ -0,0 +1,344 @@
+package tools
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+
+	operatorsv1 "github.com/operator-framework/api/pkg/operators/v1"
+	"github.com/operator-framework/api/pkg/operators/v1alpha1"
+	"github.com/operator-framework/operator-lifecycle-manager/olmv0-mcp-server/pkg/types"
+)
+
+// operatorGroupReasons are the CSV status reasons OLM sets for OperatorGroup
+// problems.
+var operatorGroupReasons = map[v1alpha1.ConditionReason]bool{
+	v1alpha1.CSVReasonNoOperatorGroup:                 true,
+	v1alpha1.CSVReasonTooManyOperatorGroups:           true,
+	v1alpha1.CSVReasonUnsupportedOperatorGroup:        true,
+	v1alpha1.CSVReasonInterOperatorGroupOwnerConflict: true,
+}
+
+// operatorGroupTargets returns the namespaces an OperatorGroup targets, with
+// "" meaning all namespaces. It prefers the namespaces OLM resolved in the
+// status and falls back to the spec; ok is false when the group uses a
+// selector that has not been resolved yet.
+func operatorGroupTargets(og *operatorsv1.OperatorGroup) ([]string, bool) {
+	if len(og.Status.Namespaces) > 0 {
+		return og.Status.Namespaces, true
+	}
+	if len(og.Spec.TargetNamespaces) > 0 {
+		return og.Spec.TargetNamespaces, true
+	}
+	if og.Spec.Selector == nil {
+		return []string{""}, true
+	}
+	return nil, false
+}
+
+// requiredInstallMode returns the install mode a CSV needs to support to be
+// installed into an OperatorGroup with the given targets.
+func requiredInstallMode(namespace string, targets []string) v1alpha1.InstallModeType {
+	switch {
+	case len(targets) == 1 && targets[0] == "":
+		return v1alpha1.InstallModeTypeAllNamespaces
+	case len(targets) == 1 && targets[0] == namespace:
+		return v1alpha1.InstallModeTypeOwnNamespace
+	case len(targets) == 1:
+		return v1alpha1.InstallModeTypeSingleNamespace
+	default:
+		return v1alpha1.InstallModeTypeMultiNamespace
+	}
+}
+
+func supportsInstallMode(csv *v1alpha1.ClusterServiceVersion, mode v1alpha1.InstallModeType) bool {
+	for _, m := range csv.Spec.InstallModes {
+		if m.Type == mode {
+			return m.Supported
+		}
+	}
+	return false
+}
+
+// targetsOverlap reports whether two target sets share a namespace.
+func targetsOverlap(a, b []string) bool {
+	for _, x := range a {
+		for _, y := range b {
+			if x == "" || y == "" || x == y {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+func formatTargets(targets []string, ok bool) string {
+	switch {
+	case !ok:
+		return "<selector not resolved>"
+	case len(targets) == 1 && targets[0] == "":
+		return "<all namespaces>"
+	default:
+		return strings.Join(targets, ",")
+	}
+}
+
+// providedAPIs returns the APIs an OperatorGroup provides, from the
+// olm.providedAPIs annotation ("Kind.version.group" entries) and from the
+// owned CRDs and APIServices of the CSVs in its namespace.
+func providedAPIs(og *operatorsv1.OperatorGroup, csvs []*v1alpha1.ClusterServiceVersion) map[string]bool {
+	apis := map[string]bool{}
+	for _, api := range strings.Split(og.GetAnnotations()[operatorsv1.OperatorGroupProvidedAPIsAnnotationKey], ",") {
+		if api = strings.TrimSpace(api); api != "" {
+			apis[api] = true
+		}
+	}
+	for _, csv := range csvs {
+		for _, crd := range csv.Spec.CustomResourceDefinitions.Owned {
+			key := crdAPIKey(crd)
+			apis[fmt.Sprintf("%s.%s.%s", key.Kind, key.Version, key.Group)] = true
+		}
+		for _, api := range csv.Spec.APIServiceDefinitions.Owned {
+			apis[fmt.Sprintf("%s.%s.%s", api.Kind, api.Version, api.Group)] = true
+		}
+	}
+	return apis
+}
+
+// csvsWithReason lists the CSVs whose status reports the given reason, with
+// their status message.
+func csvsWithReason(csvs []*v1alpha1.ClusterServiceVersion, reason v1alpha1.ConditionReason) []string {
+	var evidence []string
+	for _, csv := range csvs {
+		if csv.Status.Reason == reason {
+			evidence = append(evidence, fmt.Sprintf("CSV %s/%s reports %s: %s", csv.Namespace, csv.Name, reason, csv.Status.Message))
+		}
+	}
+	return evidence
+}
+
+// ValidateOperatorGroups checks OperatorGroups across the cluster for the
+// configurations OLM refuses to install into.
+func (t *DiagnosticTools) ValidateOperatorGroups(ctx context.Context, params map[string]string) (*types.MCPToolResult, error) {
+	operatorGroups, err := t.server.OLMClient.ListOperatorGroups(ctx, "")
+	if err != nil {
+		return &types.MCPToolResult{
+			Content: []types.MCPContent{{
+				Type: "text",
+				Text: fmt.Sprintf("Error listing OperatorGroups: %v", err),
+			}},
+			IsError: true,
+		}, nil
+	}
+	csvList, err := t.server.OLMClient.ListClusterServiceVersions(ctx, "")
+	if err != nil {
+		return &types.MCPToolResult{
+			Content: []types.MCPContent{{
+				Type: "text",
+				Text: fmt.Sprintf("Error listing ClusterServiceVersions: %v", err),
+			}},
+			IsError: true,
+		}, nil
+	}
+
+	groupsByNamespace := map[string][]*operatorsv1.OperatorGroup{}
+	for i := range operatorGroups.Items {
+		og := &operatorGroups.Items[i]
+		groupsByNamespace[og.Namespace] = append(groupsByNamespace[og.Namespace], og)
+	}
+	csvsByNamespace := map[string][]*v1alpha1.ClusterServiceVersion{}
+	for i := range csvList.Items {
+		csv := &csvList.Items[i]
+		if !csv.IsCopied() {
+			csvsByNamespace[csv.Namespace] = append(csvsByNamespace[csv.Namespace], csv)
+		}
+	}
+
+	var findings []finding
+	explained := map[string]bool{}
+	explain := func(csvs []*v1alpha1.ClusterServiceVersion, reason v1alpha1.ConditionReason) []string {
+		evidence := csvsWithReason(csvs, reason)
+		for _, csv := range csvs {
+			if csv.Status.Reason == reason {
+				explained[csv.Namespace+"/"+csv.Name] = true
+			}
+		}
+		return evidence
+	}
+
+	namespaces := map[string]bool{}
+	for ns := range groupsByNamespace {
+		namespaces[ns] = true
+	}
+	for ns := range csvsByNamespace {
+		namespaces[ns] = true
+	}
+	sortedNamespaces := make([]string, 0, len(namespaces))
+	for ns := range namespaces {
+		sortedNamespaces = append(sortedNamespaces, ns)
+	}
+	sort.Strings(sortedNamespaces)
+
+	for _, ns := range sortedNamespaces {
+		groups := groupsByNamespace[ns]
+		csvs := csvsByNamespace[ns]
+
+		switch {
+		case len(groups) > 1:
+			var names []string
+			for _, og := range groups {
+				names = append(names, og.Name)
+			}
+			evidence := []string{fmt.Sprintf("OperatorGroups: %s", strings.Join(names, ", "))}
+			evidence = append(evidence, explain(csvs, v1alpha1.CSVReasonTooManyOperatorGroups)...)
+			evidence = append(evidence, "OLM installs nothing in a namespace with more than one OperatorGroup; delete all but one")
+			findings = append(findings, finding{
+				Severity: severityCritical,
+				Title:    fmt.Sprintf("Namespace %s has %d OperatorGroups", ns, len(groups)),
+				Evidence: evidence,
+			})
+			continue
+		case len(groups) == 0 && len(csvs) > 0:
+			evidence := explain(csvs, v1alpha1.CSVReasonNoOperatorGroup)
+			evidence = append(evidence, "create an OperatorGroup whose targets match the operator's install modes")
+			findings = append(findings, finding{
+				Severity: severityCritical,
+				Title:    fmt.Sprintf("Namespace %s has CSVs but no OperatorGroup", ns),
+				Evidence: evidence,
+			})
+			continue
+		case len(groups) == 0:
+			continue
+		}
+
+		og := groups[0]
+		targets, ok := operatorGroupTargets(og)
+		if !ok {
+			continue
+		}
+		mode := requiredInstallMode(ns, targets)
+		for _, csv := range csvs {
+			if supportsInstallMode(csv, mode) {
+				continue
+			}
+			var supported []string
+			for _, m := range csv.Spec.InstallModes {
+				if m.Supported {
+					supported = append(supported, string(m.Type))
+				}
+			}
+			evidence := []string{
+				fmt.Sprintf("OperatorGroup %s targets %s, which needs install mode %s", og.Name, formatTargets(targets, ok), mode),
+				fmt.Sprintf("CSV supports: %s", valueOrNone(strings.Join(supported, ", "))),
+			}
+			if csv.Status.Reason == v1alpha1.CSVReasonUnsupportedOperatorGroup {
+				explained[csv.Namespace+"/"+csv.Name] = true
+				evidence = append(evidence, fmt.Sprintf("CSV reports %s: %s", csv.Status.Reason, csv.Status.Message))
+			}
+			findings = append(findings, finding{
+				Severity: severityCritical,
+				Title:    fmt.Sprintf("CSV %s/%s does not support OperatorGroup %s's targets", ns, csv.Name, og.Name),
+				Evidence: evidence,
+			})
+		}
+	}
+
+	// Groups whose targets overlap must not provide the same API, since
+	// OLM would have two owners for it in the shared namespaces.
+	type groupAPIs struct {
+		og      *operatorsv1.OperatorGroup
+		targets []string
+		apis    map[string]bool
+	}
+	var resolved []groupAPIs
+	for i := range operatorGroups.Items {
+		og := &operatorGroups.Items[i]
+		if targets, ok := operatorGroupTargets(og); ok {
+			resolved = append(resolved, groupAPIs{og: og, targets: targets, apis: providedAPIs(og, csvsByNamespace[og.Namespace])})
+		}
+	}
+	for i := 0; i < len(resolved); i++ {
+		for j := i + 1; j < len(resolved); j++ {
+			a, b := resolved[i], resolved[j]
+			if a.og.Namespace == b.og.Namespace || !targetsOverlap(a.targets, b.targets) {
+				continue
+			}
+			shared := setDifference(a.apis, setToMap(setDifference(a.apis, b.apis)))
+			if len(shared) == 0 {
+				continue
+			}
+			evidence := []string{
+				fmt.Sprintf("%s/%s targets %s", a.og.Namespace, a.og.Name, formatTargets(a.targets, true)),
+				fmt.Sprintf("%s/%s targets %s", b.og.Namespace, b.og.Name, formatTargets(b.targets, true)),
+				fmt.Sprintf("both provide %s", strings.Join(shared, ", ")),
+			}
+			evidence = append(evidence, explain(csvsByNamespace[a.og.Namespace], v1alpha1.CSVReasonInterOperatorGroupOwnerConflict)...)
+			evidence = append(evidence, explain(csvsByNamespace[b.og.Namespace], v1alpha1.CSVReasonInterOperatorGroupOwnerConflict)...)
+			findings = append(findings, finding{
+				Severity: severityCritical,
+				Title:    fmt.Sprintf("OperatorGroups %s/%s and %s/%s overlap and provide the same APIs", a.og.Namespace, a.og.Name, b.og.Namespace, b.og.Name),
+				Evidence: evidence,
+			})
+		}
+	}
+
+	// Anything OLM flagged that the checks above did not explain.
+	for _, ns := range sortedNamespaces {
+		for _, csv := range csvsByNamespace[ns] {
+			if operatorGroupReasons[csv.Status.Reason] && !explained[csv.Namespace+"/"+csv.Name] {
+				findings = append(findings, finding{
+					Severity: severityWarning,
+					Title:    fmt.Sprintf("CSV %s/%s reports %s", csv.Namespace, csv.Name, csv.Status.Reason),
+					Evidence: []string{csv.Status.Message, "the OperatorGroups may have changed since OLM last reconciled the CSV"},
+				})
+			}
+		}
+	}
+
+	var result strings.Builder
+	result.WriteString("OperatorGroups in all namespaces:\n\n")
+	if len(operatorGroups.Items) == 0 {
+		result.WriteString("No OperatorGroups found.\n")
+	} else {
+		result.WriteString("NAMESPACE\tNAME\tTARGET NAMESPACES\tOPERATORS\n")
+		groups := operatorGroups.Items
+		sort.Slice(groups, func(i, j int) bool {
+			if groups[i].Namespace != groups[j].Namespace {
+				return groups[i].Namespace < groups[j].Namespace
+			}
+			return groups[i].Name < groups[j].Name
+		})
+		for i := range groups {
+			og := &groups[i]
+			targets, ok := operatorGroupTargets(og)
+			var names []string
+			for _, csv := range csvsByNamespace[og.Namespace] {
+				names = append(names, csv.Name)
+			}
+			result.WriteString(fmt.Sprintf("%s\t%s\t%s\t%s\n",
+				og.Namespace,
+				og.Name,
+				formatTargets(targets, ok),
+				valueOrNone(strings.Join(names, ",")),
+			))
+		}
+	}
+	result.WriteString("\n")
+	writeFindings(&result, findings)
+
+	return &types.MCPToolResult{
+		Content: []types.MCPContent{{
+			Type: "text",
+			Text: result.String(),
+		}},
+	}, nil
+}
+
+func setToMap(values []string) map[string]bool {
+	set := map[string]bool{}
+	for _, value := range values {
+		set[value] = true
+	}
+	return set
+}
@@ -0,0 +1,362 @@
+package tools
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	operatorsv1 "github.com/operator-framework/api/pkg/operators/v1"
+	"github.com/operator-framework/api/pkg/operators/v1alpha1"
+	"github.com/operator-framework/operator-lifecycle-manager/olmv0-mcp-server/pkg/types"
+	"k8s.io/apimachinery/pkg/runtime"
+)
+
+const defaultOrphanMinAge = time.Hour
+
+// orphan is a stale OLM object that nothing depends on anymore.
+type orphan struct {
+	Kind      string
+	Namespace string
+	Name      string
+	Since     time.Time
+	Reason    string
+	Object    runtime.Object
+}
+
+// ID is the identifier cleanup_orphans accepts, e.g.
+// "installplan/operators/install-abcde".
+func (o orphan) ID() string {
+	return fmt.Sprintf("%s/%s/%s", strings.ToLower(o.Kind), o.Namespace, o.Name)
+}
+
+// findOrphans looks for InstallPlans no Subscription references anymore,
+// CSVs stuck in Replacing or Deleting, Subscriptions whose CatalogSource was
+// deleted and OperatorGroups in namespaces without operators. Only objects
+// older than minAge are reported, so in-flight work is left alone. Errors
+// from individual list calls are returned alongside the partial result.
+func findOrphans(ctx context.Context, server *types.MCPServer, namespace string, minAge time.Duration) ([]orphan, []string) {
+	inventory := listOLMInventory(ctx, server.OLMClient, namespace)
+	errors := inventory.errors
+
+	// Subscriptions may use catalogs from any namespace.
+	if namespace != "" {
+		catalogs, err := server.OLMClient.ListCatalogSources(ctx, "")
+		if err != nil {
+			errors = append(errors, fmt.Sprintf("listing CatalogSources in all namespaces: %v", err))
+		} else {
+			inventory.catalogs = catalogs
+		}
+	}
+	operatorGroups, err := server.OLMClient.ListOperatorGroups(ctx, namespace)
+	if err != nil {
+		errors = append(errors, fmt.Sprintf("listing OperatorGroups: %v", err))
+		operatorGroups = nil
+	}
+
+	now := time.Now()
+	oldEnough := func(since time.Time) bool {
+		return !since.IsZero() && now.Sub(since) >= minAge
+	}
+	var orphans []orphan
+
+	referencedPlans := map[string]bool{}
+	populated := map[string]bool{}
+	if inventory.subscriptions != nil {
+		for _, sub := range inventory.subscriptions.Items {
+			populated[sub.Namespace] = true
+			if ref := sub.Status.InstallPlanRef; ref != nil {
+				referencedPlans[ref.Namespace+"/"+ref.Name] = true
+			}
+		}
+	}
+
+	if inventory.installPlans != nil && inventory.subscriptions != nil {
+		for i := range inventory.installPlans.Items {
+			installPlan := &inventory.installPlans.Items[i]
+			phase := installPlan.Status.Phase
+			if phase != v1alpha1.InstallPlanPhaseComplete && phase != v1alpha1.InstallPlanPhaseFailed {
+				continue
+			}
+			if referencedPlans[installPlan.Namespace+"/"+installPlan.Name] || !oldEnough(installPlan.CreationTimestamp.Time) {
+				continue
+			}
+			orphans = append(orphans, orphan{
+				Kind:      v1alpha1.InstallPlanKind,
+				Namespace: installPlan.Namespace,
+				Name:      installPlan.Name,
+				Since:     installPlan.CreationTimestamp.Time,
+				Reason:    fmt.Sprintf("phase %s and no Subscription references it (installs %s)", phase, strings.Join(installPlan.Spec.ClusterServiceVersionNames, ", ")),
+				Object:    installPlan,
+			})
+		}
+	}
+
+	if inventory.csvs != nil {
+		replacedBy := map[string]string{}
+		for _, csv := range inventory.csvs.Items {
+			if csv.Spec.Replaces != "" {
+				replacedBy[csv.Namespace+"/"+csv.Spec.Replaces] = csv.Name
+			}
+		}
+		for i := range inventory.csvs.Items {
+			csv := &inventory.csvs.Items[i]
+			if csv.IsCopied() {
+				continue
+			}
+			populated[csv.Namespace] = true
+
+			phase := csv.Status.Phase
+			if phase != v1alpha1.CSVPhaseReplacing && phase != v1alpha1.CSVPhaseDeleting {
+				continue
+			}
+			since := csv.CreationTimestamp.Time
+			if csv.Status.LastTransitionTime != nil {
+				since = csv.Status.LastTransitionTime.Time
+			}
+			if !oldEnough(since) {
+				continue
+			}
+			reason := fmt.Sprintf("stuck in %s", phase)
+			if successor := replacedBy[csv.Namespace+"/"+csv.Name]; successor != "" {
+				reason += fmt.Sprintf(" (replaced by %s)", successor)
+			}
+			if csv.DeletionTimestamp != nil && len(csv.Finalizers) > 0 {
+				reason += fmt.Sprintf("; deletion blocked by finalizers %s", strings.Join(csv.Finalizers, ", "))
+			}
+			orphans = append(orphans, orphan{
+				Kind:      v1alpha1.ClusterServiceVersionKind,
+				Namespace: csv.Namespace,
+				Name:      csv.Name,
+				Since:     since,
+				Reason:    reason,
+				Object:    csv,
+			})
+		}
+	}
+
+	if inventory.subscriptions != nil && inventory.catalogs != nil {
+		catalogs := map[string]bool{}
+		for _, catalog := range inventory.catalogs.Items {
+			catalogs[catalog.Namespace+"/"+catalog.Name] = true
+		}
+		for i := range inventory.subscriptions.Items {
+			sub := &inventory.subscriptions.Items[i]
+			if catalogs[sub.Spec.CatalogSourceNamespace+"/"+sub.Spec.CatalogSource] || !oldEnough(sub.CreationTimestamp.Time) {
+				continue
+			}
+			orphans = append(orphans, orphan{
+				Kind:      v1alpha1.SubscriptionKind,
+				Namespace: sub.Namespace,
+				Name:      sub.Name,
+				Since:     sub.CreationTimestamp.Time,
+				Reason:    fmt.Sprintf("CatalogSource %s/%s no longer exists; deleting the Subscription leaves CSV %s installed", sub.Spec.CatalogSourceNamespace, sub.Spec.CatalogSource, valueOrNone(sub.Status.InstalledCSV)),
+				Object:    sub,
+			})
+		}
+	}
+
+	// Without the CSV and Subscription listings an empty namespace cannot
+	// be told apart from a failed call.
+	if operatorGroups != nil && inventory.csvs != nil && inventory.subscriptions != nil {
+		for i := range operatorGroups.Items {
+			og := &operatorGroups.Items[i]
+			if populated[og.Namespace] || !oldEnough(og.CreationTimestamp.Time) {
+				continue
+			}
+			orphans = append(orphans, orphan{
+				Kind:      operatorsv1.OperatorGroupKind,
+				Namespace: og.Namespace,
+				Name:      og.Name,
+				Since:     og.CreationTimestamp.Time,
+				Reason:    "no Subscriptions or CSVs in its namespace",
+				Object:    og,
+			})
+		}
+	}
+
+	sort.Slice(orphans, func(i, j int) bool {
+		return orphans[i].ID() < orphans[j].ID()
+	})
+	sort.Strings(errors)
+	return orphans, errors
+}
+
+// orphanMinAge parses the min_age parameter.
+func orphanMinAge(params map[string]string) (time.Duration, *types.MCPToolResult) {
+	value := params["min_age"]
+	if value == "" {
+		return defaultOrphanMinAge, nil
+	}
+	minAge, err := time.ParseDuration(value)
+	if err != nil || minAge < 0 {
+		return 0, &types.MCPToolResult{
+			Content: []types.MCPContent{{
+				Type: "text",
+				Text: fmt.Sprintf("Error: 'min_age' must be a duration such as 30m or 24h, got %q", value),
+			}},
+			IsError: true,
+		}
+	}
+	return minAge, nil
+}
+
+// FindOrphans lists stale OLM objects with their age and why they are
+// considered orphaned.
+func (t *DiagnosticTools) FindOrphans(ctx context.Context, params map[string]string) (*types.MCPToolResult, error) {
+	// Orphans accumulate across namespaces, so this defaults to all of them.
+	namespace := params["namespace"]
+	minAge, errResult := orphanMinAge(params)
+	if errResult != nil {
+		return errResult, nil
+	}
+
+	orphans, errors := findOrphans(ctx, t.server, namespace, minAge)
+	if len(orphans) == 0 && len(errors) >= 4 {
+		return &types.MCPToolResult{
+			Content: []types.MCPContent{{
+				Type: "text",
+				Text: fmt.Sprintf("Error listing OLM resources: %s", strings.Join(errors, "; ")),
+			}},
+			IsError: true,
+		}, nil
+	}
+
+	var result strings.Builder
+	scope := "all namespaces"
+	if namespace != "" {
+		scope = fmt.Sprintf("namespace '%s'", namespace)
+	}
+	result.WriteString(fmt.Sprintf("Orphaned OLM objects in %s (older than %s):\n\n", scope, minAge))
+
+	if len(orphans) == 0 {
+		result.WriteString("No orphaned objects found.\n")
+	} else {
+		result.WriteString("ID\tAGE\tREASON\n")
+		for _, o := range orphans {
+			result.WriteString(fmt.Sprintf("%s\t%s\t%s\n", o.ID(), time.Since(o.Since).Round(time.Minute), o.Reason))
+		}
+		result.WriteString("\nTo delete some of them, pass their IDs to cleanup_orphans as 'targets' (comma-separated), with 'dry_run' set to true first.\n")
+	}
+
+	if len(errors) > 0 {
+		result.WriteString("\nIncomplete results:\n")
+		for _, msg := range errors {
+			result.WriteString(fmt.Sprintf("  - %s\n", msg))
+		}
+	}
+
+	return &types.MCPToolResult{
+		Content: []types.MCPContent{{
+			Type: "text",
+			Text: result.String(),
+		}},
+	}, nil
+}
+
+// CleanupOrphans deletes the selected orphans. Targets are re-checked
+// against a fresh orphan scan so that objects which have become relevant
+// again since find_orphans ran are skipped.
+func (t *DiagnosticTools) CleanupOrphans(ctx context.Context, params map[string]string) (*types.MCPToolResult, error) {
+	dryRun := boolParam(params, "dry_run")
+
+	var targets []string
+	for _, target := range strings.Split(params["targets"], ",") {
+		if target = strings.ToLower(strings.TrimSpace(target)); target != "" {
+			targets = append(targets, target)
+		}
+	}
+	if len(targets) == 0 {
+		return &types.MCPToolResult{
+			Content: []types.MCPContent{{
+				Type: "text",
+				Text: "Error: 'targets' parameter is required; pass the comma-separated IDs reported by find_orphans",
+			}},
+			IsError: true,
+		}, nil
+	}
+	if errResult := checkWriteAllowed(t.server, dryRun); errResult != nil {
+		return errResult, nil
+	}
+	minAge, errResult := orphanMinAge(params)
+	if errResult != nil {
+		return errResult, nil
+	}
+
+	orphans, errors := findOrphans(ctx, t.server, "", minAge)
+	byID := map[string]orphan{}
+	for _, o := range orphans {
+		byID[o.ID()] = o
+	}
+
+	var result strings.Builder
+	if dryRun {
+		result.WriteString(fmt.Sprintf("Cleanup of %d orphaned objects (dry run):\n\n", len(targets)))
+	} else {
+		result.WriteString(fmt.Sprintf("Cleanup of %d orphaned objects:\n\n", len(targets)))
+	}
+
+	failed := false
+	for _, target := range targets {
+		o, ok := byID[target]
+		if !ok {
+			result.WriteString(fmt.Sprintf("Skipping %s: not currently an orphan (older than %s); re-run find_orphans\n\n", target, minAge))
+			continue
+		}
+
+		result.WriteString(fmt.Sprintf("%s: %s\n", o.ID(), o.Reason))
+		report, err := executeWrite(ctx, orphanDeletion(t.server, o), dryRun)
+		result.WriteString(report)
+		if err != nil {
+			failed = true
+			result.WriteString(fmt.Sprintf("Error: %v\n", err))
+		}
+		result.WriteString("\n")
+	}
+
+	if len(errors) > 0 {
+		result.WriteString("Orphan scan was incomplete, so some targets may have been skipped:\n")
+		for _, msg := range errors {
+			result.WriteString(fmt.Sprintf("  - %s\n", msg))
+		}
+	}
+
+	return &types.MCPToolResult{
+		Content: []types.MCPContent{{
+			Type: "text",
+			Text: result.String(),
+		}},
+		IsError: failed,
+	}, nil
+}
+
+func orphanDeletion(server *types.MCPServer, o orphan) writeOperation {
+	op := writeOperation{
+		Action:    "deletion",
+		Kind:      o.Kind,
+		Namespace: o.Namespace,
+		Name:      o.Name,
+		Live:      o.Object,
+	}
+	client := server.OLMClient
+	switch o.Kind {
+	case v1alpha1.InstallPlanKind:
+		op.Apply = func(ctx context.Context, dryRun bool) (runtime.Object, error) {
+			return nil, client.DeleteInstallPlan(ctx, o.Namespace, o.Name, dryRun)
+		}
+	case v1alpha1.ClusterServiceVersionKind:
+		op.Apply = func(ctx context.Context, dryRun bool) (runtime.Object, error) {
+			return nil, client.DeleteClusterServiceVersion(ctx, o.Namespace, o.Name, dryRun)
+		}
+	case v1alpha1.SubscriptionKind:
+		op.Apply = func(ctx context.Context, dryRun bool) (runtime.Object, error) {
+			return nil, client.DeleteSubscription(ctx, o.Namespace, o.Name, dryRun)
+		}
+	case operatorsv1.OperatorGroupKind:
+		op.Apply = func(ctx context.Context, dryRun bool) (runtime.Object, error) {
+			return nil, client.DeleteOperatorGroup(ctx, o.Namespace, o.Name, dryRun)
+		}
+	}
+	return op
+}
@@ -0,0 +1,56 @@
+package tools
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/operator-framework/api/pkg/operators/v1alpha1"
+	"github.com/operator-framework/operator-lifecycle-manager/olmv0-mcp-server/pkg/types"
+)
+
+// findPackageManifest returns the PackageManifest that the Subscription
+// resolves from. The package server exposes every catalog visible to a
+// namespace, so packages are matched on catalog as well as package name.
+func findPackageManifest(ctx context.Context, server *types.MCPServer, subscription *v1alpha1.Subscription) (*types.PackageManifest, error) {
+	packages, err := server.OLMClient.ListPackageManifests(ctx, subscription.Namespace)
+	if err != nil {
+		return nil, err
+	}
+	return matchPackageManifest(packages, subscription)
+}
+
+// matchPackageManifest picks the Subscription's package out of a listing.
+func matchPackageManifest(packages *types.PackageManifestList, subscription *v1alpha1.Subscription) (*types.PackageManifest, error) {
+	for i := range packages.Items {
+		pkg := &packages.Items[i]
+		if pkg.Status.PackageName == subscription.Spec.Package &&
+			pkg.Status.CatalogSource == subscription.Spec.CatalogSource &&
+			pkg.Status.CatalogSourceNamespace == subscription.Spec.CatalogSourceNamespace {
+			return pkg, nil
+		}
+	}
+
+	return nil, fmt.Errorf("package '%s' not found in CatalogSource %s/%s",
+		subscription.Spec.Package, subscription.Spec.CatalogSourceNamespace, subscription.Spec.CatalogSource)
+}
+
+// findChannelEntry returns the channel that serves the named CSV, preferring
+// the given channel when several do, along with the entry's index in it.
+func findChannelEntry(pkg *types.PackageManifest, preferredChannel, csvName string) (*types.PackageChannel, int) {
+	if channel := pkg.GetChannel(preferredChannel); channel != nil {
+		for i, entry := range channel.Entries {
+			if entry.Name == csvName {
+				return channel, i
+			}
+		}
+	}
+	for c := range pkg.Status.Channels {
+		channel := &pkg.Status.Channels[c]
+		for i, entry := range channel.Entries {
+			if entry.Name == csvName {
+				return channel, i
+			}
+		}
+	}
+	return nil, -1
+}
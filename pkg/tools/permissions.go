@@ -0,0 +1,330 @@
+package tools
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/operator-framework/api/pkg/operators/v1alpha1"
+	"github.com/operator-framework/operator-lifecycle-manager/olmv0-mcp-server/pkg/types"
+	rbacv1 "k8s.io/api/rbac/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// Labels OLM puts on the RBAC it generates for a CSV.
+const (
+	ownerLabel          = "olm.owner"
+	ownerNamespaceLabel = "olm.owner.namespace"
+)
+
+// escalationVerbs let a subject grant itself more than it has.
+var escalationVerbs = map[string]bool{"escalate": true, "bind": true, "impersonate": true}
+
+func containsString(values []string, value string) bool {
+	for _, v := range values {
+		if v == value {
+			return true
+		}
+	}
+	return false
+}
+
+// ruleRisks explains why a rule deserves a security reviewer's attention.
+func ruleRisks(rule rbacv1.PolicyRule, clusterScoped bool) (severity, []string) {
+	wildcardGroups := containsString(rule.APIGroups, "*")
+	wildcardResources := containsString(rule.Resources, "*")
+	wildcardVerbs := containsString(rule.Verbs, "*")
+
+	if clusterScoped && wildcardGroups && wildcardResources && wildcardVerbs {
+		return severityCritical, []string{"equivalent to cluster-admin"}
+	}
+
+	sev := severityInfo
+	var risks []string
+	raise := func(s severity, reason string) {
+		if s > sev {
+			sev = s
+		}
+		risks = append(risks, reason)
+	}
+
+	if wildcardGroups || wildcardResources || wildcardVerbs || containsString(rule.NonResourceURLs, "*") {
+		raise(severityWarning, "uses wildcards")
+	}
+	for _, verb := range rule.Verbs {
+		if escalationVerbs[verb] {
+			raise(severityWarning, fmt.Sprintf("grants '%s'", verb))
+		}
+	}
+	readsSecrets := containsString(rule.Resources, "secrets") || wildcardResources
+	if readsSecrets && clusterScoped && (wildcardVerbs || containsString(rule.Verbs, "get") || containsString(rule.Verbs, "list") || containsString(rule.Verbs, "watch")) {
+		raise(severityWarning, "reads Secrets in every namespace")
+	}
+	for _, resource := range []string{"clusterroles", "clusterrolebindings", "roles", "rolebindings"} {
+		if containsString(rule.Resources, resource) && (wildcardVerbs || containsString(rule.Verbs, "create") || containsString(rule.Verbs, "update") || containsString(rule.Verbs, "patch")) {
+			raise(severityWarning, fmt.Sprintf("can modify %s", resource))
+		}
+	}
+	return sev, risks
+}
+
+// generatedRBAC holds the RBAC OLM created for a CSV, keyed by service
+// account.
+type generatedRBAC struct {
+	// Rules granted in the CSV's namespace through Roles.
+	NamespaceRules map[string]map[string]bool
+	// Rules granted cluster-wide through ClusterRoles. OLM promotes
+	// namespaced permissions to ClusterRoles when the OperatorGroup targets
+	// all namespaces.
+	ClusterRules map[string]map[string]bool
+	Objects      []string
+}
+
+func ownedByCSV(meta metav1.ObjectMeta, csv *v1alpha1.ClusterServiceVersion) bool {
+	if meta.Labels[ownerLabel] == csv.Name && meta.Labels[ownerNamespaceLabel] == csv.Namespace {
+		return true
+	}
+	for _, ref := range meta.OwnerReferences {
+		if ref.Kind == v1alpha1.ClusterServiceVersionKind && ref.UID == csv.UID {
+			return true
+		}
+	}
+	return false
+}
+
+func addRules(set map[string]map[string]bool, serviceAccount string, rules []rbacv1.PolicyRule) {
+	if set[serviceAccount] == nil {
+		set[serviceAccount] = map[string]bool{}
+	}
+	for _, rule := range rules {
+		set[serviceAccount][policyRuleString(rule)] = true
+	}
+}
+
+// boundServiceAccounts returns the service accounts in namespace that a
+// binding's subjects name.
+func boundServiceAccounts(subjects []rbacv1.Subject, namespace string) []string {
+	var accounts []string
+	for _, subject := range subjects {
+		if subject.Kind == rbacv1.ServiceAccountKind && subject.Namespace == namespace {
+			accounts = append(accounts, subject.Name)
+		}
+	}
+	return accounts
+}
+
+// getGeneratedRBAC reads the Roles, RoleBindings, ClusterRoles and
+// ClusterRoleBindings OLM generated for the CSV.
+func getGeneratedRBAC(ctx context.Context, server *types.MCPServer, csv *v1alpha1.ClusterServiceVersion) (*generatedRBAC, error) {
+	rbac := server.K8sClient.RbacV1()
+	generated := &generatedRBAC{
+		NamespaceRules: map[string]map[string]bool{},
+		ClusterRules:   map[string]map[string]bool{},
+	}
+
+	roles, err := rbac.Roles(csv.Namespace).List(ctx, metav1.ListOptions{})
+	if err != nil {
+		return nil, fmt.Errorf("error listing Roles: %v", err)
+	}
+	roleRules := map[string][]rbacv1.PolicyRule{}
+	for _, role := range roles.Items {
+		if ownedByCSV(role.ObjectMeta, csv) {
+			roleRules[role.Name] = role.Rules
+		}
+	}
+
+	roleBindings, err := rbac.RoleBindings(csv.Namespace).List(ctx, metav1.ListOptions{})
+	if err != nil {
+		return nil, fmt.Errorf("error listing RoleBindings: %v", err)
+	}
+	for _, binding := range roleBindings.Items {
+		rules, ok := roleRules[binding.RoleRef.Name]
+		if binding.RoleRef.Kind != "Role" || !ok {
+			continue
+		}
+		accounts := boundServiceAccounts(binding.Subjects, csv.Namespace)
+		for _, account := range accounts {
+			addRules(generated.NamespaceRules, account, rules)
+		}
+		generated.Objects = append(generated.Objects, fmt.Sprintf("Role %s/%s bound to %s by RoleBinding %s", csv.Namespace, binding.RoleRef.Name, strings.Join(accounts, ","), binding.Name))
+	}
+
+	selector := metav1.ListOptions{LabelSelector: fmt.Sprintf("%s=%s,%s=%s", ownerLabel, csv.Name, ownerNamespaceLabel, csv.Namespace)}
+	clusterRoles, err := rbac.ClusterRoles().List(ctx, selector)
+	if err != nil {
+		return nil, fmt.Errorf("error listing ClusterRoles: %v", err)
+	}
+	clusterRoleRules := map[string][]rbacv1.PolicyRule{}
+	for _, role := range clusterRoles.Items {
+		clusterRoleRules[role.Name] = role.Rules
+	}
+
+	clusterRoleBindings, err := rbac.ClusterRoleBindings().List(ctx, selector)
+	if err != nil {
+		return nil, fmt.Errorf("error listing ClusterRoleBindings: %v", err)
+	}
+	for _, binding := range clusterRoleBindings.Items {
+		rules, ok := clusterRoleRules[binding.RoleRef.Name]
+		if !ok {
+			continue
+		}
+		accounts := boundServiceAccounts(binding.Subjects, csv.Namespace)
+		for _, account := range accounts {
+			addRules(generated.ClusterRules, account, rules)
+		}
+		generated.Objects = append(generated.Objects, fmt.Sprintf("ClusterRole %s bound to %s by ClusterRoleBinding %s", binding.RoleRef.Name, strings.Join(accounts, ","), binding.Name))
+	}
+
+	sort.Strings(generated.Objects)
+	return generated, nil
+}
+
+// GetOperatorPermissions summarizes what a CSV's service accounts may do and
+// compares it with the RBAC OLM generated.
+func (t *CSVTools) GetOperatorPermissions(ctx context.Context, params map[string]string) (*types.MCPToolResult, error) {
+	namespace := params["namespace"]
+	name := params["name"]
+
+	if namespace == "" {
+		namespace = "default"
+	}
+	if name == "" {
+		return &types.MCPToolResult{
+			Content: []types.MCPContent{{
+				Type: "text",
+				Text: "Error: 'name' parameter is required",
+			}},
+			IsError: true,
+		}, nil
+	}
+
+	csv, err := t.server.OLMClient.GetClusterServiceVersion(ctx, namespace, name)
+	if err != nil {
+		return &types.MCPToolResult{
+			Content: []types.MCPContent{{
+				Type: "text",
+				Text: fmt.Sprintf("Error getting ClusterServiceVersion '%s': %v", name, err),
+			}},
+			IsError: true,
+		}, nil
+	}
+
+	var result strings.Builder
+	result.WriteString(fmt.Sprintf("Permissions requested by ClusterServiceVersion %s/%s:\n", namespace, name))
+	if csv.IsCopied() {
+		result.WriteString(fmt.Sprintf("Note: this is a copied CSV; OLM generates RBAC for the original in namespace %s.\n", csv.GetAnnotations()["olm.operatorNamespace"]))
+	}
+
+	strategy := csv.Spec.InstallStrategy.StrategySpec
+	expectedNamespace := map[string]map[string]bool{}
+	expectedCluster := map[string]map[string]bool{}
+	var findings []finding
+
+	writeRules := func(scope string, perms []v1alpha1.StrategyDeploymentPermissions, clusterScoped bool, expected map[string]map[string]bool) {
+		for _, perm := range perms {
+			result.WriteString(fmt.Sprintf("\nService account %s, %s:\n", perm.ServiceAccountName, scope))
+			addRules(expected, perm.ServiceAccountName, perm.Rules)
+			for _, rule := range perm.Rules {
+				sev, risks := ruleRisks(rule, clusterScoped)
+				line := policyRuleString(rule)
+				if len(risks) > 0 {
+					result.WriteString(fmt.Sprintf("  ! %s  (%s)\n", line, strings.Join(risks, ", ")))
+					findings = append(findings, finding{
+						Severity: sev,
+						Title:    fmt.Sprintf("%s %s rule %s", perm.ServiceAccountName, scope, strings.Join(risks, ", ")),
+						Evidence: []string{line},
+					})
+				} else {
+					result.WriteString(fmt.Sprintf("  - %s\n", line))
+				}
+			}
+		}
+	}
+	writeRules(fmt.Sprintf("namespace %s", namespace), strategy.Permissions, false, expectedNamespace)
+	writeRules("cluster-wide", strategy.ClusterPermissions, true, expectedCluster)
+	if len(strategy.Permissions) == 0 && len(strategy.ClusterPermissions) == 0 {
+		result.WriteString("\nThe CSV requests no permissions.\n")
+	}
+
+	result.WriteString("\nRBAC generated by OLM:\n")
+	generated, err := getGeneratedRBAC(ctx, t.server, csv)
+	if err != nil {
+		result.WriteString(fmt.Sprintf("  Unavailable: %v\n", err))
+	} else {
+		if len(generated.Objects) == 0 {
+			result.WriteString("  None found.\n")
+		}
+		for _, object := range generated.Objects {
+			result.WriteString(fmt.Sprintf("  - %s\n", object))
+		}
+
+		accounts := map[string]bool{}
+		for _, set := range []map[string]map[string]bool{expectedNamespace, expectedCluster, generated.NamespaceRules, generated.ClusterRules} {
+			for account := range set {
+				accounts[account] = true
+			}
+		}
+		names := make([]string, 0, len(accounts))
+		for account := range accounts {
+			names = append(names, account)
+		}
+		sort.Strings(names)
+
+		result.WriteString("\nDrift between the CSV and generated RBAC:\n")
+		drifted := false
+		for _, account := range names {
+			granted := map[string]bool{}
+			for rule := range generated.NamespaceRules[account] {
+				granted[rule] = true
+			}
+			for rule := range generated.ClusterRules[account] {
+				granted[rule] = true
+			}
+			requested := map[string]bool{}
+			for rule := range expectedNamespace[account] {
+				requested[rule] = true
+			}
+			for rule := range expectedCluster[account] {
+				requested[rule] = true
+			}
+
+			// Namespaced permissions may be granted by a ClusterRole when
+			// OLM promotes them, so both scopes are compared together,
+			// but cluster permissions must come from a ClusterRole.
+			missing := setDifference(requested, granted)
+			missing = append(missing, setDifference(expectedCluster[account], generated.ClusterRules[account])...)
+			extra := setDifference(granted, requested)
+			missing = uniqueStrings(missing)
+
+			for _, rule := range missing {
+				drifted = true
+				result.WriteString(fmt.Sprintf("  - %s: requested by the CSV but not granted: %s\n", account, rule))
+			}
+			for _, rule := range extra {
+				drifted = true
+				result.WriteString(fmt.Sprintf("  + %s: granted but not in the CSV: %s\n", account, rule))
+			}
+			if len(missing) > 0 || len(extra) > 0 {
+				findings = append(findings, finding{
+					Severity: severityWarning,
+					Title:    fmt.Sprintf("RBAC for %s has drifted from the CSV (%d missing, %d extra rules)", account, len(missing), len(extra)),
+					Evidence: []string{"OLM regenerates RBAC when the CSV is reinstalled; extra rules were likely added by hand"},
+				})
+			}
+		}
+		if !drifted {
+			result.WriteString("  None.\n")
+		}
+	}
+
+	result.WriteString("\n")
+	writeFindings(&result, findings)
+
+	return &types.MCPToolResult{
+		Content: []types.MCPContent{{
+			Type: "text",
+			Text: result.String(),
+		}},
+	}, nil
+}
@@ -0,0 +1,66 @@
+package tools
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/operator-framework/api/pkg/operators/v1alpha1"
+	rbacv1 "k8s.io/api/rbac/v1"
+)
+
+// policyRuleString renders a rule compactly, e.g.
+// "apps: deployments,replicasets [get list watch]". Lists are sorted so that
+// equivalent rules render identically.
+func policyRuleString(rule rbacv1.PolicyRule) string {
+	rule = *rule.DeepCopy()
+	for _, list := range [][]string{rule.APIGroups, rule.Resources, rule.ResourceNames, rule.NonResourceURLs, rule.Verbs} {
+		sort.Strings(list)
+	}
+
+	if len(rule.NonResourceURLs) > 0 {
+		return fmt.Sprintf("nonResourceURLs %s [%s]", strings.Join(rule.NonResourceURLs, ","), strings.Join(rule.Verbs, " "))
+	}
+
+	groups := make([]string, 0, len(rule.APIGroups))
+	for _, group := range rule.APIGroups {
+		if group == "" {
+			group = "core"
+		}
+		groups = append(groups, group)
+	}
+	resources := strings.Join(rule.Resources, ",")
+	if len(rule.ResourceNames) > 0 {
+		resources += fmt.Sprintf(" (names: %s)", strings.Join(rule.ResourceNames, ","))
+	}
+	return fmt.Sprintf("%s: %s [%s]", strings.Join(groups, ","), resources, strings.Join(rule.Verbs, " "))
+}
+
+// permissionSet flattens the permissions of a CSV install strategy into
+// "scope serviceAccount: rule" strings so two CSVs can be compared.
+func permissionSet(strategy v1alpha1.StrategyDetailsDeployment) map[string]bool {
+	set := map[string]bool{}
+	for _, perm := range strategy.Permissions {
+		for _, rule := range perm.Rules {
+			set[fmt.Sprintf("namespace %s: %s", perm.ServiceAccountName, policyRuleString(rule))] = true
+		}
+	}
+	for _, perm := range strategy.ClusterPermissions {
+		for _, rule := range perm.Rules {
+			set[fmt.Sprintf("cluster %s: %s", perm.ServiceAccountName, policyRuleString(rule))] = true
+		}
+	}
+	return set
+}
+
+// setDifference returns the sorted entries of a that are not in b.
+func setDifference(a, b map[string]bool) []string {
+	var diff []string
+	for key := range a {
+		if !b[key] {
+			diff = append(diff, key)
+		}
+	}
+	sort.Strings(diff)
+	return diff
+}
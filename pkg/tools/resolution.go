@@ -0,0 +1,539 @@
+package tools
+
+import (
+	"context"
+	"fmt"
+	"regexp"
+	"sort"
+	"strings"
+
+	"github.com/operator-framework/api/pkg/operators/v1alpha1"
+	"github.com/operator-framework/operator-lifecycle-manager/olmv0-mcp-server/pkg/types"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+type violationKind int
+
+const (
+	violationUnknown violationKind = iota
+	violationMissingPackage
+	violationNoChannelHead
+	violationConflictingProvider
+	violationRequiredAPIMissing
+	violationInstalledCannotUpgrade
+	violationCatalogUnavailable
+)
+
+func (k violationKind) String() string {
+	switch k {
+	case violationMissingPackage:
+		return "Missing package"
+	case violationNoChannelHead:
+		return "No channel head"
+	case violationConflictingProvider:
+		return "Conflicting API provider"
+	case violationRequiredAPIMissing:
+		return "Required API not provided"
+	case violationInstalledCannotUpgrade:
+		return "Installed CSV cannot be upgraded"
+	case violationCatalogUnavailable:
+		return "Catalog unavailable"
+	default:
+		return "Unrecognized constraint"
+	}
+}
+
+// bundleID is how the resolver names a bundle in constraint messages:
+// "catalog/namespace/channel/csv", or "@existing/namespace//csv" for a CSV
+// already on the cluster.
+type bundleID struct {
+	Catalog   string
+	Namespace string
+	Channel   string
+	CSV       string
+}
+
+const existingCatalog = "@existing"
+
+func parseBundleID(s string) (bundleID, bool) {
+	parts := strings.Split(s, "/")
+	if len(parts) != 4 || parts[3] == "" {
+		return bundleID{}, false
+	}
+	return bundleID{Catalog: parts[0], Namespace: parts[1], Channel: parts[2], CSV: parts[3]}, true
+}
+
+func (b bundleID) installed() bool {
+	return b.Catalog == existingCatalog
+}
+
+// constraintViolation is one clause of a resolver failure, along with the
+// resources it mentions.
+type constraintViolation struct {
+	Kind          violationKind
+	Constraint    string
+	Subscriptions []string
+	Packages      []string
+	Catalogs      []string
+	CSVs          []string
+	Bundles       []bundleID
+	// GVK or version range of a missing dependency, if any.
+	Requirement  string
+	GVK          *metav1.GroupVersionKind
+	Explanation  string
+	SuggestedFix []string
+}
+
+func (v *constraintViolation) addBundle(id string) {
+	bundle, ok := parseBundleID(id)
+	if !ok {
+		v.CSVs = append(v.CSVs, id)
+		return
+	}
+	v.Bundles = append(v.Bundles, bundle)
+	v.CSVs = append(v.CSVs, bundle.CSV)
+	if !bundle.installed() {
+		v.Catalogs = append(v.Catalogs, bundle.Namespace+"/"+bundle.Catalog)
+	}
+}
+
+var (
+	reNoPackage       = regexp.MustCompile(`^no operators found in package (\S+) in the catalog referenced by subscription (\S+)$`)
+	reNoChannel       = regexp.MustCompile(`^no operators found in channel (\S+) of package (\S+) in the catalog referenced by subscription (\S+)$`)
+	reNoStartingCSV   = regexp.MustCompile(`^no operators found with name (\S+) in channel (\S+) of package (\S+) in the catalog referenced by subscription (\S+)$`)
+	reNoCatalog       = regexp.MustCompile(`^no operators found from catalog (\S+) in namespace (\S+) referenced by subscription (\S+)$`)
+	reNoChannelHeads  = regexp.MustCompile(`no channel heads \(entries not replaced by another entry\) found in channel "([^"]*)" of package "([^"]*)"`)
+	reMultipleHeads   = regexp.MustCompile(`multiple channel heads found in graph`)
+	reProvide         = regexp.MustCompile(`^(\S+) and (\S+) provide (\S+) \(([^)]*)\)$`)
+	reOriginate       = regexp.MustCompile(`^(\S+) and (\S+) originate from package (\S+)$`)
+	reRequiresAPI     = regexp.MustCompile(`^bundle (\S+) requires an operator providing an API with group: ([^,]*), version: ([^,]*), kind: (\S+)$`)
+	reRequiresPackage = regexp.MustCompile(`^bundle (\S+) requires an operator with package: (\S+) and with version in range: (.+)$`)
+	reNoCandidates    = regexp.MustCompile(`^(\S+) has a dependency without any candidates to satisfy it$`)
+	reNotReferenced   = regexp.MustCompile(`^clusterserviceversion (\S+) exists and is not referenced by a subscription$`)
+	reSubExists       = regexp.MustCompile(`^subscription (\S+) exists$`)
+	reSubRequires     = regexp.MustCompile(`^subscription (\S+) requires (?:at least one of )?(.+)$`)
+	// OLM names the catalog namespace/name when it cannot use a source, but
+	// prints its SourceKey, name/namespace, when it cannot cache one.
+	reCatalogError = regexp.MustCompile(`error using catalog ?source ([^/:\s]+)/([^:\s]+)`)
+	reCacheError   = regexp.MustCompile(`failed to populate resolver cache from source ([^/:\s]+)/([^:\s]+)`)
+)
+
+// splitConstraints splits a resolver message into its clauses. Clauses are
+// joined with ", ", which also separates the candidates in "requires at
+// least one of a, b" and the fields of a GVK, so those are glued back on.
+func splitConstraints(message string) []string {
+	message = strings.TrimSpace(message)
+	if i := strings.Index(message, "constraints not satisfiable:"); i >= 0 {
+		message = message[i+len("constraints not satisfiable:"):]
+	} else if reCatalogError.MatchString(message) || reCacheError.MatchString(message) {
+		return []string{message}
+	}
+
+	var clauses []string
+	for _, part := range strings.Split(message, ", ") {
+		part = strings.TrimSpace(part)
+		if part == "" {
+			continue
+		}
+		continuation := !strings.Contains(part, " ") ||
+			strings.HasPrefix(part, "version: ") ||
+			strings.HasPrefix(part, "kind: ")
+		if continuation && len(clauses) > 0 {
+			clauses[len(clauses)-1] += ", " + part
+			continue
+		}
+		clauses = append(clauses, part)
+	}
+	return clauses
+}
+
+// parseResolutionFailure turns a ResolutionFailed message into violations.
+// Clauses that only restate the Subscription ("subscription x exists",
+// "subscription x requires ...") are returned separately as context.
+func parseResolutionFailure(message string) ([]*constraintViolation, []string) {
+	var violations []*constraintViolation
+	var restated []string
+
+	for _, clause := range splitConstraints(message) {
+		v := &constraintViolation{Constraint: clause}
+
+		if m := reNoStartingCSV.FindStringSubmatch(clause); m != nil {
+			v.Kind = violationNoChannelHead
+			v.CSVs = []string{m[1]}
+			v.Packages = []string{m[3]}
+			v.Subscriptions = []string{m[4]}
+			v.Requirement = "channel " + m[2]
+			v.Explanation = fmt.Sprintf("The Subscription's startingCSV %s is not an entry of channel %s in the catalog.", m[1], m[2])
+		} else if m := reNoChannel.FindStringSubmatch(clause); m != nil {
+			v.Kind = violationNoChannelHead
+			v.Packages = []string{m[2]}
+			v.Subscriptions = []string{m[3]}
+			v.Requirement = "channel " + m[1]
+			v.Explanation = fmt.Sprintf("Package %s has no channel %s in the catalog, or the channel has no installable head.", m[2], m[1])
+		} else if m := reNoPackage.FindStringSubmatch(clause); m != nil {
+			v.Kind = violationMissingPackage
+			v.Packages = []string{m[1]}
+			v.Subscriptions = []string{m[2]}
+			v.Explanation = fmt.Sprintf("The catalog the Subscription points at does not contain package %s.", m[1])
+		} else if m := reNoCatalog.FindStringSubmatch(clause); m != nil {
+			v.Kind = violationMissingPackage
+			v.Catalogs = []string{m[2] + "/" + m[1]}
+			v.Subscriptions = []string{m[3]}
+			v.Explanation = fmt.Sprintf("CatalogSource %s/%s returned no operators for the Subscription; it may not exist, be unhealthy, or not serve the package.", m[2], m[1])
+		} else if m := reNoChannelHeads.FindStringSubmatch(clause); m != nil {
+			v.Kind = violationNoChannelHead
+			v.Packages = []string{m[2]}
+			v.Requirement = "channel " + m[1]
+			v.Explanation = "Every entry in the channel is replaced by another entry, so the upgrade graph has a cycle and no head."
+		} else if reMultipleHeads.MatchString(clause) {
+			v.Kind = violationNoChannelHead
+			v.Explanation = "The channel's upgrade graph has more than one head, so OLM cannot pick the latest entry."
+		} else if m := reProvide.FindStringSubmatch(clause); m != nil {
+			v.Kind = violationConflictingProvider
+			v.addBundle(m[1])
+			v.addBundle(m[2])
+			v.Requirement = fmt.Sprintf("%s (%s)", m[3], m[4])
+			v.Explanation = fmt.Sprintf("Both %s and %s provide %s; OLM allows only one provider of an API per namespace.", m[1], m[2], v.Requirement)
+		} else if m := reOriginate.FindStringSubmatch(clause); m != nil {
+			v.addBundle(m[1])
+			v.addBundle(m[2])
+			v.Packages = []string{m[3]}
+			v.Kind = violationConflictingProvider
+			v.Explanation = fmt.Sprintf("Only one bundle from package %s may be installed in a namespace.", m[3])
+			for _, bundle := range v.Bundles {
+				if bundle.installed() {
+					v.Kind = violationInstalledCannotUpgrade
+					v.Explanation = fmt.Sprintf("Installed CSV %s and the catalog candidate both come from package %s, and the candidate does not replace or skip the installed CSV, so there is no upgrade edge between them.", bundle.CSV, m[3])
+				}
+			}
+		} else if m := reRequiresAPI.FindStringSubmatch(clause); m != nil {
+			v.Kind = violationRequiredAPIMissing
+			v.addBundle(m[1])
+			v.GVK = &metav1.GroupVersionKind{Group: m[2], Version: m[3], Kind: m[4]}
+			v.Requirement = v.GVK.String()
+			v.Explanation = fmt.Sprintf("No installed operator or catalog bundle provides %s, which %s requires.", v.Requirement, m[1])
+		} else if m := reRequiresPackage.FindStringSubmatch(clause); m != nil {
+			v.Kind = violationRequiredAPIMissing
+			v.addBundle(m[1])
+			v.Packages = []string{m[2]}
+			v.Requirement = fmt.Sprintf("package %s %s", m[2], m[3])
+			v.Explanation = fmt.Sprintf("%s depends on package %s in version range %s, and no bundle satisfies it.", m[1], m[2], m[3])
+		} else if m := reNoCandidates.FindStringSubmatch(clause); m != nil {
+			v.Kind = violationRequiredAPIMissing
+			v.addBundle(m[1])
+			v.Explanation = fmt.Sprintf("%s declares a dependency that no installed operator or catalog bundle satisfies.", m[1])
+		} else if m := reNotReferenced.FindStringSubmatch(clause); m != nil {
+			v.Kind = violationInstalledCannotUpgrade
+			v.CSVs = []string{m[1]}
+			v.Explanation = fmt.Sprintf("CSV %s is installed but no Subscription tracks it, so OLM will not replace it and cannot install anything that conflicts with it.", m[1])
+		} else if m := reCatalogError.FindStringSubmatch(clause); m != nil {
+			v.Kind = violationCatalogUnavailable
+			v.Catalogs = []string{m[1] + "/" + m[2]}
+			v.Explanation = "OLM could not read the catalog, so it cannot resolve anything in this namespace."
+		} else if m := reCacheError.FindStringSubmatch(clause); m != nil {
+			v.Kind = violationCatalogUnavailable
+			v.Catalogs = []string{m[2] + "/" + m[1]}
+			v.Explanation = "OLM could not read the catalog, so it cannot resolve anything in this namespace."
+		} else if reSubExists.MatchString(clause) || reSubRequires.MatchString(clause) {
+			restated = append(restated, clause)
+			continue
+		} else {
+			v.Explanation = "This clause is not in a format this tool recognizes; see the raw message."
+		}
+
+		violations = append(violations, v)
+	}
+
+	return violations, restated
+}
+
+// resolutionAdvisor adds suggested fixes using what the catalogs visible to
+// the Subscription's namespace actually serve.
+type resolutionAdvisor struct {
+	subscription *v1alpha1.Subscription
+	packages     *types.PackageManifestList
+}
+
+func (a *resolutionAdvisor) suggest(v *constraintViolation) {
+	switch v.Kind {
+	case violationMissingPackage:
+		a.suggestPackage(v)
+	case violationNoChannelHead:
+		a.suggestChannel(v)
+	case violationConflictingProvider:
+		for _, bundle := range v.Bundles {
+			if bundle.installed() {
+				v.SuggestedFix = append(v.SuggestedFix, fmt.Sprintf("Uninstall the operator owning CSV %s if it is no longer needed, or install the new operator in a different namespace with its own OperatorGroup", bundle.CSV))
+			}
+		}
+		v.SuggestedFix = append(v.SuggestedFix, "Pick one operator to provide the API; two catalog bundles providing the same API cannot be installed together")
+	case violationRequiredAPIMissing:
+		a.suggestProvider(v)
+	case violationInstalledCannotUpgrade:
+		a.suggestUpgradePath(v)
+	case violationCatalogUnavailable:
+		for _, catalog := range v.Catalogs {
+			v.SuggestedFix = append(v.SuggestedFix, fmt.Sprintf("Run check_catalog_health for CatalogSource %s and fix its registry pod", catalog))
+		}
+	default:
+		v.SuggestedFix = append(v.SuggestedFix, "Read the raw message and the catalog-operator logs (get_olm_logs) for this Subscription")
+	}
+}
+
+func (a *resolutionAdvisor) suggestPackage(v *constraintViolation) {
+	if a.subscription == nil || a.packages == nil {
+		v.SuggestedFix = append(v.SuggestedFix, "Check spec.name, spec.source and spec.sourceNamespace of the Subscription against the catalog's packages")
+		return
+	}
+	sub := a.subscription
+	var sameName, similar []string
+	for _, pkg := range a.packages.Items {
+		name := pkg.Status.PackageName
+		switch {
+		case name == sub.Spec.Package:
+			sameName = append(sameName, fmt.Sprintf("%s/%s", pkg.Status.CatalogSourceNamespace, pkg.Status.CatalogSource))
+		case strings.Contains(name, sub.Spec.Package) || strings.Contains(sub.Spec.Package, name):
+			similar = append(similar, fmt.Sprintf("%s (catalog %s/%s)", name, pkg.Status.CatalogSourceNamespace, pkg.Status.CatalogSource))
+		}
+	}
+	if len(sameName) > 0 {
+		v.SuggestedFix = append(v.SuggestedFix, fmt.Sprintf("Package %s is served by CatalogSource %s; point spec.source and spec.sourceNamespace at it", sub.Spec.Package, strings.Join(sameName, ", ")))
+	}
+	if len(similar) > 0 {
+		sort.Strings(similar)
+		v.SuggestedFix = append(v.SuggestedFix, fmt.Sprintf("Similarly named packages: %s; check spec.name for a typo", strings.Join(similar, ", ")))
+	}
+	if len(sameName) == 0 {
+		v.SuggestedFix = append(v.SuggestedFix, fmt.Sprintf("No catalog visible to namespace %s serves package %s; add a CatalogSource that does, or run check_catalog_health on %s/%s", sub.Namespace, sub.Spec.Package, sub.Spec.CatalogSourceNamespace, sub.Spec.CatalogSource))
+	}
+}
+
+func (a *resolutionAdvisor) suggestChannel(v *constraintViolation) {
+	pkg := a.subscriptionPackage()
+	if pkg == nil {
+		v.SuggestedFix = append(v.SuggestedFix, "Check spec.channel and spec.startingCSV of the Subscription against the channels the package publishes")
+		return
+	}
+
+	var channels []string
+	for _, channel := range pkg.Status.Channels {
+		channels = append(channels, channel.Name)
+	}
+	sort.Strings(channels)
+	v.SuggestedFix = append(v.SuggestedFix, fmt.Sprintf("Package %s publishes channels: %s (default: %s); set spec.channel to one of them", pkg.Status.PackageName, strings.Join(channels, ", "), pkg.Status.DefaultChannelName))
+
+	if startingCSV := a.subscription.Spec.StartingCSV; startingCSV != "" {
+		if channel := pkg.GetChannel(a.subscription.Spec.Channel); channel != nil {
+			var entries []string
+			for _, entry := range channel.Entries {
+				entries = append(entries, entry.Name)
+			}
+			v.SuggestedFix = append(v.SuggestedFix, fmt.Sprintf("startingCSV %s must be one of the channel's entries: %s", startingCSV, strings.Join(entries, ", ")))
+		}
+	}
+	if reNoChannelHeads.MatchString(v.Constraint) || reMultipleHeads.MatchString(v.Constraint) {
+		v.SuggestedFix = append(v.SuggestedFix, "Fix the replaces/skips edges in the catalog so that exactly one entry is not replaced by another")
+	}
+}
+
+func (a *resolutionAdvisor) suggestProvider(v *constraintViolation) {
+	if a.packages == nil {
+		v.SuggestedFix = append(v.SuggestedFix, "Install an operator that provides the required API, or remove the dependency from the bundle")
+		return
+	}
+
+	var providers []string
+	for _, pkg := range a.packages.Items {
+		for _, channel := range pkg.Status.Channels {
+			provides := false
+			if gvk := v.GVK; gvk != nil {
+				for _, crd := range channel.CurrentCSVDesc.CustomResourceDefinitions.Owned {
+					if crd.Kind == gvk.Kind && crd.Version == gvk.Version && strings.HasSuffix(crd.Name, "."+gvk.Group) {
+						provides = true
+					}
+				}
+				for _, api := range channel.CurrentCSVDesc.APIServiceDefinitions.Owned {
+					if api.Kind == gvk.Kind && api.Version == gvk.Version && api.Group == gvk.Group {
+						provides = true
+					}
+				}
+			} else {
+				for _, name := range v.Packages {
+					provides = provides || pkg.Status.PackageName == name
+				}
+			}
+			if provides {
+				providers = append(providers, fmt.Sprintf("package %s, channel %s (catalog %s/%s)", pkg.Status.PackageName, channel.Name, pkg.Status.CatalogSourceNamespace, pkg.Status.CatalogSource))
+			}
+		}
+	}
+
+	if len(providers) == 0 {
+		v.SuggestedFix = append(v.SuggestedFix, fmt.Sprintf("No catalog visible to this namespace provides %s; add a CatalogSource that does or remove the dependency from the bundle", v.Requirement))
+		return
+	}
+	sort.Strings(providers)
+	v.SuggestedFix = append(v.SuggestedFix, fmt.Sprintf("Provided by %s; OLM installs it automatically only if that channel is the package's default, otherwise create a Subscription for it", strings.Join(providers, "; ")))
+}
+
+func (a *resolutionAdvisor) suggestUpgradePath(v *constraintViolation) {
+	pkg := a.subscriptionPackage()
+	sub := a.subscription
+	if pkg == nil || sub.Status.InstalledCSV == "" {
+		v.SuggestedFix = append(v.SuggestedFix, "If the CSV is a leftover of a deleted Subscription, delete the CSV or re-create a Subscription for it")
+		return
+	}
+
+	channel, index := findChannelEntry(pkg, sub.Spec.Channel, sub.Status.InstalledCSV)
+	switch {
+	case channel == nil:
+		v.SuggestedFix = append(v.SuggestedFix, fmt.Sprintf("Installed CSV %s is no longer in any channel of package %s, so the catalog has no upgrade edge from it; ask the catalog owner to add it to a replaces/skips/skipRange, or reinstall (delete the Subscription and CSV, then re-subscribe)", sub.Status.InstalledCSV, pkg.Status.PackageName))
+	case channel.Name != sub.Spec.Channel:
+		v.SuggestedFix = append(v.SuggestedFix, fmt.Sprintf("Installed CSV %s is in channel %s, not in the subscribed channel %s; switch back to %s or pick a channel that contains an upgrade edge from it", sub.Status.InstalledCSV, channel.Name, sub.Spec.Channel, channel.Name))
+	default:
+		v.SuggestedFix = append(v.SuggestedFix, fmt.Sprintf("Installed CSV %s is entry %d of channel %s; check that a newer entry replaces or skips it (including skipRange)", sub.Status.InstalledCSV, index+1, channel.Name))
+	}
+	for _, csv := range v.CSVs {
+		if csv != sub.Status.InstalledCSV && csv != sub.Status.CurrentCSV {
+			v.SuggestedFix = append(v.SuggestedFix, fmt.Sprintf("If CSV %s was left behind by a deleted Subscription, delete it or create a Subscription that tracks it", csv))
+		}
+	}
+}
+
+func (a *resolutionAdvisor) subscriptionPackage() *types.PackageManifest {
+	if a.subscription == nil || a.packages == nil {
+		return nil
+	}
+	pkg, err := matchPackageManifest(a.packages, a.subscription)
+	if err != nil {
+		return nil
+	}
+	return pkg
+}
+
+// ExplainResolutionFailure parses a Subscription's ResolutionFailed condition
+// into constraint violations with suggested fixes.
+func (t *DiagnosticTools) ExplainResolutionFailure(ctx context.Context, params map[string]string) (*types.MCPToolResult, error) {
+	namespace := params["namespace"]
+	name := params["name"]
+	message := params["message"]
+
+	if namespace == "" {
+		namespace = "default"
+	}
+	if name == "" && message == "" {
+		return &types.MCPToolResult{
+			Content: []types.MCPContent{{
+				Type: "text",
+				Text: "Error: either 'name' (a Subscription) or 'message' (a resolution failure message) is required",
+			}},
+			IsError: true,
+		}, nil
+	}
+
+	var result strings.Builder
+	advisor := &resolutionAdvisor{}
+
+	if name != "" {
+		subscription, err := t.server.OLMClient.GetSubscription(ctx, namespace, name)
+		if err != nil {
+			return &types.MCPToolResult{
+				Content: []types.MCPContent{{
+					Type: "text",
+					Text: fmt.Sprintf("Error getting Subscription '%s': %v", name, err),
+				}},
+				IsError: true,
+			}, nil
+		}
+		advisor.subscription = subscription
+		if packages, err := t.server.OLMClient.ListPackageManifests(ctx, namespace); err == nil {
+			advisor.packages = packages
+		}
+
+		result.WriteString(fmt.Sprintf("Resolution failure for Subscription %s/%s (package %s, channel %s, catalog %s/%s):\n\n",
+			namespace, name, subscription.Spec.Package, subscription.Spec.Channel, subscription.Spec.CatalogSourceNamespace, subscription.Spec.CatalogSource))
+
+		if message == "" {
+			var failed *v1alpha1.SubscriptionCondition
+			for i := range subscription.Status.Conditions {
+				cond := &subscription.Status.Conditions[i]
+				if cond.Type == v1alpha1.SubscriptionResolutionFailed && cond.Status == corev1.ConditionTrue {
+					failed = cond
+				}
+			}
+			if failed == nil {
+				result.WriteString("The Subscription has no ResolutionFailed=True condition; OLM resolved it successfully.\n")
+				return &types.MCPToolResult{
+					Content: []types.MCPContent{{
+						Type: "text",
+						Text: result.String(),
+					}},
+				}, nil
+			}
+			message = failed.Message
+			result.WriteString(fmt.Sprintf("Condition: ResolutionFailed=True reason=%s, last transition %s\n", failed.Reason, formatTime(failed.LastTransitionTime)))
+		}
+	} else {
+		result.WriteString("Resolution failure message analysis:\n\n")
+	}
+
+	result.WriteString(fmt.Sprintf("Raw message:\n  %s\n\n", message))
+
+	violations, restated := parseResolutionFailure(message)
+	if len(restated) > 0 {
+		result.WriteString("Subscription context:\n")
+		for _, clause := range restated {
+			result.WriteString(fmt.Sprintf("  - %s\n", clause))
+		}
+		result.WriteString("\n")
+	}
+
+	result.WriteString(fmt.Sprintf("Constraint violations (%d):\n", len(violations)))
+	if len(violations) == 0 {
+		result.WriteString("None recognized; the message only restates the Subscription. Check the catalog-operator logs with get_olm_logs.\n")
+	}
+	for i, v := range violations {
+		advisor.suggest(v)
+
+		result.WriteString(fmt.Sprintf("\n%d. [%s] %s\n", i+1, v.Kind, v.Constraint))
+		writeInvolved := func(label string, values []string) {
+			if len(values) > 0 {
+				result.WriteString(fmt.Sprintf("   %s: %s\n", label, strings.Join(uniqueStrings(values), ", ")))
+			}
+		}
+		writeInvolved("Subscriptions", v.Subscriptions)
+		writeInvolved("Packages", v.Packages)
+		writeInvolved("Catalogs", v.Catalogs)
+		writeInvolved("CSVs", v.CSVs)
+		if v.Requirement != "" {
+			result.WriteString(fmt.Sprintf("   Requirement: %s\n", v.Requirement))
+		}
+		result.WriteString(fmt.Sprintf("   Explanation: %s\n", v.Explanation))
+		result.WriteString("   Suggested fixes:\n")
+		for _, fix := range v.SuggestedFix {
+			result.WriteString(fmt.Sprintf("     - %s\n", fix))
+		}
+	}
+
+	return &types.MCPToolResult{
+		Content: []types.MCPContent{{
+			Type: "text",
+			Text: result.String(),
+		}},
+	}, nil
+}
+
+// uniqueStrings returns values without duplicates, keeping the first
+// occurrence of each.
+func uniqueStrings(values []string) []string {
+	seen := map[string]bool{}
+	var unique []string
+	for _, value := range values {
+		if !seen[value] {
+			seen[value] = true
+			unique = append(unique, value)
+		}
+	}
+	return unique
+}
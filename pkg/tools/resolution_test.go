@@ -0,0 +1,143 @@
+package tools
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestSplitConstraints(t *testing.T) {
+	tests := []struct {
+		name    string
+		message string
+		want    []string
+	}{
+		{
+			name:    "candidates and GVK fields stay with their clause",
+			message: "constraints not satisfiable: subscription etcd requires at least one of community/olm/alpha/etcdoperator.v0.9.4, community/olm/alpha/etcdoperator.v0.9.2, bundle etcdoperator.v0.9.4 requires an operator providing an API with group: etcd.database.coreos.com, version: v1beta2, kind: EtcdBackup, subscription etcd exists",
+			want: []string{
+				"subscription etcd requires at least one of community/olm/alpha/etcdoperator.v0.9.4, community/olm/alpha/etcdoperator.v0.9.2",
+				"bundle etcdoperator.v0.9.4 requires an operator providing an API with group: etcd.database.coreos.com, version: v1beta2, kind: EtcdBackup",
+				"subscription etcd exists",
+			},
+		},
+		{
+			name:    "catalog errors are one clause",
+			message: "failed to populate resolver cache from source broken/olm: failed to list bundles: rpc error: code = Unavailable, desc = connection refused",
+			want:    []string{"failed to populate resolver cache from source broken/olm: failed to list bundles: rpc error: code = Unavailable, desc = connection refused"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := splitConstraints(tt.message); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("splitConstraints() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestParseResolutionFailure(t *testing.T) {
+	type violation struct {
+		Kind        violationKind
+		CSVs        []string
+		Packages    []string
+		Catalogs    []string
+		Requirement string
+	}
+	tests := []struct {
+		name     string
+		message  string
+		want     []violation
+		restated int
+	}{
+		{
+			name:     "missing package",
+			message:  "constraints not satisfiable: no operators found in package etcd in the catalog referenced by subscription etcd, subscription etcd exists",
+			want:     []violation{{Kind: violationMissingPackage, Packages: []string{"etcd"}}},
+			restated: 1,
+		},
+		{
+			name:     "missing channel",
+			message:  "constraints not satisfiable: no operators found in channel beta of package etcd in the catalog referenced by subscription etcd, subscription etcd exists",
+			want:     []violation{{Kind: violationNoChannelHead, Packages: []string{"etcd"}, Requirement: "channel beta"}},
+			restated: 1,
+		},
+		{
+			name:    "missing startingCSV",
+			message: "constraints not satisfiable: no operators found with name etcdoperator.v0.9.9 in channel alpha of package etcd in the catalog referenced by subscription etcd",
+			want:    []violation{{Kind: violationNoChannelHead, CSVs: []string{"etcdoperator.v0.9.9"}, Packages: []string{"etcd"}, Requirement: "channel alpha"}},
+		},
+		{
+			name:    "required API",
+			message: "constraints not satisfiable: bundle cluster-logging.4.6.0 requires an operator providing an API with group: logging.openshift.io, version: v1, kind: ClusterLogging, subscription cluster-logging exists, subscription cluster-logging requires redhat-operators/openshift-marketplace/4.6/cluster-logging.4.6.0",
+			want: []violation{{
+				Kind:        violationRequiredAPIMissing,
+				CSVs:        []string{"cluster-logging.4.6.0"},
+				Requirement: "logging.openshift.io/v1, Kind=ClusterLogging",
+			}},
+			restated: 2,
+		},
+		{
+			name:    "required package",
+			message: "constraints not satisfiable: bundle community/olm/stable/app.v1.2.0 requires an operator with package: database and with version in range: >=2.0.0 <3.0.0",
+			want: []violation{{
+				Kind:        violationRequiredAPIMissing,
+				CSVs:        []string{"app.v1.2.0"},
+				Packages:    []string{"database"},
+				Catalogs:    []string{"olm/community"},
+				Requirement: "package database >=2.0.0 <3.0.0",
+			}},
+		},
+		{
+			name:    "installed CSV without an upgrade edge",
+			message: "constraints not satisfiable: subscription app exists, subscription app requires at least one of community/olm/stable/app.v2.0.0, @existing/operators//app.v1.0.0 and community/olm/stable/app.v2.0.0 originate from package app, clusterserviceversion app.v1.0.0 exists and is not referenced by a subscription",
+			want: []violation{
+				{Kind: violationInstalledCannotUpgrade, CSVs: []string{"app.v1.0.0", "app.v2.0.0"}, Packages: []string{"app"}, Catalogs: []string{"olm/community"}},
+				{Kind: violationInstalledCannotUpgrade, CSVs: []string{"app.v1.0.0"}},
+			},
+			restated: 2,
+		},
+		{
+			name:    "conflicting API providers",
+			message: "constraints not satisfiable: @existing/operators//widgets.v1.0.0 and community/olm/stable/gadgets.v3.1.0 provide Widget (example.com/v1), subscription gadgets exists",
+			want: []violation{{
+				Kind:        violationConflictingProvider,
+				CSVs:        []string{"widgets.v1.0.0", "gadgets.v3.1.0"},
+				Catalogs:    []string{"olm/community"},
+				Requirement: "Widget (example.com/v1)",
+			}},
+			restated: 1,
+		},
+		{
+			name:    "catalog unavailable",
+			message: "failed to populate resolver cache from source broken/olm: failed to list bundles: rpc error: code = Unavailable desc = connection error",
+			want:    []violation{{Kind: violationCatalogUnavailable, Catalogs: []string{"olm/broken"}}},
+		},
+		{
+			name:    "catalog source unusable",
+			message: "error using catalogsource olm/broken: failed to list bundles: rpc error: code = Unavailable desc = connection error",
+			want:    []violation{{Kind: violationCatalogUnavailable, Catalogs: []string{"olm/broken"}}},
+		},
+		{
+			name:    "unrecognized clause",
+			message: "constraints not satisfiable: something new the resolver says",
+			want:    []violation{{Kind: violationUnknown}},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			violations, restated := parseResolutionFailure(tt.message)
+			var got []violation
+			for _, v := range violations {
+				got = append(got, violation{Kind: v.Kind, CSVs: v.CSVs, Packages: v.Packages, Catalogs: v.Catalogs, Requirement: v.Requirement})
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("parseResolutionFailure() violations = %+v, want %+v", got, tt.want)
+			}
+			if len(restated) != tt.restated {
+				t.Errorf("parseResolutionFailure() restated %q, want %d clauses", restated, tt.restated)
+			}
+		})
+	}
+}
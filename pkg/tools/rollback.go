@@ -0,0 +1,239 @@
+package tools
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/operator-framework/api/pkg/operators/v1alpha1"
+	"github.com/operator-framework/operator-lifecycle-manager/olmv0-mcp-server/pkg/types"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+)
+
+// RollbackOperator reinstalls the CSV that the Subscription's installed CSV
+// replaced. OLM cannot downgrade in place, so this follows the supported
+// procedure: delete the Subscription and the installed CSV, then recreate the
+// Subscription pinned to the previous CSV with Manual approval.
+func (t *SubscriptionTools) RollbackOperator(ctx context.Context, params map[string]string) (*types.MCPToolResult, error) {
+	namespace := params["namespace"]
+	name := params["name"]
+	targetCSV := params["target_csv"]
+	dryRun := boolParam(params, "dry_run")
+
+	if namespace == "" {
+		namespace = "default"
+	}
+	if name == "" {
+		return &types.MCPToolResult{
+			Content: []types.MCPContent{{
+				Type: "text",
+				Text: "Error: 'name' parameter is required",
+			}},
+			IsError: true,
+		}, nil
+	}
+	if denied := checkWriteAllowed(t.server, dryRun); denied != nil {
+		return denied, nil
+	}
+
+	subscription, err := t.server.OLMClient.GetSubscription(ctx, namespace, name)
+	if err != nil {
+		return &types.MCPToolResult{
+			Content: []types.MCPContent{{
+				Type: "text",
+				Text: fmt.Sprintf("Error getting Subscription '%s': %v", name, err),
+			}},
+			IsError: true,
+		}, nil
+	}
+
+	installedCSV := subscription.Status.InstalledCSV
+	if installedCSV == "" {
+		return &types.MCPToolResult{
+			Content: []types.MCPContent{{
+				Type: "text",
+				Text: fmt.Sprintf("Error: Subscription '%s' has no installed CSV to roll back from", name),
+			}},
+			IsError: true,
+		}, nil
+	}
+
+	csv, err := t.server.OLMClient.GetClusterServiceVersion(ctx, namespace, installedCSV)
+	if err != nil && !apierrors.IsNotFound(err) {
+		return &types.MCPToolResult{
+			Content: []types.MCPContent{{
+				Type: "text",
+				Text: fmt.Sprintf("Error getting ClusterServiceVersion '%s': %v", installedCSV, err),
+			}},
+			IsError: true,
+		}, nil
+	}
+	if err != nil {
+		csv = nil
+	}
+
+	pkg, err := findPackageManifest(ctx, t.server, subscription)
+	if err != nil {
+		return &types.MCPToolResult{
+			Content: []types.MCPContent{{
+				Type: "text",
+				Text: fmt.Sprintf("Error: cannot roll back without catalog content, since OLM can only install CSVs its catalog still serves: %v", err),
+			}},
+			IsError: true,
+		}, nil
+	}
+
+	previousCSV, foundVia := targetCSV, "requested explicitly"
+	if previousCSV == "" && csv != nil && csv.Spec.Replaces != "" {
+		previousCSV, foundVia = csv.Spec.Replaces, fmt.Sprintf("spec.replaces of %s", installedCSV)
+	}
+	if previousCSV == "" {
+		// Bundles that upgrade through skips or skipRange may not set
+		// replaces; fall back to the next older entry in the channel.
+		if channel, i := findChannelEntry(pkg, subscription.Spec.Channel, installedCSV); channel != nil && i+1 < len(channel.Entries) {
+			previousCSV, foundVia = channel.Entries[i+1].Name, fmt.Sprintf("next older entry in channel '%s'", channel.Name)
+		}
+	}
+	if previousCSV == "" {
+		return &types.MCPToolResult{
+			Content: []types.MCPContent{{
+				Type: "text",
+				Text: fmt.Sprintf("Error: could not determine the CSV that %s replaced; it has no spec.replaces and no older entry in the catalog. Pass 'target_csv' to choose one.", installedCSV),
+			}},
+			IsError: true,
+		}, nil
+	}
+
+	channel, _ := findChannelEntry(pkg, subscription.Spec.Channel, previousCSV)
+	if channel == nil {
+		return &types.MCPToolResult{
+			Content: []types.MCPContent{{
+				Type: "text",
+				Text: fmt.Sprintf("Error: CSV '%s' is not served by any channel of package '%s' in CatalogSource %s/%s, so OLM cannot install it. Add a catalog that still contains it and retry.",
+					previousCSV, pkg.Status.PackageName, pkg.Status.CatalogSourceNamespace, pkg.Status.CatalogSource),
+			}},
+			IsError: true,
+		}, nil
+	}
+
+	replacement := &v1alpha1.Subscription{
+		TypeMeta: metav1.TypeMeta{
+			APIVersion: v1alpha1.SubscriptionCRDAPIVersion,
+			Kind:       v1alpha1.SubscriptionKind,
+		},
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      subscription.Name,
+			Namespace: subscription.Namespace,
+			Labels:    subscription.Labels,
+		},
+		Spec: subscription.Spec.DeepCopy(),
+	}
+	replacement.Spec.Channel = channel.Name
+	replacement.Spec.StartingCSV = previousCSV
+	replacement.Spec.InstallPlanApproval = v1alpha1.ApprovalManual
+
+	var result strings.Builder
+	result.WriteString(fmt.Sprintf("Rollback of Subscription %s/%s\n\n", namespace, name))
+	result.WriteString(fmt.Sprintf("  Package: %s\n", subscription.Spec.Package))
+	result.WriteString(fmt.Sprintf("  Installed CSV: %s\n", installedCSV))
+	if subscription.Status.CurrentCSV != "" && subscription.Status.CurrentCSV != installedCSV {
+		result.WriteString(fmt.Sprintf("  Pending CSV: %s (the in-progress upgrade will be abandoned)\n", subscription.Status.CurrentCSV))
+	}
+	result.WriteString(fmt.Sprintf("  Roll back to: %s (%s)\n", previousCSV, foundVia))
+	result.WriteString(fmt.Sprintf("  Channel: %s\n\n", channel.Name))
+
+	result.WriteString("OLM downgrade limitations:\n")
+	result.WriteString("  - OLM never downgrades in place; the only supported path is to remove the Subscription and CSV and install the older CSV fresh.\n")
+	result.WriteString("  - CRDs and custom resources are left untouched. If the newer version changed CRD schemas or stored versions, the older operator may fail to read existing resources.\n")
+	result.WriteString("  - The operator's workloads are removed with the CSV and are unavailable until the older CSV finishes installing.\n")
+	result.WriteString("  - The recreated Subscription uses Manual approval so OLM does not immediately upgrade again. Approve the new InstallPlan with approve_install_plan.\n\n")
+
+	steps := []writeOperation{
+		{
+			Action:    "deletion",
+			Kind:      v1alpha1.SubscriptionKind,
+			Namespace: namespace,
+			Name:      name,
+			Live:      subscription,
+			Apply: func(ctx context.Context, dryRun bool) (runtime.Object, error) {
+				return nil, t.server.OLMClient.DeleteSubscription(ctx, namespace, name, dryRun)
+			},
+		},
+	}
+	if csv != nil {
+		steps = append(steps, writeOperation{
+			Action:    "deletion",
+			Kind:      v1alpha1.ClusterServiceVersionKind,
+			Namespace: namespace,
+			Name:      installedCSV,
+			Live:      csv,
+			Apply: func(ctx context.Context, dryRun bool) (runtime.Object, error) {
+				return nil, t.server.OLMClient.DeleteClusterServiceVersion(ctx, namespace, installedCSV, dryRun)
+			},
+		})
+	} else {
+		result.WriteString(fmt.Sprintf("ClusterServiceVersion '%s' no longer exists; skipping its deletion.\n\n", installedCSV))
+	}
+	steps = append(steps, writeOperation{
+		Action:    "creation",
+		Kind:      v1alpha1.SubscriptionKind,
+		Namespace: namespace,
+		Name:      name,
+		Apply: func(ctx context.Context, dryRun bool) (runtime.Object, error) {
+			return t.server.OLMClient.CreateSubscription(ctx, replacement, dryRun)
+		},
+	})
+
+	for i, step := range steps {
+		result.WriteString(fmt.Sprintf("Step %d/%d: %s of %s '%s'\n", i+1, len(steps), step.Action, step.Kind, step.Name))
+
+		if dryRun && step.Live == nil {
+			// The Subscription still exists during a dry run, so the API
+			// server would reject a dry-run create; render it locally.
+			manifest, err := renderManifest(replacement)
+			if err != nil {
+				return &types.MCPToolResult{
+					Content: []types.MCPContent{{
+						Type: "text",
+						Text: fmt.Sprintf("%sError rendering Subscription: %v", result.String(), err),
+					}},
+					IsError: true,
+				}, nil
+			}
+			result.WriteString("Planned creation (rendered locally; the server-side dry-run is only possible once the old Subscription is gone):\n")
+			result.WriteString(fmt.Sprintf("```yaml\n%s```\n", manifest))
+			result.WriteString("Dry run only: no changes were persisted.\n\n")
+			continue
+		}
+
+		report, err := executeWrite(ctx, step, dryRun)
+		result.WriteString(report)
+		if err != nil {
+			result.WriteString(fmt.Sprintf("Error: %v\n", err))
+			result.WriteString(fmt.Sprintf("Rollback stopped after %d of %d steps completed.\n", i, len(steps)))
+			return &types.MCPToolResult{
+				Content: []types.MCPContent{{
+					Type: "text",
+					Text: result.String(),
+				}},
+				IsError: true,
+			}, nil
+		}
+		result.WriteString("\n")
+	}
+
+	if dryRun {
+		result.WriteString("Dry run complete. Re-run without 'dry_run' to perform the rollback.\n")
+	} else {
+		result.WriteString(fmt.Sprintf("Rollback submitted. Approve the InstallPlan that OLM creates for %s to finish installing it.\n", previousCSV))
+	}
+
+	return &types.MCPToolResult{
+		Content: []types.MCPContent{{
+			Type: "text",
+			Text: result.String(),
+		}},
+	}, nil
+}
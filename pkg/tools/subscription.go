@@ -6,7 +6,9 @@ import (
 	"fmt"
 	"strings"
 
+	"github.com/operator-framework/api/pkg/operators/v1alpha1"
 	"github.com/operator-framework/operator-lifecycle-manager/olmv0-mcp-server/pkg/types"
+	"k8s.io/apimachinery/pkg/runtime"
 )
 
 type SubscriptionTools struct {
@@ -112,6 +114,15 @@ func (t *SubscriptionTools) GetSubscription(ctx context.Context, params map[stri
 	result.WriteString(fmt.Sprintf("  Installed CSV: %s\n", subscription.Status.InstalledCSV))
 	result.WriteString(fmt.Sprintf("  Current CSV: %s\n\n", subscription.Status.CurrentCSV))
 
+	if hasDeprecationCondition(subscription) {
+		pkg, _ := findPackageManifest(ctx, t.server, subscription)
+		result.WriteString("Deprecations:\n")
+		writeDeprecationLines(&result, subscription, pkg, subscriptionDeprecations(subscription, pkg))
+		result.WriteString("\n")
+	}
+
+	writeEventsSection(ctx, &result, t.server, v1alpha1.SubscriptionKind, namespace, name)
+
 	result.WriteString("Full JSON representation:\n")
 	result.WriteString("```json\n")
 	result.WriteString(string(jsonData))
@@ -123,4 +134,92 @@ func (t *SubscriptionTools) GetSubscription(ctx context.Context, params map[stri
 			Text: result.String(),
 		}},
 	}, nil
-}
\ No newline at end of file
+}
+
+func (t *SubscriptionTools) UpdateSubscription(ctx context.Context, params map[string]string) (*types.MCPToolResult, error) {
+	namespace := params["namespace"]
+	name := params["name"]
+	channel := params["channel"]
+	approval := params["approval"]
+	dryRun := boolParam(params, "dry_run")
+
+	if namespace == "" {
+		namespace = "default"
+	}
+	if name == "" {
+		return &types.MCPToolResult{
+			Content: []types.MCPContent{{
+				Type: "text",
+				Text: "Error: 'name' parameter is required",
+			}},
+			IsError: true,
+		}, nil
+	}
+	if channel == "" && approval == "" {
+		return &types.MCPToolResult{
+			Content: []types.MCPContent{{
+				Type: "text",
+				Text: "Error: at least one of 'channel' or 'approval' must be set",
+			}},
+			IsError: true,
+		}, nil
+	}
+	if approval != "" && approval != string(v1alpha1.ApprovalAutomatic) && approval != string(v1alpha1.ApprovalManual) {
+		return &types.MCPToolResult{
+			Content: []types.MCPContent{{
+				Type: "text",
+				Text: fmt.Sprintf("Error: 'approval' must be %q or %q", v1alpha1.ApprovalAutomatic, v1alpha1.ApprovalManual),
+			}},
+			IsError: true,
+		}, nil
+	}
+	if denied := checkWriteAllowed(t.server, dryRun); denied != nil {
+		return denied, nil
+	}
+
+	subscription, err := t.server.OLMClient.GetSubscription(ctx, namespace, name)
+	if err != nil {
+		return &types.MCPToolResult{
+			Content: []types.MCPContent{{
+				Type: "text",
+				Text: fmt.Sprintf("Error getting Subscription '%s': %v", name, err),
+			}},
+			IsError: true,
+		}, nil
+	}
+
+	updated := subscription.DeepCopy()
+	if channel != "" {
+		updated.Spec.Channel = channel
+	}
+	if approval != "" {
+		updated.Spec.InstallPlanApproval = v1alpha1.Approval(approval)
+	}
+
+	report, err := executeWrite(ctx, writeOperation{
+		Action:    "update",
+		Kind:      v1alpha1.SubscriptionKind,
+		Namespace: namespace,
+		Name:      name,
+		Live:      subscription,
+		Apply: func(ctx context.Context, dryRun bool) (runtime.Object, error) {
+			return t.server.OLMClient.UpdateSubscription(ctx, updated, dryRun)
+		},
+	}, dryRun)
+	if err != nil {
+		return &types.MCPToolResult{
+			Content: []types.MCPContent{{
+				Type: "text",
+				Text: fmt.Sprintf("%sError updating Subscription '%s': %v", report, name, err),
+			}},
+			IsError: true,
+		}, nil
+	}
+
+	return &types.MCPToolResult{
+		Content: []types.MCPContent{{
+			Type: "text",
+			Text: report,
+		}},
+	}, nil
+}
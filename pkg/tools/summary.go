@@ -0,0 +1,329 @@
+package tools
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strings"
+	"sync"
+
+	"github.com/operator-framework/api/pkg/operators/v1alpha1"
+	"github.com/operator-framework/operator-lifecycle-manager/olmv0-mcp-server/pkg/types"
+	corev1 "k8s.io/api/core/v1"
+)
+
+// olmSummary is the structured payload of summarize_olm.
+type olmSummary struct {
+	Namespace        string             `json:"namespace,omitempty"`
+	CSVs             csvSummary         `json:"clusterServiceVersions"`
+	Subscriptions    subscriptionCounts `json:"subscriptions"`
+	CatalogSources   catalogSummary     `json:"catalogSources"`
+	InstallPlans     installPlanCounts  `json:"installPlans"`
+	PendingApprovals []pendingApproval  `json:"pendingApprovals"`
+	Errors           []string           `json:"errors,omitempty"`
+}
+
+type csvSummary struct {
+	Total   int            `json:"total"`
+	Copied  int            `json:"copied"`
+	ByPhase map[string]int `json:"byPhase"`
+	// Failed lists "namespace/name" of CSVs in the Failed phase.
+	Failed []string `json:"failed"`
+}
+
+type subscriptionCounts struct {
+	Total   int                   `json:"total"`
+	Failing []failingSubscription `json:"failing"`
+}
+
+type failingSubscription struct {
+	Namespace  string   `json:"namespace"`
+	Name       string   `json:"name"`
+	Package    string   `json:"package"`
+	Conditions []string `json:"conditions"`
+}
+
+type catalogSummary struct {
+	Total     int            `json:"total"`
+	ByState   map[string]int `json:"byConnectionState"`
+	Unhealthy []string       `json:"unhealthy"`
+}
+
+type installPlanCounts struct {
+	Total   int            `json:"total"`
+	ByPhase map[string]int `json:"byPhase"`
+}
+
+type pendingApproval struct {
+	Namespace     string   `json:"namespace"`
+	InstallPlan   string   `json:"installPlan"`
+	CSVs          []string `json:"csvs"`
+	Subscriptions []string `json:"subscriptions"`
+}
+
+// olmInventory is the result of listing every OLM resource kind at once.
+type olmInventory struct {
+	csvs          *v1alpha1.ClusterServiceVersionList
+	subscriptions *v1alpha1.SubscriptionList
+	catalogs      *v1alpha1.CatalogSourceList
+	installPlans  *v1alpha1.InstallPlanList
+	errors        []string
+}
+
+// listOLMInventory fans out the OLM list calls concurrently. A failed call
+// is recorded rather than failing the whole inventory.
+func listOLMInventory(ctx context.Context, client types.OLMClientInterface, namespace string) *olmInventory {
+	inventory := &olmInventory{}
+	var mu sync.Mutex
+	var wg sync.WaitGroup
+
+	record := func(kind string, err error) {
+		mu.Lock()
+		defer mu.Unlock()
+		inventory.errors = append(inventory.errors, fmt.Sprintf("listing %s: %v", kind, err))
+	}
+
+	wg.Add(4)
+	go func() {
+		defer wg.Done()
+		list, err := client.ListClusterServiceVersions(ctx, namespace)
+		if err != nil {
+			record("ClusterServiceVersions", err)
+			return
+		}
+		inventory.csvs = list
+	}()
+	go func() {
+		defer wg.Done()
+		list, err := client.ListSubscriptions(ctx, namespace)
+		if err != nil {
+			record("Subscriptions", err)
+			return
+		}
+		inventory.subscriptions = list
+	}()
+	go func() {
+		defer wg.Done()
+		list, err := client.ListCatalogSources(ctx, namespace)
+		if err != nil {
+			record("CatalogSources", err)
+			return
+		}
+		inventory.catalogs = list
+	}()
+	go func() {
+		defer wg.Done()
+		list, err := client.ListInstallPlans(ctx, namespace)
+		if err != nil {
+			record("InstallPlans", err)
+			return
+		}
+		inventory.installPlans = list
+	}()
+	wg.Wait()
+
+	sort.Strings(inventory.errors)
+	return inventory
+}
+
+func summarizeInventory(namespace string, inventory *olmInventory) *olmSummary {
+	summary := &olmSummary{
+		Namespace:        namespace,
+		CSVs:             csvSummary{ByPhase: map[string]int{}, Failed: []string{}},
+		Subscriptions:    subscriptionCounts{Failing: []failingSubscription{}},
+		CatalogSources:   catalogSummary{ByState: map[string]int{}, Unhealthy: []string{}},
+		InstallPlans:     installPlanCounts{ByPhase: map[string]int{}},
+		PendingApprovals: []pendingApproval{},
+		Errors:           inventory.errors,
+	}
+
+	if inventory.csvs != nil {
+		for i := range inventory.csvs.Items {
+			csv := &inventory.csvs.Items[i]
+			if csv.IsCopied() {
+				summary.CSVs.Copied++
+				continue
+			}
+			summary.CSVs.Total++
+			summary.CSVs.ByPhase[phaseOrNone(csv.Status.Phase)]++
+			if csv.Status.Phase == v1alpha1.CSVPhaseFailed {
+				summary.CSVs.Failed = append(summary.CSVs.Failed, csv.Namespace+"/"+csv.Name)
+			}
+		}
+	}
+
+	// Subscriptions referencing each InstallPlan, for the approvals list.
+	planSubscriptions := map[string][]string{}
+	if inventory.subscriptions != nil {
+		for i := range inventory.subscriptions.Items {
+			sub := &inventory.subscriptions.Items[i]
+			summary.Subscriptions.Total++
+			if ref := sub.Status.InstallPlanRef; ref != nil {
+				key := ref.Namespace + "/" + ref.Name
+				planSubscriptions[key] = append(planSubscriptions[key], sub.Name)
+			}
+
+			var failing []string
+			for _, cond := range sub.Status.Conditions {
+				if cond.Status == corev1.ConditionTrue && failingSubscriptionConditions[cond.Type] {
+					failing = append(failing, fmt.Sprintf("%s (%s)", cond.Type, cond.Reason))
+				}
+			}
+			if len(failing) > 0 {
+				summary.Subscriptions.Failing = append(summary.Subscriptions.Failing, failingSubscription{
+					Namespace:  sub.Namespace,
+					Name:       sub.Name,
+					Package:    sub.Spec.Package,
+					Conditions: failing,
+				})
+			}
+		}
+	}
+
+	if inventory.catalogs != nil {
+		for i := range inventory.catalogs.Items {
+			catalog := &inventory.catalogs.Items[i]
+			summary.CatalogSources.Total++
+			state := catalogConnectionState(catalog)
+			if state == "" {
+				state = "UNKNOWN"
+			}
+			summary.CatalogSources.ByState[state]++
+			if state != "READY" {
+				summary.CatalogSources.Unhealthy = append(summary.CatalogSources.Unhealthy, fmt.Sprintf("%s/%s (%s)", catalog.Namespace, catalog.Name, state))
+			}
+		}
+	}
+
+	if inventory.installPlans != nil {
+		for i := range inventory.installPlans.Items {
+			installPlan := &inventory.installPlans.Items[i]
+			summary.InstallPlans.Total++
+			phase := string(installPlan.Status.Phase)
+			if phase == "" {
+				phase = "<none>"
+			}
+			summary.InstallPlans.ByPhase[phase]++
+			if installPlan.Status.Phase == v1alpha1.InstallPlanPhaseRequiresApproval {
+				summary.PendingApprovals = append(summary.PendingApprovals, pendingApproval{
+					Namespace:     installPlan.Namespace,
+					InstallPlan:   installPlan.Name,
+					CSVs:          installPlan.Spec.ClusterServiceVersionNames,
+					Subscriptions: planSubscriptions[installPlan.Namespace+"/"+installPlan.Name],
+				})
+			}
+		}
+	}
+
+	sort.Strings(summary.CSVs.Failed)
+	sort.Strings(summary.CatalogSources.Unhealthy)
+	sort.Slice(summary.Subscriptions.Failing, func(i, j int) bool {
+		a, b := summary.Subscriptions.Failing[i], summary.Subscriptions.Failing[j]
+		return a.Namespace+"/"+a.Name < b.Namespace+"/"+b.Name
+	})
+	sort.Slice(summary.PendingApprovals, func(i, j int) bool {
+		a, b := summary.PendingApprovals[i], summary.PendingApprovals[j]
+		return a.Namespace+"/"+a.InstallPlan < b.Namespace+"/"+b.InstallPlan
+	})
+	return summary
+}
+
+// formatCounts renders a count map as "A=1, B=2" sorted by key.
+func formatCounts(counts map[string]int) string {
+	if len(counts) == 0 {
+		return "none"
+	}
+	keys := make([]string, 0, len(counts))
+	for key := range counts {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	parts := make([]string, 0, len(keys))
+	for _, key := range keys {
+		parts = append(parts, fmt.Sprintf("%s=%d", key, counts[key]))
+	}
+	return strings.Join(parts, ", ")
+}
+
+// SummarizeOLM returns a cluster-wide inventory of OLM resources and their
+// health.
+func (t *DiagnosticTools) SummarizeOLM(ctx context.Context, params map[string]string) (*types.MCPToolResult, error) {
+	// An inventory is cluster-wide unless a namespace is given.
+	namespace := params["namespace"]
+
+	inventory := listOLMInventory(ctx, t.server.OLMClient, namespace)
+	if len(inventory.errors) == 4 {
+		return &types.MCPToolResult{
+			Content: []types.MCPContent{{
+				Type: "text",
+				Text: fmt.Sprintf("Error listing OLM resources: %s", strings.Join(inventory.errors, "; ")),
+			}},
+			IsError: true,
+		}, nil
+	}
+	summary := summarizeInventory(namespace, inventory)
+
+	var result strings.Builder
+	scope := "all namespaces"
+	if namespace != "" {
+		scope = fmt.Sprintf("namespace '%s'", namespace)
+	}
+	result.WriteString(fmt.Sprintf("OLM summary for %s:\n\n", scope))
+
+	result.WriteString(fmt.Sprintf("ClusterServiceVersions: %d (%d copied CSVs ignored)\n", summary.CSVs.Total, summary.CSVs.Copied))
+	result.WriteString(fmt.Sprintf("  By phase: %s\n", formatCounts(summary.CSVs.ByPhase)))
+	for _, csv := range summary.CSVs.Failed {
+		result.WriteString(fmt.Sprintf("  Failed: %s\n", csv))
+	}
+
+	result.WriteString(fmt.Sprintf("\nSubscriptions: %d (%d failing)\n", summary.Subscriptions.Total, len(summary.Subscriptions.Failing)))
+	for _, sub := range summary.Subscriptions.Failing {
+		result.WriteString(fmt.Sprintf("  Failing: %s/%s (package %s): %s\n", sub.Namespace, sub.Name, sub.Package, strings.Join(sub.Conditions, ", ")))
+	}
+
+	result.WriteString(fmt.Sprintf("\nCatalogSources: %d\n", summary.CatalogSources.Total))
+	result.WriteString(fmt.Sprintf("  By connection state: %s\n", formatCounts(summary.CatalogSources.ByState)))
+	for _, catalog := range summary.CatalogSources.Unhealthy {
+		result.WriteString(fmt.Sprintf("  Not ready: %s\n", catalog))
+	}
+
+	result.WriteString(fmt.Sprintf("\nInstallPlans: %d\n", summary.InstallPlans.Total))
+	result.WriteString(fmt.Sprintf("  By phase: %s\n", formatCounts(summary.InstallPlans.ByPhase)))
+
+	result.WriteString(fmt.Sprintf("\nPending approvals: %d\n", len(summary.PendingApprovals)))
+	for _, approval := range summary.PendingApprovals {
+		result.WriteString(fmt.Sprintf("  %s/%s: %s (Subscriptions: %s)\n",
+			approval.Namespace, approval.InstallPlan, strings.Join(approval.CSVs, ", "), valueOrNone(strings.Join(approval.Subscriptions, ", "))))
+	}
+
+	if len(summary.Errors) > 0 {
+		result.WriteString("\nIncomplete results:\n")
+		for _, msg := range summary.Errors {
+			result.WriteString(fmt.Sprintf("  - %s\n", msg))
+		}
+	}
+
+	jsonData, err := json.MarshalIndent(summary, "", "  ")
+	if err != nil {
+		return &types.MCPToolResult{
+			Content: []types.MCPContent{{
+				Type: "text",
+				Text: fmt.Sprintf("Error marshaling summary: %v", err),
+			}},
+			IsError: true,
+		}, nil
+	}
+
+	result.WriteString("\nStructured summary:\n")
+	result.WriteString("```json\n")
+	result.WriteString(string(jsonData))
+	result.WriteString("\n```\n")
+
+	return &types.MCPToolResult{
+		Content: []types.MCPContent{{
+			Type: "text",
+			Text: result.String(),
+		}},
+	}, nil
+}
@@ -0,0 +1,440 @@
+package tools
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/operator-framework/api/pkg/operators/v1alpha1"
+	"github.com/operator-framework/operator-lifecycle-manager/olmv0-mcp-server/pkg/types"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// timelineEntry is one dated fact about an install.
+type timelineEntry struct {
+	Time   time.Time
+	Object string
+	Event  string
+}
+
+// installTimeline collects dated facts about a Subscription, its InstallPlan
+// and its CSV, along with the milestones used to split the install into
+// stages.
+type installTimeline struct {
+	entries []timelineEntry
+
+	subscription *v1alpha1.Subscription
+	installPlan  *v1alpha1.InstallPlan
+	csv          *v1alpha1.ClusterServiceVersion
+}
+
+func (tl *installTimeline) add(t *metav1.Time, object, event string) {
+	if t == nil || t.IsZero() {
+		return
+	}
+	tl.entries = append(tl.entries, timelineEntry{Time: t.Time, Object: object, Event: event})
+}
+
+func (tl *installTimeline) addEvents(ctx context.Context, server *types.MCPServer, kind, namespace, name, object string) {
+	if server.K8sClient == nil {
+		return
+	}
+	events, err := listObjectEvents(ctx, server.K8sClient, kind, namespace, name)
+	if err != nil {
+		return
+	}
+	for _, e := range events {
+		event := fmt.Sprintf("event %s: %s", e.Reason, e.Message)
+		if e.Count > 1 {
+			event += fmt.Sprintf(" (x%d, last at %s)", e.Count, e.Last.UTC().Format(time.RFC3339))
+		}
+		first := metav1.NewTime(e.First)
+		tl.add(&first, object, event)
+	}
+}
+
+func conditionText(conditionType string, status corev1.ConditionStatus, reason, message string) string {
+	text := fmt.Sprintf("condition %s=%s", conditionType, status)
+	if reason != "" {
+		text += fmt.Sprintf(" (%s)", reason)
+	}
+	if message != "" {
+		text += ": " + message
+	}
+	return text
+}
+
+func (tl *installTimeline) addSubscription(sub *v1alpha1.Subscription) {
+	object := "Subscription " + sub.Name
+	tl.add(&sub.CreationTimestamp, object, "created")
+	for _, cond := range sub.Status.Conditions {
+		tl.add(cond.LastTransitionTime, object, conditionText(string(cond.Type), cond.Status, cond.Reason, cond.Message))
+	}
+}
+
+func (tl *installTimeline) addInstallPlan(plan *v1alpha1.InstallPlan) {
+	object := "InstallPlan " + plan.Name
+	tl.add(&plan.CreationTimestamp, object, fmt.Sprintf("created for %s (approval %s)", strings.Join(plan.Spec.ClusterServiceVersionNames, ", "), plan.Spec.Approval))
+	for _, cond := range plan.Status.Conditions {
+		tl.add(cond.LastTransitionTime, object, conditionText(string(cond.Type), cond.Status, string(cond.Reason), cond.Message))
+	}
+	for _, lookup := range plan.Status.BundleLookups {
+		for _, cond := range lookup.Conditions {
+			tl.add(cond.LastTransitionTime, object, "bundle "+lookup.Path+" "+conditionText(string(cond.Type), cond.Status, cond.Reason, cond.Message))
+		}
+	}
+	tl.add(plan.Status.StartTime, object, "started applying steps")
+}
+
+func (tl *installTimeline) addCSV(csv *v1alpha1.ClusterServiceVersion) {
+	object := "CSV " + csv.Name
+	tl.add(&csv.CreationTimestamp, object, "created")
+	for _, cond := range csv.Status.Conditions {
+		event := fmt.Sprintf("phase %s", phaseOrNone(cond.Phase))
+		if cond.Reason != "" {
+			event += fmt.Sprintf(" (%s)", cond.Reason)
+		}
+		if cond.Message != "" {
+			event += ": " + cond.Message
+		}
+		tl.add(cond.LastTransitionTime, object, event)
+	}
+}
+
+// timelineStage is a span of the install between two milestones. Stages
+// without an end are still in progress.
+type timelineStage struct {
+	Name  string
+	Start time.Time
+	End   time.Time
+	Open  bool
+}
+
+func (s timelineStage) duration(now time.Time) time.Duration {
+	if s.Open {
+		return now.Sub(s.Start)
+	}
+	return s.End.Sub(s.Start)
+}
+
+func timeOf(t *metav1.Time) time.Time {
+	if t == nil {
+		return time.Time{}
+	}
+	return t.Time
+}
+
+// stages splits the install at its milestones: InstallPlan creation, step
+// resolution (which includes bundle unpacking), the start of step execution,
+// and the CSV reaching Succeeded. Stages whose milestones are missing are
+// left out.
+func (tl *installTimeline) stages() []timelineStage {
+	var stages []timelineStage
+	span := func(name string, start, end time.Time, inProgress bool) {
+		switch {
+		case start.IsZero():
+		case end.IsZero() && inProgress:
+			stages = append(stages, timelineStage{Name: name, Start: start, Open: true})
+		case !end.IsZero() && !end.Before(start):
+			stages = append(stages, timelineStage{Name: name, Start: start, End: end})
+		}
+	}
+
+	plan := tl.installPlan
+	if plan == nil {
+		if tl.subscription != nil && tl.csv == nil {
+			span("Resolving the Subscription (no InstallPlan yet)", tl.subscription.CreationTimestamp.Time, time.Time{}, true)
+		}
+	} else {
+		created := plan.CreationTimestamp.Time
+		// Upgrades are triggered by catalog updates rather than by the
+		// Subscription, so resolution is only timed for the first plan.
+		if tl.subscription != nil && plan.Spec.Generation <= 1 {
+			span("Resolving the Subscription into an InstallPlan", tl.subscription.CreationTimestamp.Time, created, false)
+		}
+
+		var resolved time.Time
+		for _, cond := range plan.Status.Conditions {
+			if cond.Type == v1alpha1.InstallPlanResolved && cond.Status == corev1.ConditionTrue {
+				resolved = timeOf(cond.LastTransitionTime)
+			}
+		}
+		started := timeOf(plan.Status.StartTime)
+		if resolved.IsZero() {
+			resolved = started
+		}
+		span("Unpacking bundles and resolving InstallPlan steps", created, resolved, plan.Status.Phase == v1alpha1.InstallPlanPhasePlanning || plan.Status.Phase == v1alpha1.InstallPlanPhaseNone)
+
+		waitName := "Waiting to apply the InstallPlan"
+		if plan.Spec.Approval == v1alpha1.ApprovalManual {
+			waitName = "Waiting for manual approval"
+		}
+		span(waitName, resolved, started, plan.Status.Phase == v1alpha1.InstallPlanPhaseRequiresApproval)
+
+		var installed time.Time
+		for _, cond := range plan.Status.Conditions {
+			if cond.Type == v1alpha1.InstallPlanInstalled && cond.Status == corev1.ConditionTrue {
+				installed = timeOf(cond.LastTransitionTime)
+			}
+		}
+		span("Applying InstallPlan steps", started, installed, plan.Status.Phase == v1alpha1.InstallPlanPhaseInstalling)
+	}
+
+	if csv := tl.csv; csv != nil {
+		var succeeded time.Time
+		for _, cond := range csv.Status.Conditions {
+			if cond.Phase == v1alpha1.CSVPhaseSucceeded {
+				succeeded = timeOf(cond.LastTransitionTime)
+				break
+			}
+		}
+		if succeeded.IsZero() && csv.Status.Phase == v1alpha1.CSVPhaseSucceeded {
+			succeeded = timeOf(csv.Status.LastTransitionTime)
+		}
+		span(fmt.Sprintf("Installing CSV %s (requirements and deployments)", csv.Name), csv.CreationTimestamp.Time, succeeded, csv.Status.Phase != v1alpha1.CSVPhaseFailed)
+	}
+	return stages
+}
+
+// formatDuration renders a duration rounded to the second, or to the
+// millisecond for sub-second spans.
+func formatDuration(d time.Duration) string {
+	if d < time.Second {
+		return d.Round(time.Millisecond).String()
+	}
+	return d.Round(time.Second).String()
+}
+
+// newest returns the time of the latest entry.
+func (tl *installTimeline) newest() time.Time {
+	var newest time.Time
+	for _, e := range tl.entries {
+		if e.Time.After(newest) {
+			newest = e.Time
+		}
+	}
+	return newest
+}
+
+func (tl *installTimeline) write(result *strings.Builder, now time.Time) {
+	sort.SliceStable(tl.entries, func(i, j int) bool {
+		return tl.entries[i].Time.Before(tl.entries[j].Time)
+	})
+
+	result.WriteString("Timeline (oldest first):\n")
+	if len(tl.entries) == 0 {
+		result.WriteString("  No timestamps found.\n")
+		return
+	}
+	start := tl.entries[0].Time
+	result.WriteString("TIME\tOFFSET\tOBJECT\tEVENT\n")
+	for _, e := range tl.entries {
+		result.WriteString(fmt.Sprintf("%s\t+%s\t%s\t%s\n",
+			e.Time.UTC().Format(time.RFC3339),
+			formatDuration(e.Time.Sub(start)),
+			e.Object,
+			e.Event,
+		))
+	}
+
+	result.WriteString("\nWhere time was spent:\n")
+	stages := tl.stages()
+	if len(stages) == 0 {
+		result.WriteString("  Not enough milestones to split the install into stages.\n")
+	}
+	longest := -1
+	for i, stage := range stages {
+		if longest < 0 || stage.duration(now) > stages[longest].duration(now) {
+			longest = i
+		}
+	}
+	for i, stage := range stages {
+		line := fmt.Sprintf("  %s: %s", stage.Name, formatDuration(stage.duration(now)))
+		if stage.Open {
+			line += " so far (still in progress)"
+		}
+		if i == longest && len(stages) > 1 {
+			line += "  <- longest"
+		}
+		result.WriteString(line + "\n")
+	}
+
+	// The largest gap between two facts is often the real answer to "why
+	// did this take so long", even when it falls inside one stage.
+	var gapStart, gapEnd timelineEntry
+	for i := 1; i < len(tl.entries); i++ {
+		if tl.entries[i].Time.Sub(tl.entries[i-1].Time) > gapEnd.Time.Sub(gapStart.Time) {
+			gapStart, gapEnd = tl.entries[i-1], tl.entries[i]
+		}
+	}
+	if gap := gapEnd.Time.Sub(gapStart.Time); gap > 0 {
+		result.WriteString(fmt.Sprintf("\nLongest quiet period: %s between \"%s: %s\" and \"%s: %s\"\n",
+			formatDuration(gap), gapStart.Object, gapStart.Event, gapEnd.Object, gapEnd.Event))
+	}
+	end := tl.entries[len(tl.entries)-1].Time
+	result.WriteString(fmt.Sprintf("Total span: %s\n", formatDuration(end.Sub(start))))
+}
+
+// GetInstallTimeline merges the timestamps of a Subscription, its InstallPlan
+// and its CSV into one chronological timeline and reports which stage of the
+// install took longest.
+func (t *DiagnosticTools) GetInstallTimeline(ctx context.Context, params map[string]string) (*types.MCPToolResult, error) {
+	kind := strings.ToLower(params["kind"])
+	namespace := params["namespace"]
+	name := params["name"]
+
+	if namespace == "" {
+		namespace = "default"
+	}
+	if kind == "" {
+		kind = "subscription"
+	}
+	if name == "" {
+		return &types.MCPToolResult{
+			Content: []types.MCPContent{{
+				Type: "text",
+				Text: "Error: 'name' parameter is required",
+			}},
+			IsError: true,
+		}, nil
+	}
+	if kind != "subscription" && kind != "csv" && kind != "clusterserviceversion" {
+		return &types.MCPToolResult{
+			Content: []types.MCPContent{{
+				Type: "text",
+				Text: "Error: 'kind' must be subscription or csv",
+			}},
+			IsError: true,
+		}, nil
+	}
+
+	tl := &installTimeline{}
+	var notes []string
+
+	if kind == "subscription" {
+		sub, err := t.server.OLMClient.GetSubscription(ctx, namespace, name)
+		if err != nil {
+			return &types.MCPToolResult{
+				Content: []types.MCPContent{{
+					Type: "text",
+					Text: fmt.Sprintf("Error getting Subscription '%s': %v", name, err),
+				}},
+				IsError: true,
+			}, nil
+		}
+		tl.subscription = sub
+	} else {
+		csv, err := t.server.OLMClient.GetClusterServiceVersion(ctx, namespace, name)
+		if err != nil {
+			return &types.MCPToolResult{
+				Content: []types.MCPContent{{
+					Type: "text",
+					Text: fmt.Sprintf("Error getting ClusterServiceVersion '%s': %v", name, err),
+				}},
+				IsError: true,
+			}, nil
+		}
+		tl.csv = csv
+		if subs, err := t.server.OLMClient.ListSubscriptions(ctx, namespace); err == nil {
+			for i := range subs.Items {
+				sub := &subs.Items[i]
+				if sub.Status.InstalledCSV == name || sub.Status.CurrentCSV == name {
+					tl.subscription = sub
+					break
+				}
+			}
+		}
+	}
+
+	// InstallPlan: the Subscription's reference, or for a CSV whose
+	// Subscription has moved on, the plan that installed it.
+	if sub := tl.subscription; sub != nil && sub.Status.InstallPlanRef != nil {
+		ref := sub.Status.InstallPlanRef
+		ipNamespace := ref.Namespace
+		if ipNamespace == "" {
+			ipNamespace = namespace
+		}
+		if plan, err := t.server.OLMClient.GetInstallPlan(ctx, ipNamespace, ref.Name); err == nil {
+			tl.installPlan = plan
+		} else {
+			notes = append(notes, fmt.Sprintf("InstallPlan %s/%s could not be read: %v", ipNamespace, ref.Name, err))
+		}
+	}
+	if csv := tl.csv; csv != nil && (tl.installPlan == nil || !containsString(tl.installPlan.Spec.ClusterServiceVersionNames, csv.Name)) {
+		tl.installPlan = nil
+		if plans, err := t.server.OLMClient.ListInstallPlans(ctx, namespace); err == nil {
+			for i := range plans.Items {
+				plan := &plans.Items[i]
+				if containsString(plan.Spec.ClusterServiceVersionNames, csv.Name) &&
+					(tl.installPlan == nil || plan.CreationTimestamp.After(tl.installPlan.CreationTimestamp.Time)) {
+					tl.installPlan = plan
+				}
+			}
+		}
+	}
+
+	// CSV: for a Subscription, the one being installed.
+	if sub := tl.subscription; sub != nil && tl.csv == nil {
+		csvName := sub.Status.CurrentCSV
+		if csvName == "" {
+			csvName = sub.Status.InstalledCSV
+		}
+		if csvName != "" {
+			if csv, err := t.server.OLMClient.GetClusterServiceVersion(ctx, namespace, csvName); err == nil {
+				tl.csv = csv
+			} else {
+				notes = append(notes, fmt.Sprintf("CSV %s/%s could not be read: %v", namespace, csvName, err))
+			}
+		}
+	}
+
+	var chain []string
+	if sub := tl.subscription; sub != nil {
+		tl.addSubscription(sub)
+		tl.addEvents(ctx, t.server, v1alpha1.SubscriptionKind, sub.Namespace, sub.Name, "Subscription "+sub.Name)
+		chain = append(chain, "Subscription "+sub.Name)
+	}
+	if plan := tl.installPlan; plan != nil {
+		tl.addInstallPlan(plan)
+		tl.addEvents(ctx, t.server, v1alpha1.InstallPlanKind, plan.Namespace, plan.Name, "InstallPlan "+plan.Name)
+		chain = append(chain, "InstallPlan "+plan.Name)
+	}
+	if csv := tl.csv; csv != nil {
+		tl.addCSV(csv)
+		tl.addEvents(ctx, t.server, v1alpha1.ClusterServiceVersionKind, csv.Namespace, csv.Name, "CSV "+csv.Name)
+		chain = append(chain, "CSV "+csv.Name)
+	}
+
+	// Offline state stops at the capture, so open stages are measured up to
+	// the newest timestamp rather than the wall clock.
+	now := time.Now()
+	if !t.server.CapturedAt.IsZero() && len(tl.entries) > 0 {
+		now = tl.newest()
+		for _, stage := range tl.stages() {
+			if stage.Open {
+				notes = append(notes, fmt.Sprintf("The state was captured offline; stages still in progress are measured up to the newest timestamp, %s", now.UTC().Format(time.RFC3339)))
+				break
+			}
+		}
+	}
+
+	var result strings.Builder
+	result.WriteString(fmt.Sprintf("Install timeline in namespace %s: %s\n\n", namespace, strings.Join(chain, " -> ")))
+	tl.write(&result, now)
+	if len(notes) > 0 {
+		result.WriteString("\nNotes:\n")
+		for _, note := range notes {
+			result.WriteString(fmt.Sprintf("  - %s\n", note))
+		}
+	}
+
+	return &types.MCPToolResult{
+		Content: []types.MCPContent{{
+			Type: "text",
+			Text: result.String(),
+		}},
+	}, nil
+}
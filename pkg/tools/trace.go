@@ -0,0 +1,369 @@
+package tools
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/operator-framework/api/pkg/operators/v1alpha1"
+	"github.com/operator-framework/operator-lifecycle-manager/olmv0-mcp-server/pkg/types"
+)
+
+// operatorLabelPrefix starts the label OLM puts on the objects of an
+// installed operator: operators.coreos.com/<package>.<namespace>.
+const operatorLabelPrefix = "operators.coreos.com/"
+
+// csvPackageFromLabels returns the package named by a CSV's operator label.
+func csvPackageFromLabels(csv *v1alpha1.ClusterServiceVersion) string {
+	suffix := "." + csv.Namespace
+	for key := range csv.GetLabels() {
+		if rest := strings.TrimPrefix(key, operatorLabelPrefix); rest != key && strings.HasSuffix(rest, suffix) {
+			return strings.TrimSuffix(rest, suffix)
+		}
+	}
+	return ""
+}
+
+// csvTrace is everything known about where a CSV in one namespace came
+// from.
+type csvTrace struct {
+	namespace     string
+	csvs          map[string]*v1alpha1.ClusterServiceVersion
+	subscriptions []v1alpha1.Subscription
+	installPlans  []v1alpha1.InstallPlan
+}
+
+func loadCSVTrace(ctx context.Context, server *types.MCPServer, namespace string) (*csvTrace, error) {
+	csvs, err := server.OLMClient.ListClusterServiceVersions(ctx, namespace)
+	if err != nil {
+		return nil, fmt.Errorf("error listing ClusterServiceVersions: %v", err)
+	}
+	trace := &csvTrace{namespace: namespace, csvs: map[string]*v1alpha1.ClusterServiceVersion{}}
+	for i := range csvs.Items {
+		trace.csvs[csvs.Items[i].Name] = &csvs.Items[i]
+	}
+	if subs, err := server.OLMClient.ListSubscriptions(ctx, namespace); err == nil {
+		trace.subscriptions = subs.Items
+	}
+	if plans, err := server.OLMClient.ListInstallPlans(ctx, namespace); err == nil {
+		trace.installPlans = plans.Items
+		sort.Slice(trace.installPlans, func(i, j int) bool {
+			return trace.installPlans[i].CreationTimestamp.Before(&trace.installPlans[j].CreationTimestamp)
+		})
+	}
+	return trace, nil
+}
+
+// owningSubscription finds the Subscription that installed a CSV, by its
+// installed or current CSV, or by the package in the CSV's operator label.
+func (tr *csvTrace) owningSubscription(csv *v1alpha1.ClusterServiceVersion) (*v1alpha1.Subscription, string) {
+	for i := range tr.subscriptions {
+		sub := &tr.subscriptions[i]
+		switch csv.Name {
+		case sub.Status.InstalledCSV:
+			return sub, "status.installedCSV"
+		case sub.Status.CurrentCSV:
+			return sub, "status.currentCSV"
+		}
+	}
+	if pkg := csvPackageFromLabels(csv); pkg != "" {
+		for i := range tr.subscriptions {
+			if tr.subscriptions[i].Spec.Package == pkg {
+				return &tr.subscriptions[i], fmt.Sprintf("label %s%s.%s", operatorLabelPrefix, pkg, csv.Namespace)
+			}
+		}
+	}
+	return nil, ""
+}
+
+// creatingInstallPlan returns the newest InstallPlan that installs the CSV,
+// preferring one whose CSV step was created.
+func (tr *csvTrace) creatingInstallPlan(csvName string) (*v1alpha1.InstallPlan, *v1alpha1.Step) {
+	var plan *v1alpha1.InstallPlan
+	var csvStep *v1alpha1.Step
+	for i := range tr.installPlans {
+		candidate := &tr.installPlans[i]
+		if !containsString(candidate.Spec.ClusterServiceVersionNames, csvName) {
+			continue
+		}
+		var step *v1alpha1.Step
+		for _, s := range candidate.Status.Plan {
+			if s.Resource.Kind == v1alpha1.ClusterServiceVersionKind && s.Resource.Name == csvName {
+				step = s
+			}
+		}
+		if csvStep != nil && (step == nil || step.Status != v1alpha1.StepStatusCreated) {
+			continue
+		}
+		plan, csvStep = candidate, step
+	}
+	return plan, csvStep
+}
+
+// subscriptionPlans returns the InstallPlans a Subscription produced, from
+// the InstallPlans' owner references and the Subscription's own reference.
+func (tr *csvTrace) subscriptionPlans(sub *v1alpha1.Subscription) []*v1alpha1.InstallPlan {
+	var plans []*v1alpha1.InstallPlan
+	for i := range tr.installPlans {
+		plan := &tr.installPlans[i]
+		owned := false
+		for _, owner := range plan.OwnerReferences {
+			owned = owned || (owner.Kind == v1alpha1.SubscriptionKind && owner.Name == sub.Name)
+		}
+		if ref := sub.Status.InstallPlanRef; ref != nil && ref.Name == plan.Name {
+			owned = true
+		}
+		if owned {
+			plans = append(plans, plan)
+		}
+	}
+	return plans
+}
+
+// writeReplacesChain follows spec.replaces from a CSV back through the CSVs
+// still on the cluster, and the InstallPlans that installed older ones.
+func (tr *csvTrace) writeReplacesChain(result *strings.Builder, csv *v1alpha1.ClusterServiceVersion) {
+	result.WriteString("Upgrade history (newest first):\n")
+	seen := map[string]bool{}
+	name := csv.Name
+	current := csv
+	for name != "" && !seen[name] {
+		seen[name] = true
+		line := fmt.Sprintf("  %s", name)
+		switch {
+		case current != nil:
+			line += fmt.Sprintf(" (version %s, phase %s)", csvVersion(current), phaseOrNone(current.Status.Phase))
+		default:
+			line += " (no longer on the cluster"
+			if plan, _ := tr.creatingInstallPlan(name); plan != nil {
+				line += fmt.Sprintf("; installed by InstallPlan %s", plan.Name)
+			}
+			line += ")"
+		}
+		result.WriteString(line + "\n")
+
+		if current == nil {
+			break
+		}
+		if len(current.Spec.Skips) > 0 {
+			result.WriteString(fmt.Sprintf("    skips: %s\n", strings.Join(current.Spec.Skips, ", ")))
+		}
+		if skipRange := current.GetAnnotations()[skipRangeAnnotation]; skipRange != "" {
+			result.WriteString(fmt.Sprintf("    skipRange: %s\n", skipRange))
+		}
+		name = current.Spec.Replaces
+		if name != "" {
+			result.WriteString(fmt.Sprintf("    replaces: %s\n", name))
+		}
+		current = tr.csvs[name]
+	}
+	if len(seen) == 1 && csv.Spec.Replaces == "" {
+		result.WriteString("  (first version installed; spec.replaces is empty)\n")
+	}
+	result.WriteString("\n")
+}
+
+func writePlanOrigin(result *strings.Builder, plan *v1alpha1.InstallPlan, step *v1alpha1.Step) {
+	if plan == nil {
+		result.WriteString("  InstallPlan: none found (it may have been garbage collected)\n")
+		return
+	}
+	result.WriteString(fmt.Sprintf("  InstallPlan: %s (phase %s, approval %s, created %s)\n",
+		plan.Name, plan.Status.Phase, plan.Spec.Approval, formatTime(&plan.CreationTimestamp)))
+	if step != nil {
+		result.WriteString(fmt.Sprintf("  CSV step: %s, from catalog %s/%s\n", step.Status, step.Resource.CatalogSourceNamespace, step.Resource.CatalogSource))
+	}
+}
+
+// TraceCSV links a CSV to the Subscription and InstallPlan that installed it
+// and the CSVs it replaced, or a Subscription to everything it produced.
+func (t *CSVTools) TraceCSV(ctx context.Context, params map[string]string) (*types.MCPToolResult, error) {
+	namespace := params["namespace"]
+	name := params["name"]
+	kind := strings.ToLower(params["kind"])
+
+	if namespace == "" {
+		namespace = "default"
+	}
+	if kind == "" {
+		kind = "csv"
+	}
+	if name == "" {
+		return &types.MCPToolResult{
+			Content: []types.MCPContent{{
+				Type: "text",
+				Text: "Error: 'name' parameter is required",
+			}},
+			IsError: true,
+		}, nil
+	}
+	if kind != "csv" && kind != "subscription" {
+		return &types.MCPToolResult{
+			Content: []types.MCPContent{{
+				Type: "text",
+				Text: "Error: 'kind' must be csv or subscription",
+			}},
+			IsError: true,
+		}, nil
+	}
+
+	var result strings.Builder
+	var err error
+	if kind == "csv" {
+		err = t.traceCSV(ctx, &result, namespace, name)
+	} else {
+		err = t.traceSubscription(ctx, &result, namespace, name)
+	}
+	if err != nil {
+		return &types.MCPToolResult{
+			Content: []types.MCPContent{{
+				Type: "text",
+				Text: fmt.Sprintf("Error: %v", err),
+			}},
+			IsError: true,
+		}, nil
+	}
+
+	return &types.MCPToolResult{
+		Content: []types.MCPContent{{
+			Type: "text",
+			Text: result.String(),
+		}},
+	}, nil
+}
+
+func (t *CSVTools) traceCSV(ctx context.Context, result *strings.Builder, namespace, name string) error {
+	csv, err := t.server.OLMClient.GetClusterServiceVersion(ctx, namespace, name)
+	if err != nil {
+		return fmt.Errorf("getting ClusterServiceVersion '%s': %v", name, err)
+	}
+
+	// Copied CSVs are traced in the namespace they were copied from.
+	if csv.IsCopied() {
+		origin := csv.GetLabels()[v1alpha1.CopiedLabelKey]
+		result.WriteString(fmt.Sprintf("CSV %s/%s is a copy made by OLM for the OperatorGroup's target namespaces; tracing the original in %s.\n\n", namespace, name, valueOrNone(origin)))
+		if origin == "" || origin == namespace {
+			return nil
+		}
+		return t.traceCSV(ctx, result, origin, name)
+	}
+
+	trace, err := loadCSVTrace(ctx, t.server, namespace)
+	if err != nil {
+		return err
+	}
+
+	result.WriteString(fmt.Sprintf("Trace of ClusterServiceVersion %s/%s (version %s, phase %s)\n\n", namespace, name, csvVersion(csv), phaseOrNone(csv.Status.Phase)))
+	result.WriteString("Installed by:\n")
+	sub, matchedBy := trace.owningSubscription(csv)
+	if sub == nil {
+		result.WriteString("  Subscription: none found; the CSV may have been created directly\n")
+	} else {
+		result.WriteString(fmt.Sprintf("  Subscription: %s (matched by %s)\n", sub.Name, matchedBy))
+		result.WriteString(fmt.Sprintf("  Package: %s\n", sub.Spec.Package))
+		result.WriteString(fmt.Sprintf("  Catalog: %s/%s\n", sub.Spec.CatalogSourceNamespace, sub.Spec.CatalogSource))
+		result.WriteString(fmt.Sprintf("  Channel: %s\n", valueOrNone(sub.Spec.Channel)))
+		if sub.Status.CurrentCSV != "" && sub.Status.CurrentCSV != name {
+			result.WriteString(fmt.Sprintf("  Note: the Subscription has moved on to %s\n", sub.Status.CurrentCSV))
+		}
+	}
+	plan, step := trace.creatingInstallPlan(name)
+	writePlanOrigin(result, plan, step)
+	result.WriteString("\n")
+
+	trace.writeReplacesChain(result, csv)
+
+	if replacedBy := replacingCSVs(trace, name); len(replacedBy) > 0 {
+		result.WriteString(fmt.Sprintf("Replaced by: %s\n", strings.Join(replacedBy, ", ")))
+	}
+	return nil
+}
+
+// replacingCSVs lists the CSVs whose spec.replaces names the given CSV.
+func replacingCSVs(trace *csvTrace, name string) []string {
+	var names []string
+	for _, other := range trace.csvs {
+		if other.Spec.Replaces == name {
+			names = append(names, other.Name)
+		}
+	}
+	sort.Strings(names)
+	return names
+}
+
+func (t *CSVTools) traceSubscription(ctx context.Context, result *strings.Builder, namespace, name string) error {
+	sub, err := t.server.OLMClient.GetSubscription(ctx, namespace, name)
+	if err != nil {
+		return fmt.Errorf("getting Subscription '%s': %v", name, err)
+	}
+	trace, err := loadCSVTrace(ctx, t.server, namespace)
+	if err != nil {
+		return err
+	}
+
+	result.WriteString(fmt.Sprintf("Trace of Subscription %s/%s\n\n", namespace, name))
+	result.WriteString(fmt.Sprintf("Source: package %s, channel %s, catalog %s/%s\n", sub.Spec.Package, valueOrNone(sub.Spec.Channel), sub.Spec.CatalogSourceNamespace, sub.Spec.CatalogSource))
+	result.WriteString(fmt.Sprintf("Installed CSV: %s\n", valueOrNone(sub.Status.InstalledCSV)))
+	result.WriteString(fmt.Sprintf("Current CSV: %s\n\n", valueOrNone(sub.Status.CurrentCSV)))
+
+	plans := trace.subscriptionPlans(sub)
+	result.WriteString("InstallPlans (oldest first):\n")
+	if len(plans) == 0 {
+		result.WriteString("  none found\n")
+	}
+	for _, plan := range plans {
+		result.WriteString(fmt.Sprintf("  %s: phase %s, approval %s, installs %s, created %s\n",
+			plan.Name, plan.Status.Phase, plan.Spec.Approval, strings.Join(plan.Spec.ClusterServiceVersionNames, ", "), formatTime(&plan.CreationTimestamp)))
+	}
+	result.WriteString("\n")
+
+	// Objects created by the Subscription's InstallPlans, by kind.
+	created := map[string][]string{}
+	for _, plan := range plans {
+		for _, step := range plan.Status.Plan {
+			if step.Status == v1alpha1.StepStatusCreated || step.Status == v1alpha1.StepStatusPresent {
+				created[step.Resource.Kind] = append(created[step.Resource.Kind], step.Resource.Name)
+			}
+		}
+	}
+	result.WriteString("Objects produced by its InstallPlans:\n")
+	if len(created) == 0 {
+		result.WriteString("  none recorded\n")
+	}
+	kinds := make([]string, 0, len(created))
+	for kind := range created {
+		kinds = append(kinds, kind)
+	}
+	sort.Strings(kinds)
+	for _, kind := range kinds {
+		result.WriteString(fmt.Sprintf("  %s: %s\n", kind, strings.Join(uniqueStrings(created[kind]), ", ")))
+	}
+	result.WriteString("\n")
+
+	csvName := sub.Status.InstalledCSV
+	if csvName == "" {
+		csvName = sub.Status.CurrentCSV
+	}
+	if csv := trace.csvs[csvName]; csv != nil {
+		trace.writeReplacesChain(result, csv)
+	}
+
+	// Copies of the installed CSV in the OperatorGroup's target namespaces.
+	if csvName != "" {
+		if all, err := t.server.OLMClient.ListClusterServiceVersions(ctx, ""); err == nil {
+			var copies []string
+			for i := range all.Items {
+				copied := &all.Items[i]
+				if copied.Name == csvName && copied.GetLabels()[v1alpha1.CopiedLabelKey] == namespace {
+					copies = append(copies, copied.Namespace)
+				}
+			}
+			if len(copies) > 0 {
+				sort.Strings(copies)
+				result.WriteString(fmt.Sprintf("Copied CSVs: %d, in %s\n", len(copies), strings.Join(copies, ", ")))
+			}
+		}
+	}
+	return nil
+}
@@ -0,0 +1,330 @@
+package tools
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/operator-framework/api/pkg/operators/v1alpha1"
+	"github.com/operator-framework/operator-lifecycle-manager/olmv0-mcp-server/pkg/types"
+	batchv1 "k8s.io/api/batch/v1"
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+const (
+	// bundleUnpackTimeoutAnnotation on an OperatorGroup overrides how long
+	// OLM lets an unpack Job run before failing the InstallPlan.
+	bundleUnpackTimeoutAnnotation = "operatorframework.io/bundle-unpack-timeout"
+	// bundleUnpackRetryAnnotation on an OperatorGroup makes OLM recreate
+	// failed unpack Jobs after the given interval.
+	bundleUnpackRetryAnnotation = "operatorframework.io/bundle-unpack-min-retry-interval"
+
+	defaultBundleUnpackTimeout = 10 * time.Minute
+	unpackLogTailLines         = 20
+)
+
+// unpackJobImages returns the images run by an unpack Job. OLM pulls the
+// bundle image in one of the Job's init containers.
+func unpackJobImages(job *batchv1.Job) []string {
+	var images []string
+	for _, c := range job.Spec.Template.Spec.InitContainers {
+		images = append(images, c.Image)
+	}
+	for _, c := range job.Spec.Template.Spec.Containers {
+		images = append(images, c.Image)
+	}
+	return images
+}
+
+// findUnpackJob returns the Job in the catalog namespace that unpacks the
+// bundle image at path.
+func findUnpackJob(jobs []batchv1.Job, path string) *batchv1.Job {
+	for i := range jobs {
+		if containsString(unpackJobImages(&jobs[i]), path) {
+			return &jobs[i]
+		}
+	}
+	return nil
+}
+
+// unpackConfigMapName is the ConfigMap the Job writes the bundle to: the
+// Job's owner, which OLM names after the Job.
+func unpackConfigMapName(job *batchv1.Job) string {
+	for _, owner := range job.OwnerReferences {
+		if owner.Kind == "ConfigMap" {
+			return owner.Name
+		}
+	}
+	return job.Name
+}
+
+func jobCondition(job *batchv1.Job, conditionType batchv1.JobConditionType) *batchv1.JobCondition {
+	for i := range job.Status.Conditions {
+		if job.Status.Conditions[i].Type == conditionType && job.Status.Conditions[i].Status == corev1.ConditionTrue {
+			return &job.Status.Conditions[i]
+		}
+	}
+	return nil
+}
+
+// DiagnoseBundleUnpack explains why an InstallPlan's bundles failed to unpack
+// or are still unpacking, from its bundle lookups and the unpack Jobs, pods
+// and ConfigMaps in the catalog namespace.
+func (t *DiagnosticTools) DiagnoseBundleUnpack(ctx context.Context, params map[string]string) (*types.MCPToolResult, error) {
+	namespace := params["namespace"]
+	name := params["name"]
+
+	if namespace == "" {
+		namespace = "default"
+	}
+	if name == "" {
+		return &types.MCPToolResult{
+			Content: []types.MCPContent{{
+				Type: "text",
+				Text: "Error: 'name' parameter is required",
+			}},
+			IsError: true,
+		}, nil
+	}
+
+	installPlan, err := t.server.OLMClient.GetInstallPlan(ctx, namespace, name)
+	if err != nil {
+		return &types.MCPToolResult{
+			Content: []types.MCPContent{{
+				Type: "text",
+				Text: fmt.Sprintf("Error getting InstallPlan '%s': %v", name, err),
+			}},
+			IsError: true,
+		}, nil
+	}
+
+	var result strings.Builder
+	var findings []finding
+
+	result.WriteString(fmt.Sprintf("Bundle unpacking for InstallPlan %s/%s\n\n", namespace, name))
+	result.WriteString(fmt.Sprintf("Phase: %s\n", installPlan.Status.Phase))
+	for _, cond := range installPlan.Status.Conditions {
+		if cond.Status != corev1.ConditionTrue {
+			result.WriteString(fmt.Sprintf("Condition %s=%s (%s): %s\n", cond.Type, cond.Status, cond.Reason, cond.Message))
+		}
+	}
+	if installPlan.Status.Message != "" {
+		result.WriteString(fmt.Sprintf("Message: %s\n", installPlan.Status.Message))
+	}
+
+	// The unpack timeout comes from the OperatorGroup in the InstallPlan's
+	// namespace.
+	timeout := defaultBundleUnpackTimeout
+	timeoutSource := "OLM default, unless catalog-operator runs with --bundle-unpack-timeout"
+	if groups, err := t.server.OLMClient.ListOperatorGroups(ctx, namespace); err == nil && len(groups.Items) == 1 {
+		og := groups.Items[0]
+		if value := og.GetAnnotations()[bundleUnpackTimeoutAnnotation]; value != "" {
+			parsed, err := time.ParseDuration(value)
+			if err != nil {
+				findings = append(findings, finding{
+					Severity: severityWarning,
+					Title:    fmt.Sprintf("OperatorGroup %s has an invalid %s annotation", og.Name, bundleUnpackTimeoutAnnotation),
+					Evidence: []string{fmt.Sprintf("%q is not a duration; OLM falls back to its default", value), err.Error()},
+				})
+			} else {
+				timeout = parsed
+				timeoutSource = fmt.Sprintf("%s annotation on OperatorGroup %s", bundleUnpackTimeoutAnnotation, og.Name)
+			}
+		}
+		if value := og.GetAnnotations()[bundleUnpackRetryAnnotation]; value != "" {
+			result.WriteString(fmt.Sprintf("Unpack retry interval: %s (%s annotation on OperatorGroup %s)\n", value, bundleUnpackRetryAnnotation, og.Name))
+		}
+	}
+	result.WriteString(fmt.Sprintf("Unpack timeout: %s (%s)\n\n", timeout, timeoutSource))
+
+	if len(installPlan.Status.BundleLookups) == 0 {
+		result.WriteString("Bundle lookups: none\n\n")
+		evidence := []string{"status.bundleLookups is empty; OLM removes lookups once their bundles are unpacked"}
+		severity := severityInfo
+		if installPlan.Status.Phase == v1alpha1.InstallPlanPhaseFailed {
+			severity = severityWarning
+			evidence = append(evidence, "the InstallPlan failed for another reason; see diagnose_subscription or get_install_plan")
+		}
+		findings = append(findings, finding{
+			Severity: severity,
+			Title:    "The InstallPlan has no pending or failed bundle lookups",
+			Evidence: evidence,
+		})
+	}
+
+	jobsByNamespace := map[string][]batchv1.Job{}
+	for _, lookup := range installPlan.Status.BundleLookups {
+		catalogNamespace := namespace
+		catalogName := ""
+		if ref := lookup.CatalogSourceRef; ref != nil {
+			catalogName = ref.Name
+			if ref.Namespace != "" {
+				catalogNamespace = ref.Namespace
+			}
+		}
+
+		result.WriteString(fmt.Sprintf("=== Bundle %s ===\n", lookup.Path))
+		result.WriteString(fmt.Sprintf("  Identifier: %s\n", valueOrNone(lookup.Identifier)))
+		result.WriteString(fmt.Sprintf("  Catalog: %s/%s\n", catalogNamespace, valueOrNone(catalogName)))
+		for _, cond := range lookup.Conditions {
+			result.WriteString(fmt.Sprintf("  Condition %s=%s (%s): %s, since %s\n", cond.Type, cond.Status, cond.Reason, cond.Message, formatTime(cond.LastTransitionTime)))
+		}
+
+		failed := lookup.GetCondition(v1alpha1.BundleLookupFailed)
+		if failed.Status == corev1.ConditionTrue {
+			findings = append(findings, finding{
+				Severity: severityCritical,
+				Title:    fmt.Sprintf("Bundle lookup failed for %s", lookup.Path),
+				Evidence: []string{fmt.Sprintf("BundleLookupFailed (%s): %s", failed.Reason, failed.Message)},
+			})
+		}
+
+		jobs, listed := jobsByNamespace[catalogNamespace]
+		if !listed {
+			list, err := t.server.K8sClient.BatchV1().Jobs(catalogNamespace).List(ctx, metav1.ListOptions{})
+			if err != nil {
+				result.WriteString(fmt.Sprintf("  Unpack Job: error listing Jobs: %v\n\n", err))
+				findings = append(findings, finding{
+					Severity: severityWarning,
+					Title:    fmt.Sprintf("Could not list unpack Jobs in %s", catalogNamespace),
+					Evidence: []string{err.Error()},
+				})
+				continue
+			}
+			jobs = list.Items
+			jobsByNamespace[catalogNamespace] = jobs
+		}
+
+		job := findUnpackJob(jobs, lookup.Path)
+		if job == nil {
+			result.WriteString("  Unpack Job: not found\n\n")
+			pending := lookup.GetCondition(v1alpha1.BundleLookupPending)
+			if pending.Status == corev1.ConditionTrue || failed.Status == corev1.ConditionTrue {
+				findings = append(findings, finding{
+					Severity: severityWarning,
+					Title:    fmt.Sprintf("No unpack Job found for %s", lookup.Path),
+					Evidence: []string{
+						fmt.Sprintf("no Job in %s runs the bundle image", catalogNamespace),
+						"OLM may have deleted a failed Job, or catalog-operator has not created it yet; check get_olm_logs for catalog-operator",
+					},
+				})
+			}
+			continue
+		}
+
+		deadline := "none"
+		if job.Spec.ActiveDeadlineSeconds != nil {
+			deadline = (time.Duration(*job.Spec.ActiveDeadlineSeconds) * time.Second).String()
+		}
+		result.WriteString(fmt.Sprintf("  Unpack Job: %s/%s (created %s, active %d, succeeded %d, failed %d, deadline %s)\n",
+			job.Namespace, job.Name, formatTime(&job.CreationTimestamp), job.Status.Active, job.Status.Succeeded, job.Status.Failed, deadline))
+
+		if cond := jobCondition(job, batchv1.JobFailed); cond != nil {
+			evidence := []string{fmt.Sprintf("Job condition Failed (%s): %s", cond.Reason, cond.Message)}
+			title := fmt.Sprintf("Unpack Job %s failed", job.Name)
+			if cond.Reason == "DeadlineExceeded" {
+				title = fmt.Sprintf("Unpack Job %s timed out", job.Name)
+				evidence = append(evidence,
+					fmt.Sprintf("the Job's deadline is %s (unpack timeout %s, from the %s)", deadline, timeout, timeoutSource),
+					fmt.Sprintf("slow registries or large bundles may need a longer %s annotation on the OperatorGroup", bundleUnpackTimeoutAnnotation))
+			}
+			findings = append(findings, finding{Severity: severityCritical, Title: title, Evidence: evidence})
+		} else if jobCondition(job, batchv1.JobComplete) == nil {
+			elapsed := t.server.Now().Sub(job.CreationTimestamp.Time)
+			severity := severityInfo
+			if elapsed > timeout/2 {
+				severity = severityWarning
+			}
+			title := fmt.Sprintf("Unpack Job %s is still running after %s", job.Name, formatDuration(elapsed))
+			if !t.server.CapturedAt.IsZero() {
+				title = fmt.Sprintf("Unpack Job %s had been running for %s when the state was captured", job.Name, formatDuration(elapsed))
+			}
+			findings = append(findings, finding{
+				Severity: severity,
+				Title:    title,
+				Evidence: []string{fmt.Sprintf("it fails when it reaches the %s unpack timeout", timeout)},
+			})
+		}
+
+		// Pods: image pull errors and the log tail of failed containers.
+		selector, err := metav1.LabelSelectorAsSelector(job.Spec.Selector)
+		if err == nil {
+			pods, err := t.server.K8sClient.CoreV1().Pods(job.Namespace).List(ctx, metav1.ListOptions{LabelSelector: selector.String()})
+			if err == nil {
+				for _, pod := range pods.Items {
+					result.WriteString(fmt.Sprintf("  Pod: %s (%s%s)\n", pod.Name, pod.Status.Phase, podStateSuffix(pod)))
+					findings = append(findings, podFindings(pod)...)
+					t.writeUnpackLogs(ctx, &result, &pod)
+				}
+				if len(pods.Items) == 0 {
+					result.WriteString("  Pods: none\n")
+				}
+			}
+		}
+
+		// ConfigMap the bundle is unpacked into.
+		cmName := unpackConfigMapName(job)
+		cm, err := t.server.K8sClient.CoreV1().ConfigMaps(job.Namespace).Get(ctx, cmName, metav1.GetOptions{})
+		switch {
+		case apierrors.IsNotFound(err):
+			result.WriteString(fmt.Sprintf("  ConfigMap: %s/%s (not found)\n", job.Namespace, cmName))
+			findings = append(findings, finding{
+				Severity: severityWarning,
+				Title:    fmt.Sprintf("Bundle ConfigMap %s does not exist", cmName),
+				Evidence: []string{"OLM creates the ConfigMap before the unpack Job and deletes both when it gives up"},
+			})
+		case err != nil:
+			result.WriteString(fmt.Sprintf("  ConfigMap: %s/%s (unreadable: %v)\n", job.Namespace, cmName, err))
+		default:
+			result.WriteString(fmt.Sprintf("  ConfigMap: %s/%s (%d data keys, %d binary data keys)\n", cm.Namespace, cm.Name, len(cm.Data), len(cm.BinaryData)))
+			if jobCondition(job, batchv1.JobComplete) != nil && len(cm.Data)+len(cm.BinaryData) == 0 {
+				findings = append(findings, finding{
+					Severity: severityCritical,
+					Title:    fmt.Sprintf("Unpack Job %s completed but ConfigMap %s is empty", job.Name, cmName),
+					Evidence: []string{"the bundle image may not contain a manifests/ directory"},
+				})
+			}
+		}
+		result.WriteString("\n")
+	}
+
+	writeFindings(&result, findings)
+
+	return &types.MCPToolResult{
+		Content: []types.MCPContent{{
+			Type: "text",
+			Text: result.String(),
+		}},
+	}, nil
+}
+
+// writeUnpackLogs writes the log tail of every container of an unpack pod
+// that failed or is still running.
+func (t *DiagnosticTools) writeUnpackLogs(ctx context.Context, result *strings.Builder, pod *corev1.Pod) {
+	statuses := append(append([]corev1.ContainerStatus{}, pod.Status.InitContainerStatuses...), pod.Status.ContainerStatuses...)
+	for _, cs := range statuses {
+		terminated := cs.State.Terminated
+		if terminated != nil && terminated.ExitCode == 0 {
+			continue
+		}
+		if cs.State.Waiting != nil && cs.RestartCount == 0 {
+			// Never started, e.g. waiting on an image pull.
+			continue
+		}
+		// A container waiting to restart has its logs on the previous run.
+		tail := int64(unpackLogTailLines)
+		options := &corev1.PodLogOptions{Container: cs.Name, TailLines: &tail, Previous: cs.State.Waiting != nil}
+		lines, _, err := readLogLines(ctx, t.server.K8sClient, pod.Namespace, pod.Name, options, logFilter{}, unpackLogTailLines)
+		if err != nil {
+			result.WriteString(fmt.Sprintf("    Logs of %s: unavailable (%v)\n", cs.Name, err))
+			continue
+		}
+		result.WriteString(fmt.Sprintf("    Last %d log lines of %s:\n", len(lines), cs.Name))
+		for _, line := range lines {
+			result.WriteString(fmt.Sprintf("      %s\n", line))
+		}
+	}
+}
@@ -0,0 +1,368 @@
+package tools
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/blang/semver/v4"
+	"github.com/operator-framework/api/pkg/operators/v1alpha1"
+	"github.com/operator-framework/operator-lifecycle-manager/olmv0-mcp-server/pkg/types"
+)
+
+// upgradeChanges summarizes what moving from one CSV to another changes.
+type upgradeChanges struct {
+	NewCRDs        []string
+	UpdatedCRDs    []string
+	NewPermissions []string
+	Notes          []string
+}
+
+// crdChanges compares the CRDs owned before and after an upgrade. CRDs that
+// an InstallPlan has a step for are treated as updated even if the owned
+// version did not change, since OLM will replace their schema.
+func crdChanges(from, to []v1alpha1.CRDDescription, planCRDs map[string]bool) (added, updated []string) {
+	fromVersions := map[string]string{}
+	for _, crd := range from {
+		fromVersions[crd.Name] = crd.Version
+	}
+
+	seen := map[string]bool{}
+	for _, crd := range to {
+		if seen[crd.Name] {
+			continue
+		}
+		seen[crd.Name] = true
+		oldVersion, owned := fromVersions[crd.Name]
+		switch {
+		case !owned:
+			added = append(added, fmt.Sprintf("%s (%s)", crd.Name, crd.Version))
+		case oldVersion != crd.Version:
+			updated = append(updated, fmt.Sprintf("%s (%s -> %s)", crd.Name, oldVersion, crd.Version))
+		case planCRDs[crd.Name]:
+			updated = append(updated, fmt.Sprintf("%s (%s, schema replaced)", crd.Name, crd.Version))
+		}
+	}
+	for name := range planCRDs {
+		if seen[name] {
+			continue
+		}
+		if _, owned := fromVersions[name]; owned {
+			updated = append(updated, name)
+		} else {
+			added = append(added, name)
+		}
+	}
+	sort.Strings(added)
+	sort.Strings(updated)
+	return added, updated
+}
+
+// planChanges describes an upgrade from an installed CSV to a CSV that an
+// InstallPlan will create. from may be nil for fresh installs.
+func planChanges(ctx context.Context, resolver *manifestResolver, installPlan *v1alpha1.InstallPlan, from *v1alpha1.ClusterServiceVersion, toName string) upgradeChanges {
+	var changes upgradeChanges
+
+	planCRDs := map[string]bool{}
+	for _, step := range installPlan.Status.Plan {
+		if step != nil && step.Resolving == toName && step.Resource.Kind == "CustomResourceDefinition" {
+			planCRDs[step.Resource.Name] = true
+		}
+	}
+
+	var fromOwned []v1alpha1.CRDDescription
+	fromPermissions := map[string]bool{}
+	if from != nil {
+		fromOwned = from.Spec.CustomResourceDefinitions.Owned
+		fromPermissions = permissionSet(from.Spec.InstallStrategy.StrategySpec)
+	}
+
+	to, err := resolver.resolvePlanCSV(ctx, installPlan, toName)
+	if err != nil {
+		changes.NewCRDs, changes.UpdatedCRDs = crdChanges(fromOwned, nil, planCRDs)
+		changes.Notes = append(changes.Notes, fmt.Sprintf("permissions unknown: %v", err))
+		return changes
+	}
+
+	changes.NewCRDs, changes.UpdatedCRDs = crdChanges(fromOwned, to.Spec.CustomResourceDefinitions.Owned, planCRDs)
+	changes.NewPermissions = setDifference(permissionSet(to.Spec.InstallStrategy.StrategySpec), fromPermissions)
+	return changes
+}
+
+// writeUpgradeChanges renders upgrade changes as an indented block.
+func writeUpgradeChanges(result *strings.Builder, changes upgradeChanges) {
+	writeList := func(label string, items []string) {
+		if len(items) == 0 {
+			result.WriteString(fmt.Sprintf("    %s: none\n", label))
+			return
+		}
+		result.WriteString(fmt.Sprintf("    %s:\n", label))
+		for _, item := range items {
+			result.WriteString(fmt.Sprintf("      - %s\n", item))
+		}
+	}
+
+	result.WriteString("  Changes:\n")
+	writeList("New CRDs", changes.NewCRDs)
+	writeList("Updated CRDs", changes.UpdatedCRDs)
+	if changes.NewPermissions != nil || len(changes.Notes) == 0 {
+		writeList("New permissions", changes.NewPermissions)
+	}
+	for _, note := range changes.Notes {
+		result.WriteString(fmt.Sprintf("    Note: %s\n", note))
+	}
+}
+
+// csvVersion returns the CSV's spec.version, or "unknown" if it is unset.
+func csvVersion(csv *v1alpha1.ClusterServiceVersion) string {
+	if csv == nil || csv.Spec.Version.Version.Equals(semver.Version{}) {
+		return "unknown"
+	}
+	return csv.Spec.Version.String()
+}
+
+// upgradeReport gathers the state list_pending_upgrades needs, caching CSVs
+// and package manifests since several Subscriptions share them.
+type upgradeReport struct {
+	server   *types.MCPServer
+	resolver *manifestResolver
+	csvs     map[string]*v1alpha1.ClusterServiceVersion
+	packages map[string]*types.PackageManifestList
+}
+
+func (r *upgradeReport) csv(ctx context.Context, namespace, name string) *v1alpha1.ClusterServiceVersion {
+	if name == "" {
+		return nil
+	}
+	key := namespace + "/" + name
+	if csv, ok := r.csvs[key]; ok {
+		return csv
+	}
+	csv, err := r.server.OLMClient.GetClusterServiceVersion(ctx, namespace, name)
+	if err != nil {
+		csv = nil
+	}
+	r.csvs[key] = csv
+	return csv
+}
+
+func (r *upgradeReport) packageManifest(ctx context.Context, subscription *v1alpha1.Subscription) (*types.PackageManifest, error) {
+	packages, ok := r.packages[subscription.Namespace]
+	if !ok {
+		var err error
+		packages, err = r.server.OLMClient.ListPackageManifests(ctx, subscription.Namespace)
+		if err != nil {
+			return nil, err
+		}
+		r.packages[subscription.Namespace] = packages
+	}
+	return matchPackageManifest(packages, subscription)
+}
+
+// ListPendingUpgrades reports upgrades that are in progress, waiting for
+// approval, or available in the catalog but not yet resolved.
+func (t *SubscriptionTools) ListPendingUpgrades(ctx context.Context, params map[string]string) (*types.MCPToolResult, error) {
+	// Unlike the list tools this defaults to every namespace, since the
+	// point is a single cluster-wide view.
+	namespace := params["namespace"]
+
+	subscriptions, err := t.server.OLMClient.ListSubscriptions(ctx, namespace)
+	if err != nil {
+		return &types.MCPToolResult{
+			Content: []types.MCPContent{{
+				Type: "text",
+				Text: fmt.Sprintf("Error listing Subscriptions: %v", err),
+			}},
+			IsError: true,
+		}, nil
+	}
+	installPlans, err := t.server.OLMClient.ListInstallPlans(ctx, namespace)
+	if err != nil {
+		return &types.MCPToolResult{
+			Content: []types.MCPContent{{
+				Type: "text",
+				Text: fmt.Sprintf("Error listing InstallPlans: %v", err),
+			}},
+			IsError: true,
+		}, nil
+	}
+
+	report := &upgradeReport{
+		server:   t.server,
+		resolver: newManifestResolver(t.server),
+		csvs:     map[string]*v1alpha1.ClusterServiceVersion{},
+		packages: map[string]*types.PackageManifestList{},
+	}
+
+	plans := map[string]*v1alpha1.InstallPlan{}
+	for i := range installPlans.Items {
+		installPlan := &installPlans.Items[i]
+		plans[installPlan.Namespace+"/"+installPlan.Name] = installPlan
+	}
+
+	subs := subscriptions.Items
+	sort.Slice(subs, func(i, j int) bool {
+		if subs[i].Namespace != subs[j].Namespace {
+			return subs[i].Namespace < subs[j].Namespace
+		}
+		return subs[i].Name < subs[j].Name
+	})
+
+	var result strings.Builder
+	scope := "all namespaces"
+	if namespace != "" {
+		scope = fmt.Sprintf("namespace '%s'", namespace)
+	}
+	result.WriteString(fmt.Sprintf("Pending operator upgrades in %s:\n\n", scope))
+
+	// Upgrades OLM has resolved but not finished installing. Their changes
+	// are described here so the approval section does not repeat them.
+	described := map[string]bool{}
+	result.WriteString("Upgrades in progress (Subscription currentCSV differs from installedCSV):\n")
+	inProgress := 0
+	for i := range subs {
+		sub := &subs[i]
+		if sub.Status.CurrentCSV == "" || sub.Status.CurrentCSV == sub.Status.InstalledCSV {
+			continue
+		}
+		inProgress++
+
+		// An empty installedCSV is a first install that has not finished.
+		from := report.csv(ctx, sub.Namespace, sub.Status.InstalledCSV)
+		result.WriteString(fmt.Sprintf("\n%s/%s (package %s, channel %s)\n", sub.Namespace, sub.Name, sub.Spec.Package, sub.Spec.Channel))
+		if sub.Status.InstalledCSV == "" {
+			result.WriteString("  From: <none>\n")
+		} else {
+			result.WriteString(fmt.Sprintf("  From: %s (%s)\n", sub.Status.InstalledCSV, csvVersion(from)))
+		}
+
+		var installPlan *v1alpha1.InstallPlan
+		if ref := sub.Status.InstallPlanRef; ref != nil {
+			planNamespace := ref.Namespace
+			if planNamespace == "" {
+				planNamespace = sub.Namespace
+			}
+			installPlan = plans[planNamespace+"/"+ref.Name]
+		}
+		if installPlan == nil {
+			result.WriteString(fmt.Sprintf("  To:   %s (version unknown)\n", sub.Status.CurrentCSV))
+			result.WriteString("  InstallPlan: none found\n")
+			continue
+		}
+
+		to, _ := report.resolver.resolvePlanCSV(ctx, installPlan, sub.Status.CurrentCSV)
+		result.WriteString(fmt.Sprintf("  To:   %s (%s)\n", sub.Status.CurrentCSV, csvVersion(to)))
+		result.WriteString(fmt.Sprintf("  InstallPlan: %s (phase %s, approval %s, approved %t)\n",
+			installPlan.Name, installPlan.Status.Phase, installPlan.Spec.Approval, installPlan.Spec.Approved))
+		writeUpgradeChanges(&result, planChanges(ctx, report.resolver, installPlan, from, sub.Status.CurrentCSV))
+		described[installPlan.Namespace+"/"+installPlan.Name+"/"+sub.Status.CurrentCSV] = true
+	}
+	if inProgress == 0 {
+		result.WriteString("None.\n")
+	}
+
+	result.WriteString("\nInstallPlans awaiting manual approval:\n")
+	awaiting := 0
+	for i := range installPlans.Items {
+		installPlan := &installPlans.Items[i]
+		if installPlan.Status.Phase != v1alpha1.InstallPlanPhaseRequiresApproval {
+			continue
+		}
+		awaiting++
+
+		result.WriteString(fmt.Sprintf("\n%s/%s (created %s)\n", installPlan.Namespace, installPlan.Name, formatTime(&installPlan.CreationTimestamp)))
+		result.WriteString(fmt.Sprintf("  Approve with: approve_install_plan name=%s namespace=%s\n", installPlan.Name, installPlan.Namespace))
+		for _, csvName := range installPlan.Spec.ClusterServiceVersionNames {
+			key := installPlan.Namespace + "/" + installPlan.Name + "/" + csvName
+			if described[key] {
+				result.WriteString(fmt.Sprintf("  %s: described above\n", csvName))
+				continue
+			}
+
+			to, err := report.resolver.resolvePlanCSV(ctx, installPlan, csvName)
+			var from *v1alpha1.ClusterServiceVersion
+			if err == nil && to.Spec.Replaces != "" {
+				from = report.csv(ctx, installPlan.Namespace, to.Spec.Replaces)
+			}
+			if from != nil {
+				result.WriteString(fmt.Sprintf("  %s: %s (%s) -> %s (%s)\n", csvName, from.Name, csvVersion(from), csvName, csvVersion(to)))
+			} else {
+				result.WriteString(fmt.Sprintf("  %s: new install (%s)\n", csvName, csvVersion(to)))
+			}
+			writeUpgradeChanges(&result, planChanges(ctx, report.resolver, installPlan, from, csvName))
+		}
+	}
+	if awaiting == 0 {
+		result.WriteString("None.\n")
+	}
+
+	// Newer CSVs the catalog serves on the subscribed channel that OLM has
+	// not resolved yet, e.g. because an earlier upgrade is still pending.
+	result.WriteString("\nNewer versions available in the catalog:\n")
+	available := 0
+	var catalogErrors []string
+	for i := range subs {
+		sub := &subs[i]
+		if sub.Status.InstalledCSV == "" {
+			continue
+		}
+		pkg, err := report.packageManifest(ctx, sub)
+		if err != nil {
+			catalogErrors = append(catalogErrors, fmt.Sprintf("%s/%s: %v", sub.Namespace, sub.Name, err))
+			continue
+		}
+		channelName := sub.Spec.Channel
+		if channelName == "" {
+			channelName = pkg.Status.DefaultChannelName
+		}
+		channel := pkg.GetChannel(channelName)
+		if channel == nil || channel.CurrentCSV == "" || channel.CurrentCSV == sub.Status.InstalledCSV || channel.CurrentCSV == sub.Status.CurrentCSV {
+			continue
+		}
+
+		from := report.csv(ctx, sub.Namespace, sub.Status.InstalledCSV)
+		headVersion := channel.CurrentCSVDesc.Version
+		if from != nil && headVersion != "" {
+			head, err := semver.ParseTolerant(headVersion)
+			if err == nil && !head.GT(from.Spec.Version.Version) {
+				continue
+			}
+		}
+		available++
+
+		result.WriteString(fmt.Sprintf("\n%s/%s (package %s, channel %s)\n", sub.Namespace, sub.Name, sub.Spec.Package, channelName))
+		result.WriteString(fmt.Sprintf("  From: %s (%s)\n", sub.Status.InstalledCSV, csvVersion(from)))
+		result.WriteString(fmt.Sprintf("  To:   %s (%s)\n", channel.CurrentCSV, valueOrNone(headVersion)))
+		if _, index := findChannelEntry(pkg, channelName, sub.Status.InstalledCSV); index > 0 {
+			result.WriteString(fmt.Sprintf("  Versions behind channel head: %d\n", index))
+		}
+
+		var fromOwned []v1alpha1.CRDDescription
+		if from != nil {
+			fromOwned = from.Spec.CustomResourceDefinitions.Owned
+		}
+		var changes upgradeChanges
+		changes.NewCRDs, changes.UpdatedCRDs = crdChanges(fromOwned, channel.CurrentCSVDesc.CustomResourceDefinitions.Owned, nil)
+		changes.Notes = append(changes.Notes, "permissions are not published in package manifests; they are known once OLM creates an InstallPlan")
+		writeUpgradeChanges(&result, changes)
+	}
+	if available == 0 {
+		result.WriteString("None.\n")
+	}
+	if len(catalogErrors) > 0 {
+		result.WriteString("\nCould not check the catalog for:\n")
+		for _, msg := range catalogErrors {
+			result.WriteString(fmt.Sprintf("  - %s\n", msg))
+		}
+	}
+
+	result.WriteString(fmt.Sprintf("\nTotal: %d in progress, %d awaiting approval, %d available in catalog\n", inProgress, awaiting, available))
+
+	return &types.MCPToolResult{
+		Content: []types.MCPContent{{
+			Type: "text",
+			Text: result.String(),
+		}},
+	}, nil
+}
@@ -0,0 +1,208 @@
+package tools
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/operator-framework/api/pkg/operators/v1alpha1"
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes"
+)
+
+// Restarts within this window are reported as warnings rather than as
+// informational history.
+const recentRestartWindow = time.Hour
+
+// Container waiting reasons that mean a pod will not become ready without
+// intervention.
+var fatalWaitingReasons = map[string]bool{
+	"ErrImagePull":               true,
+	"ImagePullBackOff":           true,
+	"InvalidImageName":           true,
+	"CrashLoopBackOff":           true,
+	"CreateContainerConfigError": true,
+	"CreateContainerError":       true,
+	"RunContainerError":          true,
+}
+
+// deploymentStatus is what we learned about one deployment listed in a CSV's
+// install strategy, along with its pods.
+type deploymentStatus struct {
+	Name       string
+	Deployment *appsv1.Deployment
+	Pods       []corev1.Pod
+	Err        error
+}
+
+// inspectCSVDeployments fetches the deployments named in the CSV's install
+// strategy and the pods selected by each of them.
+func inspectCSVDeployments(ctx context.Context, k8sClient kubernetes.Interface, csv *v1alpha1.ClusterServiceVersion) []deploymentStatus {
+	var statuses []deploymentStatus
+	for _, spec := range csv.Spec.InstallStrategy.StrategySpec.DeploymentSpecs {
+		status := deploymentStatus{Name: spec.Name}
+
+		deployment, err := k8sClient.AppsV1().Deployments(csv.Namespace).Get(ctx, spec.Name, metav1.GetOptions{})
+		if err != nil {
+			status.Err = err
+			statuses = append(statuses, status)
+			continue
+		}
+		status.Deployment = deployment
+		status.Pods, status.Err = deploymentPods(ctx, k8sClient, deployment)
+
+		statuses = append(statuses, status)
+	}
+	return statuses
+}
+
+// deploymentPods lists the pods selected by a deployment.
+func deploymentPods(ctx context.Context, k8sClient kubernetes.Interface, deployment *appsv1.Deployment) ([]corev1.Pod, error) {
+	selector, err := metav1.LabelSelectorAsSelector(deployment.Spec.Selector)
+	if err != nil {
+		return nil, err
+	}
+	pods, err := k8sClient.CoreV1().Pods(deployment.Namespace).List(ctx, metav1.ListOptions{LabelSelector: selector.String()})
+	if err != nil {
+		return nil, err
+	}
+	return pods.Items, nil
+}
+
+// deploymentFindings turns the observed deployment and pod state into
+// findings. The subject names what the deployments belong to.
+func deploymentFindings(subject string, statuses []deploymentStatus) []finding {
+	var findings []finding
+	for _, status := range statuses {
+		if status.Deployment == nil {
+			if apierrors.IsNotFound(status.Err) {
+				findings = append(findings, finding{
+					Severity: severityCritical,
+					Title:    fmt.Sprintf("Deployment '%s' of %s does not exist", status.Name, subject),
+					Evidence: []string{"OLM has not created it yet, or it was deleted"},
+				})
+			} else if status.Err != nil {
+				findings = append(findings, finding{
+					Severity: severityWarning,
+					Title:    fmt.Sprintf("Could not read Deployment '%s'", status.Name),
+					Evidence: []string{status.Err.Error()},
+				})
+			}
+			continue
+		}
+
+		deployment := status.Deployment
+		desired := int32(1)
+		if deployment.Spec.Replicas != nil {
+			desired = *deployment.Spec.Replicas
+		}
+		if deployment.Status.AvailableReplicas < desired {
+			evidence := []string{fmt.Sprintf("%d/%d replicas available", deployment.Status.AvailableReplicas, desired)}
+			for _, cond := range deployment.Status.Conditions {
+				if cond.Status != corev1.ConditionTrue {
+					evidence = append(evidence, fmt.Sprintf("condition %s=%s: %s %s", cond.Type, cond.Status, cond.Reason, cond.Message))
+				}
+			}
+			findings = append(findings, finding{
+				Severity: severityWarning,
+				Title:    fmt.Sprintf("Deployment '%s' is not fully available", deployment.Name),
+				Evidence: evidence,
+			})
+		}
+
+		for _, pod := range status.Pods {
+			findings = append(findings, podFindings(pod)...)
+		}
+	}
+	return findings
+}
+
+// podFindings reports containers that cannot start, are not ready or have
+// restarted.
+func podFindings(pod corev1.Pod) []finding {
+	var findings []finding
+
+	if pod.Status.Phase == corev1.PodPending {
+		for _, cond := range pod.Status.Conditions {
+			if cond.Type == corev1.PodScheduled && cond.Status == corev1.ConditionFalse {
+				findings = append(findings, finding{
+					Severity: severityCritical,
+					Title:    fmt.Sprintf("Pod '%s' cannot be scheduled", pod.Name),
+					Evidence: []string{fmt.Sprintf("%s: %s", cond.Reason, cond.Message)},
+				})
+			}
+		}
+	}
+
+	statuses := append(append([]corev1.ContainerStatus{}, pod.Status.InitContainerStatuses...), pod.Status.ContainerStatuses...)
+	for _, cs := range statuses {
+		if waiting := cs.State.Waiting; waiting != nil && fatalWaitingReasons[waiting.Reason] {
+			evidence := []string{fmt.Sprintf("container '%s' waiting: %s", cs.Name, waiting.Reason)}
+			if waiting.Message != "" {
+				evidence = append(evidence, waiting.Message)
+			}
+			evidence = append(evidence, fmt.Sprintf("image: %s", cs.Image))
+			if last := cs.LastTerminationState.Terminated; last != nil {
+				evidence = append(evidence, fmt.Sprintf("last exit code %d (%s) at %s", last.ExitCode, last.Reason, formatTime(&last.FinishedAt)))
+			}
+			findings = append(findings, finding{
+				Severity: severityCritical,
+				Title:    fmt.Sprintf("Pod '%s' container '%s' is in %s", pod.Name, cs.Name, waiting.Reason),
+				Evidence: evidence,
+			})
+			continue
+		}
+
+		if !cs.Ready && pod.Status.Phase == corev1.PodRunning {
+			findings = append(findings, finding{
+				Severity: severityWarning,
+				Title:    fmt.Sprintf("Pod '%s' container '%s' is not ready", pod.Name, cs.Name),
+				Evidence: []string{fmt.Sprintf("restarts: %d", cs.RestartCount)},
+			})
+			continue
+		}
+
+		if cs.RestartCount > 0 {
+			sev, title := severityInfo, "has restarted"
+			evidence := []string{fmt.Sprintf("restarts: %d", cs.RestartCount)}
+			if last := cs.LastTerminationState.Terminated; last != nil {
+				evidence = append(evidence, fmt.Sprintf("last exit code %d (%s) at %s", last.ExitCode, last.Reason, formatTime(&last.FinishedAt)))
+				if time.Since(last.FinishedAt.Time) < recentRestartWindow {
+					sev, title = severityWarning, "restarted recently"
+				}
+			}
+			findings = append(findings, finding{
+				Severity: sev,
+				Title:    fmt.Sprintf("Pod '%s' container '%s' %s", pod.Name, cs.Name, title),
+				Evidence: evidence,
+			})
+		}
+	}
+
+	return findings
+}
+
+// summarizeDeployments returns a one-line availability summary per
+// deployment, e.g. "my-operator (1/1 available, pods: 1)".
+func summarizeDeployments(statuses []deploymentStatus) string {
+	var parts []string
+	for _, status := range statuses {
+		if status.Deployment == nil {
+			parts = append(parts, fmt.Sprintf("%s (missing)", status.Name))
+			continue
+		}
+		desired := int32(1)
+		if status.Deployment.Spec.Replicas != nil {
+			desired = *status.Deployment.Spec.Replicas
+		}
+		parts = append(parts, fmt.Sprintf("%s (%d/%d available, pods: %d)",
+			status.Name, status.Deployment.Status.AvailableReplicas, desired, len(status.Pods)))
+	}
+	sort.Strings(parts)
+	return strings.Join(parts, ", ")
+}
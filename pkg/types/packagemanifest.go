@@ -0,0 +1,76 @@
+package types
+
+import (
+	"github.com/operator-framework/api/pkg/operators/v1alpha1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// PackageManifest mirrors the subset of the packages.operators.coreos.com/v1
+// PackageManifest served by OLM's package server that the tools rely on. The
+// upstream type lives in the OLM repository rather than operator-framework/api,
+// so it is declared here and decoded from JSON.
+type PackageManifest struct {
+	metav1.TypeMeta   `json:",inline"`
+	metav1.ObjectMeta `json:"metadata"`
+
+	Status PackageManifestStatus `json:"status"`
+}
+
+type PackageManifestList struct {
+	metav1.TypeMeta `json:",inline"`
+	metav1.ListMeta `json:"metadata"`
+
+	Items []PackageManifest `json:"items"`
+}
+
+type PackageManifestStatus struct {
+	CatalogSource          string           `json:"catalogSource"`
+	CatalogSourceNamespace string           `json:"catalogSourceNamespace"`
+	PackageName            string           `json:"packageName"`
+	DefaultChannelName     string           `json:"defaultChannel"`
+	Channels               []PackageChannel `json:"channels"`
+	Deprecation            *Deprecation     `json:"deprecation,omitempty"`
+}
+
+type PackageChannel struct {
+	Name           string         `json:"name"`
+	CurrentCSV     string         `json:"currentCSV"`
+	CurrentCSVDesc CSVDescription `json:"currentCSVDesc,omitempty"`
+	Entries        []ChannelEntry `json:"entries,omitempty"`
+	Deprecation    *Deprecation   `json:"deprecation,omitempty"`
+}
+
+// ChannelEntry is a bundle in a channel. Entries are ordered from the channel
+// head to the oldest bundle.
+type ChannelEntry struct {
+	Name        string       `json:"name"`
+	Version     string       `json:"version,omitempty"`
+	Deprecation *Deprecation `json:"deprecation,omitempty"`
+}
+
+type CSVDescription struct {
+	DisplayName               string                             `json:"displayName,omitempty"`
+	Version                   string                             `json:"version,omitempty"`
+	Annotations               map[string]string                  `json:"annotations,omitempty"`
+	InstallModes              []v1alpha1.InstallMode             `json:"installModes,omitempty"`
+	CustomResourceDefinitions v1alpha1.CustomResourceDefinitions `json:"customresourcedefinitions,omitempty"`
+	APIServiceDefinitions     v1alpha1.APIServiceDefinitions     `json:"apiservicedefinitions,omitempty"`
+	NativeAPIs                []metav1.GroupVersionKind          `json:"nativeApis,omitempty"`
+	MinKubeVersion            string                             `json:"minKubeVersion,omitempty"`
+	RelatedImages             []string                           `json:"relatedImages,omitempty"`
+}
+
+type Deprecation struct {
+	Message string `json:"message"`
+}
+
+// GetChannel returns the named channel, or nil if the package has no such
+// channel.
+func (p *PackageManifest) GetChannel(name string) *PackageChannel {
+	for i := range p.Status.Channels {
+		if p.Status.Channels[i].Name == name {
+			return &p.Status.Channels[i]
+		}
+	}
+	return nil
+}
@@ -2,8 +2,11 @@ package types
 
 import (
 	"context"
+	"time"
 
+	operatorsv1 "github.com/operator-framework/api/pkg/operators/v1"
 	"github.com/operator-framework/api/pkg/operators/v1alpha1"
+	operatorsv2 "github.com/operator-framework/api/pkg/operators/v2"
 	"k8s.io/client-go/kubernetes"
 	"k8s.io/client-go/rest"
 )
@@ -16,6 +19,14 @@ type MCPServer struct {
 	ReadOnly   bool
 	Kubeconfig string
 	Toolsets   []string
+	// CatalogDir is the File-Based Catalog root the catalog tools analyze.
+	CatalogDir string
+	// CapturedAt is when the offline state being served was captured; it is
+	// zero when serving a live cluster.
+	CapturedAt time.Time
+	// CatalogOnly serves File-Based Catalogs without a cluster; only the
+	// CatalogOnlyTools are enabled.
+	CatalogOnly bool
 }
 
 type OLMClientInterface interface {
@@ -27,6 +38,19 @@ type OLMClientInterface interface {
 	GetCatalogSource(ctx context.Context, namespace, name string) (*v1alpha1.CatalogSource, error)
 	ListInstallPlans(ctx context.Context, namespace string) (*v1alpha1.InstallPlanList, error)
 	GetInstallPlan(ctx context.Context, namespace, name string) (*v1alpha1.InstallPlan, error)
+	ListPackageManifests(ctx context.Context, namespace string) (*PackageManifestList, error)
+	ListOperatorGroups(ctx context.Context, namespace string) (*operatorsv1.OperatorGroupList, error)
+	ListOperatorConditions(ctx context.Context, namespace string) (*operatorsv2.OperatorConditionList, error)
+
+	// Write operations. When dryRun is true the request is sent with
+	// server-side dry-run and nothing is persisted.
+	CreateSubscription(ctx context.Context, subscription *v1alpha1.Subscription, dryRun bool) (*v1alpha1.Subscription, error)
+	UpdateSubscription(ctx context.Context, subscription *v1alpha1.Subscription, dryRun bool) (*v1alpha1.Subscription, error)
+	DeleteSubscription(ctx context.Context, namespace, name string, dryRun bool) error
+	UpdateInstallPlan(ctx context.Context, installPlan *v1alpha1.InstallPlan, dryRun bool) (*v1alpha1.InstallPlan, error)
+	DeleteClusterServiceVersion(ctx context.Context, namespace, name string, dryRun bool) error
+	DeleteInstallPlan(ctx context.Context, namespace, name string, dryRun bool) error
+	DeleteOperatorGroup(ctx context.Context, namespace, name string, dryRun bool) error
 }
 
 // MCP Protocol types
@@ -73,21 +97,84 @@ type ToolConfig struct {
 	Enabled     bool
 }
 
+// CatalogOnlyTools are the tools that read only local File-Based Catalogs and
+// so work without a cluster.
+var CatalogOnlyTools = []string{"list_fbc_packages", "get_fbc_upgrade_graph", "validate_fbc"}
+
 var DefaultToolsets = map[string][]ToolConfig{
 	"csv": {
 		{Name: "list_csvs", Description: "List ClusterServiceVersions", Enabled: true},
 		{Name: "get_csv", Description: "Get ClusterServiceVersion details", Enabled: true},
+		{Name: "list_api_providers", Description: "Map APIs to providing and requiring operators", Enabled: true},
+		{Name: "get_operator_permissions", Description: "Summarize operator RBAC and drift", Enabled: true},
+		{Name: "diff_csvs", Description: "Compare two CSVs semantically", Enabled: true},
+		{Name: "get_dependency_graph", Description: "Render the operator dependency graph", Enabled: true},
+		{Name: "trace_csv", Description: "Trace a CSV's provenance", Enabled: true},
 	},
 	"subscription": {
 		{Name: "list_subscriptions", Description: "List Subscriptions", Enabled: true},
 		{Name: "get_subscription", Description: "Get Subscription details", Enabled: true},
+		{Name: "update_subscription", Description: "Update a Subscription's channel or approval strategy", Enabled: true},
+		{Name: "rollback_operator", Description: "Roll an operator back to its previous CSV", Enabled: true},
+		{Name: "list_pending_upgrades", Description: "List pending operator upgrades", Enabled: true},
+		{Name: "list_deprecations", Description: "List deprecated operators and replacement channels", Enabled: true},
 	},
 	"catalog": {
 		{Name: "list_catalog_sources", Description: "List CatalogSources", Enabled: true},
 		{Name: "get_catalog_source", Description: "Get CatalogSource details", Enabled: true},
+		{Name: "list_fbc_packages", Description: "List File-Based Catalog packages", Enabled: true},
+		{Name: "get_fbc_upgrade_graph", Description: "Render File-Based Catalog upgrade graphs", Enabled: true},
+		{Name: "validate_fbc", Description: "Validate File-Based Catalogs", Enabled: true},
 	},
 	"installplan": {
 		{Name: "list_install_plans", Description: "List InstallPlans", Enabled: true},
 		{Name: "get_install_plan", Description: "Get InstallPlan details", Enabled: true},
+		{Name: "approve_install_plan", Description: "Approve a pending InstallPlan", Enabled: true},
 	},
+	"diagnostics": {
+		{Name: "diagnose_subscription", Description: "Diagnose a Subscription's install chain", Enabled: true},
+		{Name: "check_csv_health", Description: "Check ClusterServiceVersion health", Enabled: true},
+		{Name: "check_catalog_health", Description: "Check CatalogSource health", Enabled: true},
+		{Name: "get_olm_logs", Description: "Read OLM component logs for a resource", Enabled: true},
+		{Name: "get_events", Description: "List events for an OLM resource", Enabled: true},
+		{Name: "explain_resolution_failure", Description: "Explain Subscription resolution failures", Enabled: true},
+		{Name: "summarize_olm", Description: "Summarize OLM resources cluster-wide", Enabled: true},
+		{Name: "find_orphans", Description: "Find stale and orphaned OLM objects", Enabled: true},
+		{Name: "cleanup_orphans", Description: "Delete selected orphaned OLM objects", Enabled: true},
+		{Name: "validate_operator_groups", Description: "Validate OperatorGroups and install modes", Enabled: true},
+		{Name: "collect_olm_state", Description: "Collect OLM state into a tarball", Enabled: true},
+		{Name: "get_install_timeline", Description: "Reconstruct an install timeline", Enabled: true},
+		{Name: "diagnose_bundle_unpack", Description: "Diagnose bundle unpack failures", Enabled: true},
+	},
+}
+
+// Now returns the time the served state is observed at: the capture time
+// offline and the wall clock otherwise.
+func (s *MCPServer) Now() time.Time {
+	if !s.CapturedAt.IsZero() {
+		return s.CapturedAt
+	}
+	return time.Now()
+}
+
+// EnabledTools returns the names of the tools in the server's toolsets.
+func (s *MCPServer) EnabledTools() map[string]bool {
+	enabled := make(map[string]bool)
+	for _, toolset := range s.Toolsets {
+		for _, tool := range DefaultToolsets[toolset] {
+			if tool.Enabled {
+				enabled[tool.Name] = true
+			}
+		}
+	}
+	if s.CatalogOnly {
+		catalogOnly := make(map[string]bool)
+		for _, name := range CatalogOnlyTools {
+			if enabled[name] {
+				catalogOnly[name] = true
+			}
+		}
+		enabled = catalogOnly
+	}
+	return enabled
 }